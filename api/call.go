@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// API errors
+var (
+	ErrNotReady     = errors.New("no game is running")
+	ErrTimeout      = errors.New("game did not respond in time")
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("missing or invalid API token")
+	ErrNotFound     = errors.New("not found")
+	ErrNoFloorEvent = errors.New("no floor event is active")
+)
+
+// Call is a request to run against the game while it is locked, so the API
+// never touches the game state concurrently. Whoever owns the game passes it
+// to Run.
+type Call struct {
+	fn    func(gs *models.GameState, e *engine.GameEngine) (interface{}, error)
+	reply chan callResult
+}
+
+// callResult is what a Call produced.
+type callResult struct {
+	value interface{}
+	err   error
+}
+
+// newCall wraps fn in a Call with room for its reply, so Run never blocks
+// even if the requester has given up.
+func newCall(fn func(gs *models.GameState, e *engine.GameEngine) (interface{}, error)) Call {
+	return Call{fn: fn, reply: make(chan callResult, 1)}
+}
+
+// Run executes the call and delivers its result. gs and e may be nil while
+// no game is loaded.
+func (c Call) Run(gs *models.GameState, e *engine.GameEngine) {
+	if gs == nil || e == nil {
+		c.reply <- callResult{err: ErrNotReady}
+		return
+	}
+	value, err := c.fn(gs, e)
+	c.reply <- callResult{value: value, err: err}
+}
@@ -0,0 +1,2 @@
+// Package api serves a local HTTP/JSON API for the running game.
+package api
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// Server limits
+const (
+	callTimeout     = 5 * time.Second
+	maxRequestBytes = 4 << 10
+)
+
+// Server serves the game API on a localhost address. Reads are open to
+// local clients; writes need the token as "Authorization: Bearer <token>".
+type Server struct {
+	addr  string
+	token string
+	send  func(Call)
+	http  *http.Server
+}
+
+// NewServer creates a server on addr. send runs a Call against the game,
+// typically with the game loop's lock held.
+func NewServer(addr, token string, send func(Call)) *Server {
+	s := &Server{addr: addr, token: token, send: send}
+	s.http = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Handler returns the API's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tower", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return towerStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/spells", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return spellsStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/rituals", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return ritualsStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/rotation", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return rotationStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/synergies", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return synergiesStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/stats", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return statsStatus(gs, e), nil
+	}))
+	mux.HandleFunc("GET /api/presets", s.read(func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		return presetsStatus(gs), nil
+	}))
+	mux.HandleFunc("POST /api/cast", s.write(s.handleCast))
+	mux.HandleFunc("POST /api/autocast", s.write(s.handleAutoCast))
+	mux.HandleFunc("POST /api/presets/apply", s.write(s.handleApplyPreset))
+	mux.HandleFunc("POST /api/floor-event", s.write(s.handleFloorEvent))
+	return localOnly(mux)
+}
+
+// Start listens on the server's address and serves in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.addr = listener.Addr().String()
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			utils.Log(utils.LogError, "api server stopped", "err", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Shutdown stops the server, waiting for requests in flight.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
+
+// call runs fn on the game loop and waits for its result.
+func (s *Server) call(ctx context.Context, fn func(gs *models.GameState, e *engine.GameEngine) (interface{}, error)) (interface{}, error) {
+	c := newCall(fn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	go s.send(c)
+	select {
+	case result := <-c.reply:
+		return result.value, result.err
+	case <-ctx.Done():
+		return nil, ErrTimeout
+	}
+}
+
+// read serves a snapshot built on the game loop.
+func (s *Server) read(fn func(gs *models.GameState, e *engine.GameEngine) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		value, err := s.call(r.Context(), fn)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, value)
+	}
+}
+
+// write checks the token and decodes the JSON body before running handle.
+func (s *Server) write(handle func(r *http.Request, body writeRequest) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			writeError(w, ErrUnauthorized)
+			return
+		}
+		var body writeRequest
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			writeError(w, fmt.Errorf("%w: %v", ErrBadRequest, err))
+			return
+		}
+		value, err := handle(r, body)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, value)
+	}
+}
+
+// authorized checks the bearer token.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// writeRequest is the body of a write endpoint; each uses some fields.
+type writeRequest struct {
+	SpellID string `json:"spell_id"`
+	Name    string `json:"name"`
+	Choice  string `json:"choice"`
+}
+
+// handleCast casts a spell: {"spell_id": "..."}.
+func (s *Server) handleCast(r *http.Request, body writeRequest) (interface{}, error) {
+	if body.SpellID == "" {
+		return nil, fmt.Errorf("%w: spell_id is required", ErrBadRequest)
+	}
+	return s.call(r.Context(), func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		spell := gs.GetSpellByID(body.SpellID)
+		if spell == nil {
+			return nil, engine.ErrSpellNotFound
+		}
+		if err := e.CastSpell(gs, spell, true); err != nil {
+			return nil, err
+		}
+		return spellStatus(gs, e, spell), nil
+	})
+}
+
+// handleAutoCast toggles a spell's auto-cast slot: {"spell_id": "..."}.
+func (s *Server) handleAutoCast(r *http.Request, body writeRequest) (interface{}, error) {
+	if body.SpellID == "" {
+		return nil, fmt.Errorf("%w: spell_id is required", ErrBadRequest)
+	}
+	return s.call(r.Context(), func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		if _, err := e.ToggleSpellAutoCast(gs, body.SpellID); err != nil {
+			return nil, err
+		}
+		return spellStatus(gs, e, gs.GetSpellByID(body.SpellID)), nil
+	})
+}
+
+// handleApplyPreset applies a loadout preset: {"name": "..."}.
+func (s *Server) handleApplyPreset(r *http.Request, body writeRequest) (interface{}, error) {
+	if body.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrBadRequest)
+	}
+	return s.call(r.Context(), func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		if !gs.ApplyLoadoutPreset(body.Name) {
+			return nil, fmt.Errorf("%w: no preset named %q", ErrNotFound, body.Name)
+		}
+		return towerStatus(gs, e), nil
+	})
+}
+
+// handleFloorEvent answers the active floor event: {"choice": "mana_gen"}.
+func (s *Server) handleFloorEvent(r *http.Request, body writeRequest) (interface{}, error) {
+	choice := models.FloorEventChoice(body.Choice)
+	if _, ok := models.FloorEventChoiceDisplayNames[choice]; !ok {
+		return nil, fmt.Errorf("%w: choice must be one of %s", ErrBadRequest, strings.Join(floorEventChoices(), ", "))
+	}
+	return s.call(r.Context(), func(gs *models.GameState, e *engine.GameEngine) (interface{}, error) {
+		if !e.ChooseFloorEvent(gs, choice) {
+			return nil, ErrNoFloorEvent
+		}
+		return towerStatus(gs, e), nil
+	})
+}
+
+// localOnly rejects requests whose Host isn't a loopback name, so web pages
+// can't reach the API through DNS rebinding.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		ip := net.ParseIP(strings.Trim(host, "[]"))
+		if host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			writeJSON(w, http.StatusForbidden, errorResponse{Error: "requests must be addressed to localhost"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorResponse is the body of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError responds with err and a matching status code.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrNotFound), errors.Is(err, engine.ErrSpellNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNoFloorEvent), errors.Is(err, engine.ErrSpellOnCooldown),
+		errors.Is(err, engine.ErrInsufficientMana), errors.Is(err, engine.ErrNoAutoCastSlots),
+		errors.Is(err, engine.ErrSpellDisabled), errors.Is(err, engine.ErrManualCastingDisabled):
+		status = http.StatusConflict
+	case errors.Is(err, ErrNotReady), errors.Is(err, ErrTimeout):
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON responds with value as JSON.
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		utils.Log(utils.LogWarn, "api response failed", "err", err)
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"time"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// TowerStatus is the response of GET /api/tower.
+type TowerStatus struct {
+	Floor           int               `json:"floor"`
+	MaxFloor        int               `json:"max_floor"`
+	Mana            float64           `json:"mana"`
+	MaxMana         float64           `json:"max_mana"`
+	ManaPerSecond   float64           `json:"mana_per_second"`
+	ManaToNextFloor float64           `json:"mana_to_next_floor"`
+	FloorProgress   float64           `json:"floor_progress"` // 0-1
+	SigilCharge     float64           `json:"sigil_charge"`
+	SigilRequired   float64           `json:"sigil_required"`
+	Era             int               `json:"era"`
+	Ascensions      int               `json:"ascensions"`
+	CanPrestige     bool              `json:"can_prestige"`
+	AutoCast        bool              `json:"auto_cast"`
+	FloorEvent      *FloorEventStatus `json:"floor_event,omitempty"`
+	FloorBuff       *FloorBuffStatus  `json:"floor_buff,omitempty"`
+	Boss            *BossStatus       `json:"boss,omitempty"`
+}
+
+// FloorEventStatus is a floor event waiting for a choice.
+type FloorEventStatus struct {
+	Floor       int      `json:"floor"`
+	ExpiresInMs int64    `json:"expires_in_ms"`
+	Choices     []string `json:"choices"`
+}
+
+// FloorBuffStatus is the active floor event bonus.
+type FloorBuffStatus struct {
+	Choice         string `json:"choice"`
+	Name           string `json:"name"`
+	ExpiresAtFloor int    `json:"expires_at_floor"`
+}
+
+// BossStatus is a boss fight in progress.
+type BossStatus struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Floor         int     `json:"floor"`
+	HP            float64 `json:"hp"`
+	MaxHP         float64 `json:"max_hp"`
+	Shield        float64 `json:"shield"`
+	ShieldElement string  `json:"shield_element,omitempty"`
+	RemainingMs   int64   `json:"remaining_ms"`
+	Enraged       bool    `json:"enraged"`
+}
+
+// SpellStatus is one entry of GET /api/spells.
+type SpellStatus struct {
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	Element             string  `json:"element"`
+	Level               int     `json:"level"`
+	Damage              float64 `json:"damage"`
+	ManaCost            float64 `json:"mana_cost"`
+	CooldownMs          int64   `json:"cooldown_ms"`
+	CooldownRemainingMs int64   `json:"cooldown_remaining_ms"`
+	Ready               bool    `json:"ready"`
+	AutoCast            bool    `json:"auto_cast"`
+	CastCount           int     `json:"cast_count"`
+}
+
+// RitualsStatus is the response of GET /api/rituals.
+type RitualsStatus struct {
+	MaxSlots int            `json:"max_slots"`
+	Rituals  []RitualStatus `json:"rituals"`
+}
+
+// RitualStatus is one ritual.
+type RitualStatus struct {
+	ID                  string                `json:"id"`
+	Name                string                `json:"name"`
+	SpellIDs            []string              `json:"spell_ids"`
+	Active              bool                  `json:"active"`
+	CooldownRemainingMs int64                 `json:"cooldown_remaining_ms"`
+	Effects             []models.RitualEffect `json:"effects"`
+}
+
+// RotationStatus is the response of GET /api/rotation.
+type RotationStatus struct {
+	Rotation *models.SpellRotation `json:"rotation"`
+	Next     *NextCastStatus       `json:"next,omitempty"`
+}
+
+// NextCastStatus predicts the rotation's next cast.
+type NextCastStatus struct {
+	SpellID string `json:"spell_id,omitempty"`
+	WaitMs  int64  `json:"wait_ms"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// SynergiesStatus is the response of GET /api/synergies.
+type SynergiesStatus struct {
+	Element *ElementSynergyStatus  `json:"element,omitempty"`
+	Rituals []models.RitualSynergy `json:"rituals"`
+}
+
+// ElementSynergyStatus is the synergy from casting one element repeatedly.
+type ElementSynergyStatus struct {
+	Element     string `json:"element"`
+	ExpiresInMs int64  `json:"expires_in_ms"`
+}
+
+// StatsStatus is the response of GET /api/stats.
+type StatsStatus struct {
+	Era      *models.EraStats `json:"era"`
+	Lifetime *models.EraStats `json:"lifetime"`
+}
+
+// PresetStatus is one entry of GET /api/presets.
+type PresetStatus struct {
+	Name     string    `json:"name"`
+	AutoCast bool      `json:"auto_cast"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// towerStatus snapshots the tower.
+func towerStatus(gs *models.GameState, e *engine.GameEngine) TowerStatus {
+	progress := e.GetProgressStats(gs)
+	status := TowerStatus{
+		Floor:           gs.Tower.CurrentFloor,
+		MaxFloor:        gs.Tower.MaxFloorReached,
+		Mana:            gs.Tower.CurrentMana,
+		MaxMana:         gs.Tower.MaxMana,
+		ManaPerSecond:   progress.ManaPerSecond,
+		ManaToNextFloor: progress.ManaToNextFloor,
+		FloorProgress:   progress.FloorProgress,
+		SigilCharge:     gs.Tower.SigilCharge,
+		SigilRequired:   gs.Tower.SigilRequired,
+		Era:             gs.PrestigeData.CurrentEra,
+		Ascensions:      gs.PrestigeData.TotalAscensions,
+		CanPrestige:     progress.CanPrestige,
+		AutoCast:        gs.Session.AutoCastEnabled,
+	}
+	if event := gs.Session.ActiveFloorEvent; event != nil {
+		status.FloorEvent = &FloorEventStatus{
+			Floor:       event.Floor,
+			ExpiresInMs: max(event.ExpiresAtMs-e.Now().UnixMilli(), 0),
+			Choices:     floorEventChoices(),
+		}
+	}
+	if buff := gs.Session.ActiveFloorBuff; buff != nil {
+		status.FloorBuff = &FloorBuffStatus{
+			Choice:         string(buff.Choice),
+			Name:           models.FloorEventChoiceDisplayNames[buff.Choice],
+			ExpiresAtFloor: buff.ExpiresAtFloor,
+		}
+	}
+	if fight := gs.Boss; fight != nil {
+		status.Boss = &BossStatus{
+			ID:            fight.BossID,
+			Name:          fight.BossID,
+			Floor:         fight.Floor,
+			HP:            fight.HP,
+			MaxHP:         fight.MaxHP,
+			Shield:        fight.Shield,
+			ShieldElement: string(fight.ShieldElement),
+			RemainingMs:   fight.RemainingMs(),
+			Enraged:       fight.Enraged,
+		}
+		if boss := game.GetBoss(fight.BossID); boss != nil {
+			status.Boss.Name = boss.Name
+		}
+	}
+	return status
+}
+
+// floorEventChoices lists the floor event choices in menu order.
+func floorEventChoices() []string {
+	return []string{
+		string(models.FloorEventChoiceManaGen),
+		string(models.FloorEventChoiceSigilChargeRate),
+		string(models.FloorEventChoiceCooldownReduction),
+	}
+}
+
+// spellStatus snapshots a spell.
+func spellStatus(gs *models.GameState, e *engine.GameEngine, spell *models.Spell) SpellStatus {
+	stats := e.GetSpellEffectiveStats(gs, spell)
+	remaining := e.GetSpellCooldownRemaining(spell)
+	return SpellStatus{
+		ID:                  spell.ID,
+		Name:                spell.Name,
+		Element:             string(spell.Element),
+		Level:               spell.Level,
+		Damage:              stats.Damage,
+		ManaCost:            e.CalculateEffectiveSpellManaCost(gs, spell, true),
+		CooldownMs:          stats.CooldownMs,
+		CooldownRemainingMs: remaining,
+		Ready:               remaining == 0,
+		AutoCast:            gs.IsSpellInAutoCast(spell.ID),
+		CastCount:           spell.CastCount,
+	}
+}
+
+// spellsStatus snapshots every spell.
+func spellsStatus(gs *models.GameState, e *engine.GameEngine) []SpellStatus {
+	spells := make([]SpellStatus, 0, len(gs.Spells))
+	for _, spell := range gs.Spells {
+		spells = append(spells, spellStatus(gs, e, spell))
+	}
+	return spells
+}
+
+// ritualsStatus snapshots the rituals.
+func ritualsStatus(gs *models.GameState, e *engine.GameEngine) RitualsStatus {
+	status := RitualsStatus{MaxSlots: e.GetMaxRitualSlots(gs), Rituals: []RitualStatus{}}
+	for _, ritual := range gs.Rituals {
+		status.Rituals = append(status.Rituals, RitualStatus{
+			ID:                  ritual.ID,
+			Name:                ritual.Name,
+			SpellIDs:            append([]string(nil), ritual.SpellIDs...),
+			Active:              ritual.IsActive,
+			CooldownRemainingMs: ritual.CooldownRemaining,
+			Effects:             append([]models.RitualEffect(nil), ritual.Effects...),
+		})
+	}
+	return status
+}
+
+// rotationStatus snapshots the rotation and its next cast.
+func rotationStatus(gs *models.GameState, e *engine.GameEngine) RotationStatus {
+	rotation := gs.Session.Rotation
+	if rotation == nil {
+		return RotationStatus{}
+	}
+	snapshot := *rotation
+	snapshot.Spells = append([]models.RotationSpellConfig(nil), rotation.Spells...)
+	status := RotationStatus{Rotation: &snapshot}
+	if rotation.Enabled {
+		next := e.PreviewNextRotationCast(gs)
+		status.Next = &NextCastStatus{SpellID: next.SpellID, WaitMs: next.WaitMs, Reason: next.Reason}
+	}
+	return status
+}
+
+// synergiesStatus snapshots the element and ritual synergies.
+func synergiesStatus(gs *models.GameState, e *engine.GameEngine) SynergiesStatus {
+	status := SynergiesStatus{Rituals: append([]models.RitualSynergy{}, e.GetActiveSynergies(gs)...)}
+	if nowMs := e.Now().UnixMilli(); gs.HasActiveSynergyAt(nowMs) {
+		status.Element = &ElementSynergyStatus{Element: string(gs.GetActiveSynergyAt(nowMs)), ExpiresInMs: gs.GetSynergyTimeRemainingAt(nowMs)}
+	}
+	return status
+}
+
+// statsStatus snapshots the current era and lifetime statistics.
+func statsStatus(gs *models.GameState, e *engine.GameEngine) StatsStatus {
+	ledger := gs.EnsureLedger(e.Now())
+	era := *ledger.Current
+	era.DamageByElement = make(map[models.Element]float64, len(ledger.Current.DamageByElement))
+	for element, damage := range ledger.Current.DamageByElement {
+		era.DamageByElement[element] = damage
+	}
+	return StatsStatus{Era: &era, Lifetime: ledger.Lifetime()}
+}
+
+// presetsStatus lists the loadout presets.
+func presetsStatus(gs *models.GameState) []PresetStatus {
+	presets := make([]PresetStatus, 0, len(gs.Presets))
+	for _, preset := range gs.Presets {
+		presets = append(presets, PresetStatus{Name: preset.Name, AutoCast: preset.AutoCastEnabled, SavedAt: preset.SavedAt})
+	}
+	return presets
+}
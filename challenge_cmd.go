@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Ltorre/ManaTTY/daemon"
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// runChallenge runs "manatty challenge [restart] [flags]", which plays this
+// week's challenge in the challenge save slot. An unfinished run of the
+// week is resumed; a finished one, or "restart", starts a new attempt.
+func runChallenge(args []string) int {
+	restart := len(args) > 0 && args[0] == "restart"
+	if restart {
+		args = args[1:]
+	}
+	cfg, code, ok := loadCommandConfig("challenge", args)
+	if !ok {
+		return code
+	}
+
+	// The daemon saves the profile too, and the last write would win
+	if _, err := daemon.QueryStatus(socketPath(cfg)); err == nil {
+		fmt.Fprintln(os.Stderr, "The daemon is running your game; stop it with \"manatty daemon stop\" to play a challenge.")
+		return 1
+	}
+
+	challenge := game.WeeklyChallenge(time.Now())
+	fmt.Printf("Weekly challenge %s: reach floor %d as fast as you can\n", challenge.ID, challenge.TargetFloor)
+	for _, mod := range game.GetChallengeModifiers(challenge.Modifiers) {
+		fmt.Printf("  %s: %s\n", mod.Name, mod.Description)
+	}
+
+	nickname := cfg.Nickname
+	if nickname == "" {
+		nickname = promptNickname()
+	}
+
+	b := openBackend(cfg)
+	player, err := prepareChallenge(b, nickname, challenge, restart)
+	if err != nil {
+		utils.Error("Can't start the challenge: %v", err)
+		b.close()
+		return 1
+	}
+	if best := player.ChallengeResult(challenge.ID); best != nil {
+		fmt.Printf("Your best: %s (replay %.12s)\n", utils.FormatDuration(time.Duration(best.ElapsedMs)*time.Millisecond), best.ReplayHash)
+	}
+
+	fmt.Println("Loading...")
+	return b.loadGame(nickname, models.ChallengeSlot, true).play()
+}
+
+// prepareChallenge makes sure nickname's challenge slot holds a run of
+// challenge to play, starting a fresh one unless an unfinished run of it is
+// there. It returns the player, created if needed.
+func prepareChallenge(b *backend, nickname string, challenge models.Challenge, restart bool) (*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	player, err := b.playerStore.GetByUsername(ctx, nickname)
+	if err != nil {
+		player = models.NewPlayer(uuid.New().String(), nickname)
+		if err := b.playerStore.Create(ctx, player); err != nil {
+			return nil, err
+		}
+	}
+
+	save, err := b.saveStore.Load(ctx, player.UUID, models.ChallengeSlot)
+	if err == nil && !restart && save.Challenge != nil && save.Challenge.Challenge.ID == challenge.ID && !save.Challenge.Complete() {
+		utils.Info("Resuming your run: floor %d after %s", save.Tower.CurrentFloor,
+			utils.FormatDuration(time.Duration(save.Challenge.ElapsedMs)*time.Millisecond))
+		return player, nil
+	}
+
+	// Everyone starts from scratch with only the base spells
+	gs := engine.NewChallengeGame(player.UUID, challenge)
+	if err := b.saveStore.Save(ctx, gs); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
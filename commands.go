@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// runCommand runs a non-interactive subcommand and returns the exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "optimize":
+		return runOptimize(args)
+	case "config":
+		return runConfig(args)
+	case "daemon":
+		return runDaemon(args)
+	case "attach":
+		return runAttach(args)
+	case "lobby":
+		return runLobby(args)
+	case "challenge":
+		return runChallenge(args)
+	case "replay":
+		return runReplay(args)
+	case "help", "-h", "--help":
+		printUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		return 2
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: manatty [flags]")
+	fmt.Println("       manatty <command> [flags]")
+	fmt.Println()
+	fmt.Println("With no command, starts the game.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  optimize       Search for the best spell rotation for a save")
+	fmt.Println("  config print   Show the effective settings and where each came from")
+	fmt.Println("  daemon         Keep the game running without a terminal")
+	fmt.Println("  daemon status  Show what the running daemon is doing")
+	fmt.Println("  daemon stop    Save and stop the running daemon")
+	fmt.Println("  attach         Open the running daemon's game in this terminal")
+	fmt.Println("  lobby          Serve the game over SSH to the players in lobby_authorized_keys")
+	fmt.Println("  challenge      Play this week's seeded challenge (\"challenge restart\" starts over)")
+	fmt.Println("  replay <file>  Re-run a recorded replay and check it ends in the recorded state")
+	fmt.Println("  help           Show this help")
+	fmt.Println()
+	fmt.Println("Flags (override environment variables and ~/.manatty/config.toml):")
+	fs, _ := gameFlags()
+	fs.SetOutput(os.Stdout)
+	fs.PrintDefaults()
+}
+
+// gameFlags returns the flags for starting the game, which override the
+// config file and environment.
+func gameFlags() (*flag.FlagSet, *config.Flags) {
+	fs := flag.NewFlagSet("manatty", flag.ContinueOnError)
+	flags := config.BindFlags(fs)
+	fs.Usage = printUsage
+	return fs, flags
+}
+
+// runConfig runs "manatty config print [flags]", listing every setting with
+// its effective value and source. Game flags are accepted so their effect
+// can be checked.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: manatty config print [flags]")
+		return 2
+	}
+	fs, flags := gameFlags()
+	fs.Usage = func() {
+		fmt.Println("Usage: manatty config print [flags]")
+		fmt.Println()
+		fs.SetOutput(os.Stdout)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := config.Load(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring config file: %v\n", err)
+	}
+
+	fmt.Printf("Config file: %s\n\n", cfg.Path)
+	settings := cfg.Settings()
+	keyWidth, valueWidth := len("KEY"), len("VALUE")
+	for _, s := range settings {
+		keyWidth = max(keyWidth, len(s.Key))
+		valueWidth = max(valueWidth, len(s.Value))
+	}
+	fmt.Printf("%-*s  %-*s  %s\n", keyWidth, "KEY", valueWidth, "VALUE", "SOURCE")
+	for _, s := range settings {
+		fmt.Printf("%-*s  %-*s  %s\n", keyWidth, s.Key, valueWidth, s.Value, s.Source)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runOptimize loads a player's save, optimizes its rotation and prints a comparison.
+func runOptimize(args []string) int {
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	nickname := fs.String("nickname", "Wizard", "player nickname whose save to optimize")
+	objectiveName := fs.String("objective", "sigil", "what to maximize: sigil (sigil/s) or floors (floors/hr)")
+	apply := fs.Bool("apply", false, "save the optimized rotation to the player's latest save")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	objective, err := engine.ParseOptimizeObjective(*objectiveName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		utils.Warn("Ignoring config file: %v", err)
+	}
+	utils.SetLogLevel(utils.ParseLogLevel(cfg.LogLevel))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	saveStore, playerStore, db := openStorage(ctx, cfg)
+	if db != nil {
+		defer func() { _ = db.Disconnect(context.Background()) }()
+	}
+
+	player, err := playerStore.GetByUsername(ctx, *nickname)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "no player named %q: %v\n", *nickname, err)
+		return 1
+	}
+	gameState, err := saveStore.LoadLatest(ctx, player.UUID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load save for %q: %v\n", *nickname, err)
+		return 1
+	}
+
+	fmt.Printf("Optimizing rotation for %s (Floor %d)...\n\n", player.Username, gameState.Tower.CurrentFloor)
+	result := engine.NewGameEngine().OptimizeRotation(gameState, objective)
+	fmt.Print(engine.FormatOptimizeResult(gameState, result))
+
+	if !*apply {
+		fmt.Println("\nRun with -apply to save this rotation.")
+		return 0
+	}
+
+	gameState.Session.Rotation = result.Rotation
+	if err := saveStore.Save(ctx, gameState); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save: %v\n", err)
+		return 1
+	}
+	fmt.Println("\nOptimized rotation saved.")
+	return 0
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/joho/godotenv"
@@ -15,6 +16,13 @@ type Config struct {
 	// Storage mode: "mongodb" or "local"
 	StorageMode string
 
+	// DataDir holds local saves and player profiles
+	DataDir string
+
+	// Player to load ("" = ask at startup) and save slot (SlotLatest = most recent)
+	Nickname string
+	Slot     int
+
 	// Game settings
 	GameTickRate     int // Ticks per second
 	AutoSaveInterval int // Seconds between auto-saves
@@ -24,58 +32,239 @@ type Config struct {
 
 	// Development
 	Debug bool
+
+	// Record a replay of every game (challenge runs always record)
+	RecordReplays bool
+
+	// Local HTTP API ("" = off) and the token write requests must present
+	// ("" = generated, see the api package)
+	APIAddr  string
+	APIToken string
+
+	// Prometheus metrics endpoint ("" = off)
+	MetricsAddr string
+
+	// Address the SSH lobby listens on ("manatty lobby")
+	LobbyAddr string
+
+	// Hooks run on game events (config file only)
+	Hooks []Hook
+
+	// Interface (editable from the in-game Settings view)
+	Theme          string // Theme for profiles that haven't picked one ("" = default)
+	Symbols        string // SymbolsAuto, SymbolsEmoji or SymbolsASCII
+	Notifications  string // NotificationsAll, NotificationsNormal or NotificationsQuiet
+	ConfirmPrompts bool   // Ask before prestige and other irreversible actions
+
+	// Path is the config file settings were read from and are saved to.
+	Path string
+
+	// sources records where each non-default setting came from, by key.
+	sources map[string]Source
 }
 
+// Source is where a setting's effective value came from. Later sources take
+// precedence: flag > env > file > default.
+type Source string
+
+// Setting sources
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// SlotLatest loads the player's most recent save.
+const SlotLatest = -1
+
+// Symbol modes
+const (
+	SymbolsAuto  = "auto"  // Detect emoji support from the terminal
+	SymbolsEmoji = "emoji" // Always use emoji
+	SymbolsASCII = "ascii" // Always use ASCII fallbacks
+)
+
+// Notification verbosity levels
+const (
+	NotificationsAll    = "all"    // Every notification
+	NotificationsNormal = "normal" // Skip routine ones (upgrades, automation, skipped casts)
+	NotificationsQuiet  = "quiet"  // Only important ones (achievements, floor events), warnings and errors
+)
+
 // DefaultConfig returns configuration with default values.
 // When no .env file exists, the game runs in local storage mode.
 func DefaultConfig() *Config {
 	return &Config{
 		MongoDBURI:       "", // Empty = local storage mode
 		StorageMode:      "local",
+		DataDir:          DefaultDataDir(),
+		Slot:             SlotLatest,
 		GameTickRate:     10,
 		AutoSaveInterval: 30,
 		LogLevel:         "info",
 		Debug:            false,
+		LobbyAddr:        ":2222",
+		Symbols:          SymbolsAuto,
+		Notifications:    NotificationsAll,
+		ConfirmPrompts:   true,
+		sources:          map[string]Source{},
+	}
+}
+
+// DefaultDataDir returns the default directory for local data (~/.manatty).
+func DefaultDataDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".manatty"
 	}
+	return filepath.Join(homeDir, ".manatty")
 }
 
-// Load loads configuration from environment variables.
-func Load() (*Config, error) {
+// Source returns where the setting with the given config file key came from.
+func (c *Config) Source(key string) Source {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// Changed records that a setting was edited in game, so SaveSettings writes
+// it even if it came from the environment or a flag.
+func (c *Config) Changed(key string) {
+	c.setSource(key, SourceFile)
+}
+
+// Clone returns a copy of c that shares no state with it.
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.sources = make(map[string]Source, len(c.sources))
+	for key, source := range c.sources {
+		clone.sources[key] = source
+	}
+	return &clone
+}
+
+// setSource records where a setting came from.
+func (c *Config) setSource(key string, source Source) {
+	if c.sources == nil {
+		c.sources = map[string]Source{}
+	}
+	c.sources[key] = source
+}
+
+// Load loads configuration from the config file (~/.manatty/config.toml),
+// then environment variables, then command-line flags (nil for none), each
+// taking precedence over the last. The returned config is always usable: an
+// error reports a config file that could not be read, in which case its
+// settings are ignored. Flags are not validated; call Validate.
+func Load(flags *Flags) (*Config, error) {
 	// Load .env file if it exists (ignore error if not found)
 	_ = godotenv.Load()
 
 	cfg := DefaultConfig()
 
+	var fileErr error
+	if path, err := DefaultPath(); err == nil {
+		cfg.Path = path
+		fileErr = cfg.loadFile(path)
+	}
+
+	cfg.loadEnv()
+	flags.apply(cfg)
+
+	return cfg, fileErr
+}
+
+// loadEnv applies environment variables. Invalid values are ignored.
+func (c *Config) loadEnv() {
 	// MongoDB - if URI is set, use MongoDB storage mode
 	if uri := os.Getenv("MONGODB_URI"); uri != "" {
-		cfg.MongoDBURI = uri
-		cfg.StorageMode = "mongodb"
+		c.MongoDBURI = uri
+		c.StorageMode = "mongodb"
+		c.setSource(KeyMongoDBURI, SourceEnv)
+		c.setSource(KeyStorageMode, SourceEnv)
+	}
+
+	// Storage
+	if mode := os.Getenv("STORAGE_MODE"); mode == "local" || mode == "mongodb" {
+		c.StorageMode = mode
+		c.setSource(KeyStorageMode, SourceEnv)
+	}
+
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		c.DataDir = dir
+		c.setSource(KeyDataDir, SourceEnv)
+	}
+
+	// Player
+	if nickname := os.Getenv("PLAYER_NICKNAME"); nickname != "" {
+		c.Nickname = nickname
+		c.setSource(KeyNickname, SourceEnv)
+	}
+
+	if slot := os.Getenv("SAVE_SLOT"); slot != "" {
+		if s, err := strconv.Atoi(slot); err == nil && s >= 0 {
+			c.Slot = s
+			c.setSource(KeySlot, SourceEnv)
+		}
 	}
 
 	// Game settings
 	if rate := os.Getenv("GAME_TICK_RATE"); rate != "" {
-		if r, err := strconv.Atoi(rate); err == nil && r > 0 {
-			cfg.GameTickRate = r
+		if r, err := strconv.Atoi(rate); err == nil && r >= MinTickRate && r <= MaxTickRate {
+			c.GameTickRate = r
+			c.setSource(KeyTickRate, SourceEnv)
 		}
 	}
 
 	if interval := os.Getenv("AUTO_SAVE_INTERVAL"); interval != "" {
-		if i, err := strconv.Atoi(interval); err == nil && i > 0 {
-			cfg.AutoSaveInterval = i
+		if i, err := strconv.Atoi(interval); err == nil && i >= MinAutoSaveInterval {
+			c.AutoSaveInterval = i
+			c.setSource(KeyAutoSaveInterval, SourceEnv)
 		}
 	}
 
 	// Logging
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
-		cfg.LogLevel = level
+		c.LogLevel = level
+		c.setSource(KeyLogLevel, SourceEnv)
+	}
+
+	// HTTP API
+	if addr := os.Getenv("API_ADDR"); addr != "" {
+		c.APIAddr = addr
+		c.setSource(KeyAPIAddr, SourceEnv)
+	}
+
+	if token := os.Getenv("API_TOKEN"); token != "" {
+		c.APIToken = token
+		c.setSource(KeyAPIToken, SourceEnv)
+	}
+
+	// Metrics
+	if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+		c.MetricsAddr = addr
+		c.setSource(KeyMetricsAddr, SourceEnv)
+	}
+
+	// SSH lobby
+	if addr := os.Getenv("LOBBY_ADDR"); addr != "" {
+		c.LobbyAddr = addr
+		c.setSource(KeyLobbyAddr, SourceEnv)
 	}
 
 	// Debug
 	if debug := os.Getenv("DEBUG"); debug == "true" || debug == "1" {
-		cfg.Debug = true
+		c.Debug = true
+		c.setSource(KeyDebug, SourceEnv)
 	}
 
-	return cfg, nil
+	// Replays
+	if record := os.Getenv("RECORD_REPLAYS"); record == "true" || record == "1" {
+		c.RecordReplays = true
+		c.setSource(KeyRecordReplays, SourceEnv)
+	}
 }
 
 // GetEnv retrieves an environment variable with a default value.
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ErrInvalidSetting is returned for a config value outside its allowed range.
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// Setting limits
+const (
+	MinTickRate         = 1
+	MaxTickRate         = 60
+	MinAutoSaveInterval = 5
+)
+
+// Config file keys, also used to look up a setting's Source
+const (
+	KeyNickname         = "nickname"
+	KeySlot             = "slot"
+	KeyStorageMode      = "storage_mode"
+	KeyMongoDBURI       = "mongodb_uri"
+	KeyDataDir          = "data_dir"
+	KeyLogLevel         = "log_level"
+	KeyDebug            = "debug"
+	KeyRecordReplays    = "record_replays"
+	KeyAPIAddr          = "api_addr"
+	KeyAPIToken         = "api_token"
+	KeyMetricsAddr      = "metrics_addr"
+	KeyLobbyAddr        = "lobby_addr"
+	KeyHooks            = "hooks"
+	KeyTickRate         = "tick_rate"
+	KeyAutoSaveInterval = "autosave_interval"
+	KeyTheme            = "theme"
+	KeySymbols          = "symbols"
+	KeyNotifications    = "notifications"
+	KeyConfirmPrompts   = "confirm_prompts"
+)
+
+// fileSettings is the layout of the config file. Pointers tell unset keys
+// apart from zero values.
+type fileSettings struct {
+	Nickname         *string `toml:"nickname"`
+	Slot             *int    `toml:"slot"`
+	StorageMode      *string `toml:"storage_mode"`
+	MongoDBURI       *string `toml:"mongodb_uri"`
+	DataDir          *string `toml:"data_dir"`
+	LogLevel         *string `toml:"log_level"`
+	Debug            *bool   `toml:"debug"`
+	RecordReplays    *bool   `toml:"record_replays"`
+	APIAddr          *string `toml:"api_addr"`
+	APIToken         *string `toml:"api_token"`
+	MetricsAddr      *string `toml:"metrics_addr"`
+	LobbyAddr        *string `toml:"lobby_addr"`
+	GameTickRate     *int    `toml:"tick_rate"`
+	AutoSaveInterval *int    `toml:"autosave_interval"`
+	Theme            *string `toml:"theme"`
+	Symbols          *string `toml:"symbols"`
+	Notifications    *string `toml:"notifications"`
+	ConfirmPrompts   *bool   `toml:"confirm_prompts"`
+	Hooks            []Hook  `toml:"hooks"`
+}
+
+// DefaultPath returns the user's config file (~/.manatty/config.toml).
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".manatty", "config.toml"), nil
+}
+
+// loadFile applies the settings in a config file. A missing file is not an
+// error. Nothing is applied if any value is invalid.
+func (c *Config) loadFile(path string) error {
+	var file fileSettings
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	next := *c
+	var keys []string
+	setString := func(key string, dst *string, value *string) {
+		if value != nil {
+			*dst = *value
+			keys = append(keys, key)
+		}
+	}
+	setInt := func(key string, dst *int, value *int) {
+		if value != nil {
+			*dst = *value
+			keys = append(keys, key)
+		}
+	}
+	setBool := func(key string, dst *bool, value *bool) {
+		if value != nil {
+			*dst = *value
+			keys = append(keys, key)
+		}
+	}
+	setString(KeyNickname, &next.Nickname, file.Nickname)
+	setInt(KeySlot, &next.Slot, file.Slot)
+	setString(KeyStorageMode, &next.StorageMode, file.StorageMode)
+	setString(KeyMongoDBURI, &next.MongoDBURI, file.MongoDBURI)
+	setString(KeyDataDir, &next.DataDir, file.DataDir)
+	setString(KeyLogLevel, &next.LogLevel, file.LogLevel)
+	setBool(KeyDebug, &next.Debug, file.Debug)
+	setBool(KeyRecordReplays, &next.RecordReplays, file.RecordReplays)
+	setString(KeyAPIAddr, &next.APIAddr, file.APIAddr)
+	setString(KeyAPIToken, &next.APIToken, file.APIToken)
+	setString(KeyMetricsAddr, &next.MetricsAddr, file.MetricsAddr)
+	setString(KeyLobbyAddr, &next.LobbyAddr, file.LobbyAddr)
+	setInt(KeyTickRate, &next.GameTickRate, file.GameTickRate)
+	setInt(KeyAutoSaveInterval, &next.AutoSaveInterval, file.AutoSaveInterval)
+	setString(KeyTheme, &next.Theme, file.Theme)
+	setString(KeySymbols, &next.Symbols, file.Symbols)
+	setString(KeyNotifications, &next.Notifications, file.Notifications)
+	setBool(KeyConfirmPrompts, &next.ConfirmPrompts, file.ConfirmPrompts)
+	if file.Hooks != nil {
+		next.Hooks = file.Hooks
+		keys = append(keys, KeyHooks)
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	*c = next
+	for _, key := range keys {
+		c.setSource(key, SourceFile)
+	}
+	return nil
+}
+
+// Validate checks that settings are within their allowed values.
+func (c *Config) Validate() error {
+	if c.Slot < SlotLatest {
+		return fmt.Errorf("%w: slot must not be negative, got %d", ErrInvalidSetting, c.Slot)
+	}
+	switch c.StorageMode {
+	case "local", "mongodb":
+	default:
+		return fmt.Errorf("%w: storage_mode must be local or mongodb, got %q", ErrInvalidSetting, c.StorageMode)
+	}
+	if c.DataDir == "" {
+		return fmt.Errorf("%w: data_dir must not be empty", ErrInvalidSetting)
+	}
+	if c.APIAddr != "" && !isLoopback(c.APIAddr) {
+		return fmt.Errorf("%w: api_addr must be a localhost address such as 127.0.0.1:7777, got %q", ErrInvalidSetting, c.APIAddr)
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			return fmt.Errorf("%w: metrics_addr must be host:port such as :9090, got %q", ErrInvalidSetting, c.MetricsAddr)
+		}
+	}
+	if _, _, err := net.SplitHostPort(c.LobbyAddr); err != nil {
+		return fmt.Errorf("%w: lobby_addr must be host:port such as :2222, got %q", ErrInvalidSetting, c.LobbyAddr)
+	}
+	for _, hook := range c.Hooks {
+		if err := hook.validate(); err != nil {
+			return err
+		}
+	}
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("%w: log_level must be debug, info, warn or error, got %q", ErrInvalidSetting, c.LogLevel)
+	}
+	if c.GameTickRate < MinTickRate || c.GameTickRate > MaxTickRate {
+		return fmt.Errorf("%w: tick_rate must be %d-%d, got %d", ErrInvalidSetting, MinTickRate, MaxTickRate, c.GameTickRate)
+	}
+	if c.AutoSaveInterval < MinAutoSaveInterval {
+		return fmt.Errorf("%w: autosave_interval must be at least %d seconds, got %d", ErrInvalidSetting, MinAutoSaveInterval, c.AutoSaveInterval)
+	}
+	switch c.Symbols {
+	case SymbolsAuto, SymbolsEmoji, SymbolsASCII:
+	default:
+		return fmt.Errorf("%w: symbols must be auto, emoji or ascii, got %q", ErrInvalidSetting, c.Symbols)
+	}
+	switch c.Notifications {
+	case NotificationsAll, NotificationsNormal, NotificationsQuiet:
+	default:
+		return fmt.Errorf("%w: notifications must be all, normal or quiet, got %q", ErrInvalidSetting, c.Notifications)
+	}
+	return nil
+}
+
+// isLoopback reports whether a host:port address is on the loopback interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// SaveSettings writes the in-game settings to the config file at c.Path,
+// keeping any other keys already in the file. Settings overridden by the
+// environment or a flag are left as they are in the file.
+func (c *Config) SaveSettings() error {
+	if c.Path == "" {
+		return errors.New("no config file path")
+	}
+
+	values := map[string]interface{}{}
+	if _, err := toml.DecodeFile(c.Path, &values); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", c.Path, err)
+	}
+	settings := map[string]interface{}{
+		KeyTickRate:         c.GameTickRate,
+		KeyAutoSaveInterval: c.AutoSaveInterval,
+		KeyTheme:            c.Theme,
+		KeySymbols:          c.Symbols,
+		KeyNotifications:    c.Notifications,
+		KeyConfirmPrompts:   c.ConfirmPrompts,
+	}
+	for key, value := range settings {
+		switch c.Source(key) {
+		case SourceDefault, SourceFile:
+			values[key] = value
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(values); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, buf.Bytes(), 0644)
+}
+
+// Setting is one effective config value and where it came from.
+type Setting struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// Settings lists every setting with its effective value, in config file
+// order. Secrets are masked.
+func (c *Config) Settings() []Setting {
+	nickname := c.Nickname
+	if nickname == "" {
+		nickname = "(ask at startup)"
+	}
+	slot := strconv.Itoa(c.Slot)
+	if c.Slot == SlotLatest {
+		slot = "(latest)"
+	}
+	mongoURI := "(not set)"
+	if c.MongoDBURI != "" {
+		mongoURI = "(set)"
+	}
+	apiAddr := c.APIAddr
+	if apiAddr == "" {
+		apiAddr = "(off)"
+	}
+	apiToken := "(generated)"
+	if c.APIToken != "" {
+		apiToken = "(set)"
+	}
+	metricsAddr := c.MetricsAddr
+	if metricsAddr == "" {
+		metricsAddr = "(off)"
+	}
+	hooks := "(none)"
+	if len(c.Hooks) > 0 {
+		hooks = fmt.Sprintf("%d configured", len(c.Hooks))
+	}
+	theme := c.Theme
+	if theme == "" {
+		theme = "(default)"
+	}
+
+	values := []struct{ key, value string }{
+		{KeyNickname, nickname},
+		{KeySlot, slot},
+		{KeyStorageMode, c.StorageMode},
+		{KeyMongoDBURI, mongoURI},
+		{KeyDataDir, c.DataDir},
+		{KeyLogLevel, c.LogLevel},
+		{KeyDebug, strconv.FormatBool(c.Debug)},
+		{KeyRecordReplays, strconv.FormatBool(c.RecordReplays)},
+		{KeyAPIAddr, apiAddr},
+		{KeyAPIToken, apiToken},
+		{KeyMetricsAddr, metricsAddr},
+		{KeyLobbyAddr, c.LobbyAddr},
+		{KeyHooks, hooks},
+		{KeyTickRate, strconv.Itoa(c.GameTickRate)},
+		{KeyAutoSaveInterval, strconv.Itoa(c.AutoSaveInterval)},
+		{KeyTheme, theme},
+		{KeySymbols, c.Symbols},
+		{KeyNotifications, c.Notifications},
+		{KeyConfirmPrompts, strconv.FormatBool(c.ConfirmPrompts)},
+	}
+	settings := make([]Setting, len(values))
+	for i, v := range values {
+		settings[i] = Setting{Key: v.key, Value: v.value, Source: c.Source(v.key)}
+	}
+	return settings
+}
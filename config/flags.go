@@ -0,0 +1,71 @@
+package config
+
+import "flag"
+
+// Flags are command-line overrides bound to a flag set with BindFlags. Only
+// flags actually given on the command line override other sources.
+type Flags struct {
+	fs          *flag.FlagSet
+	nickname    string
+	slot        int
+	storageMode string
+	dataDir     string
+	logLevel    string
+	tickRate    int
+	apiAddr     string
+	metricsAddr string
+	lobbyAddr   string
+	record      bool
+}
+
+// BindFlags registers the config flags on fs. Parse fs before passing the
+// result to Load.
+func BindFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.nickname, "nickname", "", "player nickname to load or create (skips the prompt)")
+	fs.IntVar(&f.slot, "slot", 0, "save slot to load (default: most recent)")
+	fs.StringVar(&f.storageMode, "storage", "local", "storage mode: local or mongodb")
+	fs.StringVar(&f.dataDir, "data-dir", DefaultDataDir(), "directory for local saves and profiles")
+	fs.StringVar(&f.logLevel, "log-level", "info", "log level: debug, info, warn or error")
+	fs.IntVar(&f.tickRate, "tick-rate", 10, "game ticks per second (1-60)")
+	fs.StringVar(&f.apiAddr, "api-addr", "", "serve the local HTTP API on this address, e.g. 127.0.0.1:7777")
+	fs.StringVar(&f.metricsAddr, "metrics-addr", "", "serve Prometheus metrics on this address, e.g. :9090")
+	fs.StringVar(&f.lobbyAddr, "lobby-addr", ":2222", "address the SSH lobby listens on (manatty lobby)")
+	fs.BoolVar(&f.record, "record", false, "record a replay of the game in <data dir>/replays")
+	return f
+}
+
+// apply overrides c with the flags that were set.
+func (f *Flags) apply(c *Config) {
+	if f == nil {
+		return
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		var key string
+		switch fl.Name {
+		case "nickname":
+			c.Nickname, key = f.nickname, KeyNickname
+		case "slot":
+			c.Slot, key = f.slot, KeySlot
+		case "storage":
+			c.StorageMode, key = f.storageMode, KeyStorageMode
+		case "data-dir":
+			c.DataDir, key = f.dataDir, KeyDataDir
+		case "log-level":
+			c.LogLevel, key = f.logLevel, KeyLogLevel
+		case "tick-rate":
+			c.GameTickRate, key = f.tickRate, KeyTickRate
+		case "api-addr":
+			c.APIAddr, key = f.apiAddr, KeyAPIAddr
+		case "metrics-addr":
+			c.MetricsAddr, key = f.metricsAddr, KeyMetricsAddr
+		case "lobby-addr":
+			c.LobbyAddr, key = f.lobbyAddr, KeyLobbyAddr
+		case "record":
+			c.RecordReplays, key = f.record, KeyRecordReplays
+		default:
+			return
+		}
+		c.setSource(key, SourceFlag)
+	})
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Hook defaults
+const (
+	DefaultHookTimeout  = 10 * time.Second
+	DefaultHookAttempts = 3
+)
+
+// Hook reacts to game events, read from [[hooks]] tables in the config
+// file. It either POSTs the event as JSON to URL or runs Command with the
+// event in MANATTY_* environment variables.
+type Hook struct {
+	Name        string   `toml:"name"`         // Shown in logs ("" = the URL or command)
+	Events      []string `toml:"events"`       // Event names, e.g. "floor_event_started"
+	URL         string   `toml:"url"`          // http(s) URL to POST to
+	Command     []string `toml:"command"`      // Program and arguments, run without a shell
+	EveryFloors int      `toml:"every_floors"` // Only fire floor_climbed on multiples of this floor
+	Timeout     int      `toml:"timeout"`      // Seconds per attempt (0 = DefaultHookTimeout)
+	Attempts    int      `toml:"attempts"`     // Tries before giving up (0 = DefaultHookAttempts)
+}
+
+// Label names the hook in logs.
+func (h Hook) Label() string {
+	switch {
+	case h.Name != "":
+		return h.Name
+	case h.URL != "":
+		return h.URL
+	case len(h.Command) > 0:
+		return h.Command[0]
+	}
+	return "hook"
+}
+
+// TimeoutDuration returns the time allowed for each attempt.
+func (h Hook) TimeoutDuration() time.Duration {
+	if h.Timeout <= 0 {
+		return DefaultHookTimeout
+	}
+	return time.Duration(h.Timeout) * time.Second
+}
+
+// MaxAttempts returns how many times delivery is tried.
+func (h Hook) MaxAttempts() int {
+	if h.Attempts <= 0 {
+		return DefaultHookAttempts
+	}
+	return h.Attempts
+}
+
+// validate checks the hook's fields. Event names are checked by the hooks
+// package, which knows the game's events.
+func (h Hook) validate() error {
+	if len(h.Events) == 0 {
+		return fmt.Errorf("%w: hook %q needs at least one event", ErrInvalidSetting, h.Label())
+	}
+	if (h.URL == "") == (len(h.Command) == 0) {
+		return fmt.Errorf("%w: hook %q needs either url or command", ErrInvalidSetting, h.Label())
+	}
+	if h.URL != "" {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: hook %q url must be an http(s) URL, got %q", ErrInvalidSetting, h.Label(), h.URL)
+		}
+	}
+	if h.EveryFloors < 0 || h.Timeout < 0 || h.Attempts < 0 {
+		return fmt.Errorf("%w: hook %q every_floors, timeout and attempts must not be negative", ErrInvalidSetting, h.Label())
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// dialTimeout bounds connecting to the daemon.
+const dialTimeout = time.Second
+
+// dial connects to the daemon at path and sends hello.
+func dial(path string, hello Hello) (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%v)", ErrNotRunning, err)
+	}
+	if err := json.NewEncoder(conn).Encode(hello); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// QueryStatus asks the daemon at path how the game is doing.
+func QueryStatus(path string) (*Status, error) {
+	return query(path, OpStatus)
+}
+
+// RequestStop asks the daemon at path to save and exit.
+func RequestStop(path string) (*Status, error) {
+	return query(path, OpStop)
+}
+
+// query sends op and reads the Status reply.
+func query(path, op string) (*Status, error) {
+	conn, err := dial(path, Hello{Op: op})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(dialTimeout))
+	var status Status
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Attach connects this terminal to the daemon at path and runs its TUI
+// until it quits or the daemon goes away. The game keeps running after.
+func Attach(path string) error {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		return ErrNotTerminal
+	}
+	width, height, err := term.GetSize(stdout)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dial(path, Hello{
+		Op:           OpAttach,
+		Width:        width,
+		Height:       height,
+		ColorProfile: int(lipgloss.ColorProfile()),
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(stdin, state) }()
+
+	// Keys and resizes share the connection
+	var writeMu sync.Mutex
+	send := func(typ byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return writeFrame(conn, typ, payload)
+	}
+
+	stopResize := watchResize(stdout, func(width, height int) {
+		_ = send(frameResize, resizePayload(width, height))
+	})
+	defer stopResize()
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 && send(frameInput, buf[:n]) != nil {
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// The daemon closes the connection when the TUI quits
+	if _, err := io.Copy(os.Stdout, conn); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
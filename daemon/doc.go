@@ -0,0 +1,4 @@
+// Package daemon keeps a game running without a terminal. Loop ticks and
+// autosaves a game (the TUI steps the same loop when it runs the game
+// itself), and Server lets TUIs attach to a running loop over a Unix socket.
+package daemon
@@ -0,0 +1,239 @@
+package daemon
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/metrics"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/storage"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// saveTimeout bounds a single save.
+const saveTimeout = 5 * time.Second
+
+// Loop advances a game: it ticks the engine and saves on the autosave
+// interval. Everything that reads or changes the game state (the loop, the
+// TUIs, the HTTP API) holds the loop's lock.
+type Loop struct {
+	mu          sync.Mutex
+	saveMu      sync.Mutex // Held for a whole save (see Save)
+	gs          *models.GameState
+	player      *models.Player
+	engine      *engine.GameEngine
+	saveStore   storage.SaveStore
+	playerStore storage.PlayerStore
+	cfg         *config.Config
+	metrics     *metrics.Exporter
+
+	lastStep time.Time
+	saving   bool // An autosave is due and hasn't finished
+	running  atomic.Bool
+
+	recording  bool   // Record the game's replay (see Record)
+	replayPath string // Where the current replay is written ("" = nowhere)
+}
+
+// NewLoop creates a loop for gs. Tick rate and autosave interval are read
+// from cfg on every step, so changes in the Settings view apply at once.
+func NewLoop(gs *models.GameState, player *models.Player, e *engine.GameEngine, saveStore storage.SaveStore, playerStore storage.PlayerStore, cfg *config.Config) *Loop {
+	return &Loop{
+		gs:          gs,
+		player:      player,
+		engine:      e,
+		saveStore:   saveStore,
+		playerStore: playerStore,
+		cfg:         cfg,
+		lastStep:    time.Now(),
+	}
+}
+
+// SetMetrics sets the exporter that samples and save timings are recorded to.
+func (l *Loop) SetMetrics(x *metrics.Exporter) {
+	l.metrics = x
+}
+
+// Lock locks the game state.
+func (l *Loop) Lock() {
+	l.mu.Lock()
+}
+
+// Unlock unlocks the game state.
+func (l *Loop) Unlock() {
+	l.mu.Unlock()
+}
+
+// Do runs fn with the game state locked.
+func (l *Loop) Do(fn func(gs *models.GameState, e *engine.GameEngine)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fn(l.gs, l.engine)
+}
+
+// SetGameState switches the loop to gs. The caller holds the lock.
+func (l *Loop) SetGameState(gs *models.GameState) {
+	l.gs = gs
+	l.startReplay()
+}
+
+// Record records a replay of the game, written to the data directory with
+// every save. Switching games starts a new replay. The caller holds the
+// lock.
+func (l *Loop) Record() {
+	l.recording = true
+	l.startReplay()
+}
+
+// startReplay starts recording the current game if the loop records. A
+// challenge run keeps one replay from its start to its finish, which a
+// resumed run carries on with.
+func (l *Loop) startReplay() {
+	l.replayPath = ""
+	if !l.recording || l.gs == nil {
+		return
+	}
+	start := time.Now()
+	if run := l.gs.Challenge; run != nil && !run.StartedAt.IsZero() {
+		start = run.StartedAt
+	}
+	path, err := storage.ReplayPath(l.cfg.DataDir, l.gs, start)
+	if err != nil {
+		utils.Log(utils.LogWarn, "not recording a replay", "err", err)
+		return
+	}
+
+	if run := l.gs.Challenge; run != nil && run.Ticks > 0 {
+		replay, err := storage.ReadReplay(path)
+		if err == nil {
+			err = l.engine.ResumeRecording(l.gs, replay)
+		}
+		if err != nil {
+			// Without its replay the run's result can't be checked
+			utils.Log(utils.LogWarn, "challenge run not recorded", "path", path, "err", err)
+			return
+		}
+	} else {
+		l.engine.Record(l.gs, rand.Int64())
+	}
+	l.replayPath = path
+}
+
+// Running reports whether Run is advancing the game, in which case a TUI
+// attached to it must not step it as well.
+func (l *Loop) Running() bool {
+	return l.running.Load()
+}
+
+// Step advances the game to now and reports whether an autosave is due; the
+// caller then runs Save once it has let go of the lock, right away or in
+// the background. The caller holds the lock.
+func (l *Loop) Step(now time.Time) bool {
+	elapsed := now.Sub(l.lastStep)
+	l.lastStep = now
+	if l.gs == nil {
+		return false
+	}
+
+	l.engine.Advance(l.gs, elapsed)
+	if l.metrics != nil {
+		l.metrics.Sample(l.gs, l.engine.CalculateManaPerSecond(l.gs), elapsed, l.engine.Now())
+	}
+
+	interval := time.Duration(l.cfg.AutoSaveInterval) * time.Second
+	if l.saveStore == nil || l.saving || now.Sub(l.gs.Session.LastSavedAt) <= interval {
+		return false
+	}
+	l.saving = true
+	return true
+}
+
+// Save writes the game and the player's profile, then emits the outcome as
+// a game_saved or save_failed event. It holds the lock only to snapshot the
+// game and to record the outcome, so the game isn't held up while the
+// stores write; the caller must not hold it.
+func (l *Loop) Save(ctx context.Context) error {
+	// One save at a time, so an older snapshot never overwrites a newer one
+	l.saveMu.Lock()
+	defer l.saveMu.Unlock()
+
+	l.mu.Lock()
+	gs := l.gs
+	if l.saveStore == nil || gs == nil {
+		l.saving = false
+		l.mu.Unlock()
+		return nil
+	}
+	start := time.Now()
+	gs.Session.LastSavedAt = start
+	snapshot := gs.Clone()
+	var player *models.Player
+	if l.playerStore != nil && l.player != nil {
+		l.player.UpdateLastPlayed()
+		player = l.player.Clone()
+	}
+	var replay *models.Replay
+	if l.replayPath != "" {
+		replay = l.engine.Recording(gs)
+	}
+	replayPath := l.replayPath
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, saveTimeout)
+	defer cancel()
+	err := l.saveStore.Save(ctx, snapshot)
+	// The profile holds progress that outlives saves (achievements)
+	if err == nil && player != nil {
+		err = l.playerStore.Update(ctx, player)
+	}
+	if l.metrics != nil {
+		l.metrics.ObserveSave(time.Since(start), err)
+	}
+	if err == nil && replay != nil {
+		if err := storage.WriteReplay(replayPath, replay); err != nil {
+			utils.Log(utils.LogWarn, "replay not written", "path", replayPath, "err", err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.saving = false
+	if l.gs == gs {
+		// The store stamps what it writes
+		gs.SavedAt, gs.Version = snapshot.SavedAt, snapshot.Version
+	}
+	l.engine.RecordSave(gs, err)
+	return err
+}
+
+// Run advances the game at the configured tick rate until ctx is done,
+// autosaving as it goes, then saves a last time.
+func (l *Loop) Run(ctx context.Context) error {
+	l.running.Store(true)
+	defer l.running.Store(false)
+
+	for {
+		l.mu.Lock()
+		interval := time.Second / time.Duration(l.cfg.GameTickRate)
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return l.Save(context.Background())
+		case now := <-time.After(interval):
+			l.mu.Lock()
+			due := l.Step(now)
+			l.mu.Unlock()
+			if due {
+				if err := l.Save(ctx); err != nil {
+					utils.Log(utils.LogError, "autosave failed", "err", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// SocketFileName is the daemon's socket, in the data directory.
+const SocketFileName = "manatty.sock"
+
+// Errors
+var (
+	ErrNotRunning  = errors.New("no daemon is running")
+	ErrRunning     = errors.New("a daemon is already running")
+	ErrNotTerminal = errors.New("attaching needs a terminal")
+	ErrBadHello    = errors.New("bad hello")
+)
+
+// Operations a client can ask for in its Hello
+const (
+	OpAttach = "attach" // Run a TUI over the connection
+	OpStatus = "status" // Reply with a Status
+	OpStop   = "stop"   // Reply with a Status, then save and exit
+)
+
+// Hello is the first message on a connection: one line of JSON from the
+// client. For OpAttach it describes the client's terminal; frames follow.
+type Hello struct {
+	Op           string `json:"op"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	ColorProfile int    `json:"color_profile,omitempty"` // termenv.Profile of the client's terminal
+}
+
+// Status describes the running game, sent as one line of JSON.
+type Status struct {
+	Player    string    `json:"player"`
+	Floor     int       `json:"floor"`
+	Era       int       `json:"era"`
+	Clients   int       `json:"clients"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Frame types sent by an attached client. The daemon sends the terminal
+// output back unframed.
+const (
+	frameInput  byte = 'i' // Keyboard input bytes
+	frameResize byte = 'r' // Width and height, two big-endian uint16s
+)
+
+// maxFrame is the largest frame payload.
+const maxFrame = 1<<16 - 1
+
+// writeFrame writes a frame: its type, a big-endian uint16 length, then the
+// payload.
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	if len(payload) > maxFrame {
+		payload = payload[:maxFrame]
+	}
+	header := []byte{typ, 0, 0}
+	binary.BigEndian.PutUint16(header[1:], uint16(len(payload)))
+	if _, err := w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readFrame reads one frame.
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [3]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+// resizePayload encodes a terminal size.
+func resizePayload(width, height int) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload, uint16(width))
+	binary.BigEndian.PutUint16(payload[2:], uint16(height))
+	return payload
+}
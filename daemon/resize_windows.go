@@ -0,0 +1,37 @@
+//go:build windows
+
+package daemon
+
+import (
+	"time"
+
+	"golang.org/x/term"
+)
+
+// resizePoll is how often the console size is checked, as Windows has no
+// resize signal.
+const resizePoll = 250 * time.Millisecond
+
+// watchResize calls onResize with the terminal's new size whenever it
+// changes, until the returned function is called.
+func watchResize(fd int, onResize func(width, height int)) func() {
+	done := make(chan struct{})
+	go func() {
+		lastWidth, lastHeight, _ := term.GetSize(fd)
+		ticker := time.NewTicker(resizePoll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				width, height, err := term.GetSize(fd)
+				if err == nil && (width != lastWidth || height != lastHeight) {
+					lastWidth, lastHeight = width, height
+					onResize(width, height)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,230 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// maxHello is the longest Hello line accepted.
+const maxHello = 4 << 10
+
+// Server lets TUIs attach to a running Loop over a Unix socket. Each
+// attached terminal gets its own TUI, and all of them share the game.
+type Server struct {
+	path     string
+	loop     *Loop
+	newModel func(r *lipgloss.Renderer) tea.Model
+	listener net.Listener
+	started  time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+
+	mu       sync.Mutex
+	programs map[*tea.Program]bool
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a server for loop on the socket at path. newModel
+// creates the TUI for each attached terminal, drawn with a renderer for
+// that terminal's color profile.
+func NewServer(path string, loop *Loop, newModel func(r *lipgloss.Renderer) tea.Model) *Server {
+	return &Server{
+		path:     path,
+		loop:     loop,
+		newModel: newModel,
+		stop:     make(chan struct{}),
+		programs: map[*tea.Program]bool{},
+	}
+}
+
+// Listen creates the socket, replacing a stale one left by a daemon that
+// didn't exit cleanly. It fails with ErrRunning if a daemon is answering.
+func (s *Server) Listen() error {
+	if _, err := QueryStatus(s.path); err == nil {
+		return fmt.Errorf("%w on %s", ErrRunning, s.path)
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.Listen("unix", s.path)
+	if err != nil {
+		return err
+	}
+	// Only the owner may attach
+	if err := os.Chmod(s.path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+	s.listener = listener
+	s.started = time.Now()
+	return nil
+}
+
+// Serve accepts connections until Close is called.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+// Stopped is closed when a client asks the daemon to stop.
+func (s *Server) Stopped() <-chan struct{} {
+	return s.stop
+}
+
+// Close stops accepting connections, detaches every TUI and removes the
+// socket, waiting up to ctx for connections to finish.
+func (s *Server) Close(ctx context.Context) error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	_ = os.Remove(s.path)
+
+	s.mu.Lock()
+	for p := range s.programs {
+		p.Quit()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
+
+// handle serves one connection.
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(io.LimitReader(conn, maxHello))
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var hello Hello
+	if err := json.Unmarshal(line, &hello); err != nil {
+		utils.Log(utils.LogWarn, "daemon client sent a bad hello", "err", err)
+		return
+	}
+
+	switch hello.Op {
+	case OpStatus:
+		s.writeStatus(conn)
+	case OpStop:
+		s.writeStatus(conn)
+		s.stopOnce.Do(func() { close(s.stop) })
+	case OpAttach:
+		// Frames follow the hello and may be longer than it
+		s.attach(conn, io.MultiReader(r, conn), hello)
+	default:
+		utils.Log(utils.LogWarn, "daemon client sent an unknown op", "op", hello.Op)
+	}
+}
+
+// writeStatus replies with the game's status.
+func (s *Server) writeStatus(conn net.Conn) {
+	status := Status{StartedAt: s.started}
+	s.loop.Do(func(gs *models.GameState, e *engine.GameEngine) {
+		if s.loop.player != nil {
+			status.Player = s.loop.player.Username
+		}
+		if gs != nil {
+			status.Floor = gs.Tower.CurrentFloor
+			status.Era = gs.PrestigeData.CurrentEra
+		}
+	})
+	s.mu.Lock()
+	status.Clients = len(s.programs)
+	s.mu.Unlock()
+	_ = json.NewEncoder(conn).Encode(status)
+}
+
+// attach runs a TUI for the client until it quits or disconnects.
+func (s *Server) attach(conn net.Conn, frames io.Reader, hello Hello) {
+	// Each TUI renders for its own terminal's colors
+	renderer := lipgloss.NewRenderer(conn)
+	renderer.SetColorProfile(termenv.Profile(hello.ColorProfile))
+
+	input, inputWriter := io.Pipe()
+	p := tea.NewProgram(s.newModel(renderer),
+		tea.WithInput(input),
+		tea.WithOutput(conn),
+		tea.WithAltScreen(),
+		tea.WithoutSignalHandler(),
+	)
+	s.mu.Lock()
+	s.programs[p] = true
+	s.mu.Unlock()
+	utils.Log(utils.LogInfo, "terminal attached", "width", hello.Width, "height", hello.Height)
+
+	go func() {
+		defer inputWriter.Close()
+		defer p.Quit()
+		if hello.Width > 0 && hello.Height > 0 {
+			p.Send(tea.WindowSizeMsg{Width: hello.Width, Height: hello.Height})
+		}
+		for {
+			typ, payload, err := readFrame(frames)
+			if err != nil {
+				return
+			}
+			switch typ {
+			case frameInput:
+				if _, err := inputWriter.Write(payload); err != nil {
+					return
+				}
+			case frameResize:
+				if len(payload) == 4 {
+					p.Send(tea.WindowSizeMsg{
+						Width:  int(binary.BigEndian.Uint16(payload)),
+						Height: int(binary.BigEndian.Uint16(payload[2:])),
+					})
+				}
+			}
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		utils.Log(utils.LogWarn, "attached TUI stopped", "err", err)
+	}
+	s.mu.Lock()
+	delete(s.programs, p)
+	s.mu.Unlock()
+	utils.Log(utils.LogInfo, "terminal detached")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/daemon"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// daemonNickname is played when the daemon has no nickname configured, as
+// it can't prompt for one.
+const daemonNickname = "Wizard"
+
+// socketPath returns the daemon socket for cfg's data directory.
+func socketPath(cfg *config.Config) string {
+	return filepath.Join(cfg.DataDir, daemon.SocketFileName)
+}
+
+// loadCommandConfig parses game flags for a subcommand and loads the config.
+// ok is false if the flags were invalid or only asked for help.
+func loadCommandConfig(name string, args []string) (cfg *config.Config, code int, ok bool) {
+	fs, flags := gameFlags()
+	fs.Usage = func() {
+		fmt.Printf("Usage: manatty %s [flags]\n\n", name)
+		fs.SetOutput(os.Stdout)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, 0, false
+		}
+		return nil, 2, false
+	}
+	cfg, err := config.Load(flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring config file: %v\n", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, 2, false
+	}
+	return cfg, 0, true
+}
+
+// runDaemon runs "manatty daemon [flags]", which keeps the game running
+// without a terminal until interrupted, and "manatty daemon status|stop".
+func runDaemon(args []string) int {
+	if len(args) > 0 && (args[0] == "status" || args[0] == "stop") {
+		cfg, code, ok := loadCommandConfig("daemon "+args[0], args[1:])
+		if !ok {
+			return code
+		}
+		query := daemon.QueryStatus
+		if args[0] == "stop" {
+			query = daemon.RequestStop
+		}
+		status, err := query(socketPath(cfg))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%s on floor %d (era %d), %d attached, running for %s\n",
+			status.Player, status.Floor, status.Era, status.Clients, utils.FormatDuration(time.Since(status.StartedAt)))
+		if args[0] == "stop" {
+			fmt.Println("Stopping.")
+		}
+		return 0
+	}
+
+	cfg, code, ok := loadCommandConfig("daemon", args)
+	if !ok {
+		return code
+	}
+	nickname := cfg.Nickname
+	if nickname == "" {
+		nickname = daemonNickname
+	}
+
+	run := openGame(cfg, nickname)
+	defer run.close()
+
+	socket := socketPath(cfg)
+	server := daemon.NewServer(socket, run.loop, func(r *lipgloss.Renderer) tea.Model {
+		model := run.newModel()
+		model.SetRenderer(r)
+		return model
+	})
+	if err := server.Listen(); err != nil {
+		utils.Error("Can't start the daemon: %v", err)
+		return 1
+	}
+	go func() {
+		if err := server.Serve(); err != nil {
+			utils.Log(utils.LogError, "daemon stopped accepting", "err", err)
+		}
+	}()
+
+	// Run until interrupted or asked to stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		select {
+		case <-server.Stopped():
+			stop()
+		case <-ctx.Done():
+		}
+	}()
+
+	utils.Info("Daemon running %s's game on %s", nickname, socket)
+	utils.Info("Attach with: manatty attach")
+	if err := run.loop.Run(ctx); err != nil {
+		utils.Error("Final save failed: %v", err)
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelShutdown()
+	_ = server.Close(shutdownCtx)
+	utils.Info("Daemon stopped")
+	return 0
+}
+
+// runAttach runs "manatty attach [flags]".
+func runAttach(args []string) int {
+	cfg, code, ok := loadCommandConfig("attach", args)
+	if !ok {
+		return code
+	}
+	return attach(socketPath(cfg))
+}
+
+// attach runs the daemon's TUI in this terminal and returns the exit code.
+func attach(socket string) int {
+	if err := daemon.Attach(socket); err != nil {
+		if errors.Is(err, daemon.ErrNotRunning) {
+			fmt.Fprintln(os.Stderr, "No daemon is running. Start one with: manatty daemon")
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return 1
+	}
+	if _, err := daemon.QueryStatus(socket); err != nil {
+		fmt.Println("The daemon has stopped.")
+		return 0
+	}
+	fmt.Println("Detached. The game keeps running (stop it with: manatty daemon stop)")
+	return 0
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// AchievementTracker listens to engine events and records achievement
+// progress on a player. Unlocks are emitted as events for the UI to announce.
+type AchievementTracker struct {
+	engine      *GameEngine
+	player      *models.Player
+	gs          *models.GameState
+	definitions []*models.AchievementDefinition
+}
+
+// NewAchievementTracker subscribes a tracker for player to e's events and
+// catches up on progress already made in gs.
+func NewAchievementTracker(e *GameEngine, player *models.Player, gs *models.GameState) *AchievementTracker {
+	player.EnsureAchievements()
+	t := &AchievementTracker{
+		engine:      e,
+		player:      player,
+		definitions: game.DefaultAchievements(),
+	}
+	e.Subscribe(t.handleEvent)
+	t.SetGameState(gs)
+	return t
+}
+
+// SetGameState switches the tracked game, e.g. after loading a save.
+func (t *AchievementTracker) SetGameState(gs *models.GameState) {
+	t.gs = gs
+	if gs == nil {
+		return
+	}
+	t.syncFromGameState()
+	t.evaluate()
+}
+
+// Definitions returns all achievements in display order.
+func (t *AchievementTracker) Definitions() []*models.AchievementDefinition {
+	return t.definitions
+}
+
+// State returns the player's achievement progress.
+func (t *AchievementTracker) State() *models.AchievementState {
+	return t.player.Achievements
+}
+
+// Progress returns the tracked stat value and completion (0-1) for an achievement.
+func (t *AchievementTracker) Progress(def *models.AchievementDefinition) (value, progress float64) {
+	state := t.player.Achievements
+	value = state.Stats[def.Stat]
+	if state.IsUnlocked(def.ID) {
+		return value, 1
+	}
+	return value, def.Progress(value)
+}
+
+// TotalManaGenReward returns the summed mana generation bonus of unlocked achievements.
+func (t *AchievementTracker) TotalManaGenReward() float64 {
+	total := 0.0
+	for _, def := range t.definitions {
+		if t.player.Achievements.IsUnlocked(def.ID) {
+			total += def.RewardManaGen
+		}
+	}
+	return total
+}
+
+// syncFromGameState raises stats to what the current save already shows, so
+// progress made before tracking existed still counts.
+func (t *AchievementTracker) syncFromGameState() {
+	gs := t.gs
+	state := t.player.Achievements
+
+	state.RaiseStat(models.StatHighestFloor, float64(gs.Tower.MaxFloorReached))
+	state.RaiseStat(models.StatAscensions, float64(gs.PrestigeData.TotalAscensions))
+
+	casts := 0
+	for _, spell := range gs.Spells {
+		casts += spell.CastCount
+	}
+	state.RaiseStat(models.StatCasts, float64(casts))
+	if gs.Ledger != nil {
+		state.RaiseStat(models.StatHighestCast, gs.Ledger.Lifetime().HighestCast)
+	}
+
+	for _, ritual := range gs.Rituals {
+		if ritual.SignatureName != "" {
+			state.RaiseStat(models.SignatureRitualStat(ritual.SignatureName), 1)
+		}
+	}
+	state.RaiseStat(models.StatRitualSynergies, float64(len(t.engine.GetActiveSynergies(gs))))
+}
+
+// handleEvent updates stats from a single engine event.
+func (t *AchievementTracker) handleEvent(event game.EventData) {
+	if t.gs == nil {
+		return
+	}
+	state := t.player.Achievements
+
+	switch event.Type {
+	case game.EventFloorClimbed:
+		floor, _ := event.Data["floor"].(int)
+		state.RaiseStat(models.StatHighestFloor, float64(floor))
+		if floor == models.PrestigeMilestone {
+			elapsed := t.engine.now().Sub(t.eraStartedAt())
+			if elapsed > 0 {
+				state.LowerStat(models.StatFastestFloor100Ms, float64(elapsed.Milliseconds()))
+			}
+		}
+	case game.EventPrestige:
+		state.AddStat(models.StatAscensions, 1)
+	case game.EventSpellCast:
+		state.AddStat(models.StatCasts, 1)
+		if damage, _ := event.Data["damage"].(float64); damage > 0 {
+			state.RaiseStat(models.StatHighestCast, damage)
+		}
+		if crit, _ := event.Data["crit"].(bool); crit {
+			state.AddStat(models.StatCrits, 1)
+		}
+	case game.EventSynergyActivated:
+		state.AddStat(models.StatElementSynergies, 1)
+	case game.EventRitualCreated, game.EventRitualActivated:
+		if signature, _ := event.Data["signature"].(string); signature != "" {
+			state.RaiseStat(models.SignatureRitualStat(signature), 1)
+		}
+		state.RaiseStat(models.StatRitualSynergies, float64(len(t.engine.GetActiveSynergies(t.gs))))
+	default:
+		return
+	}
+
+	t.evaluate()
+}
+
+// evaluate unlocks any achievements whose targets have been met.
+func (t *AchievementTracker) evaluate() {
+	state := t.player.Achievements
+	for _, def := range t.definitions {
+		if state.IsUnlocked(def.ID) || def.Progress(state.Stats[def.Stat]) < 1 {
+			continue
+		}
+		state.Unlocked[def.ID] = t.engine.now()
+		t.engine.emit(t.gs, game.EventAchievementUnlocked(def.ID, def.Name))
+	}
+
+	// Rewards follow the player, so refresh them on whichever save is loaded
+	if t.gs.PassiveBonuses == nil {
+		t.gs.PassiveBonuses = models.NewPassiveBonuses()
+	}
+	t.gs.PassiveBonuses.AchievementManaGen = t.TotalManaGenReward()
+}
+
+// eraStartedAt returns when the current era began.
+func (t *AchievementTracker) eraStartedAt() time.Time {
+	if events := t.gs.PrestigeData.PrestigeEvents; len(events) > 0 {
+		return events[len(events)-1]
+	}
+	return t.player.CreatedAt
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// ProcessAutomation applies the player's automation rules once. It runs at
+// the end of every Tick and after each floor climbed during offline progress.
+// Actions taken are appended to the session's automation log.
+func (e *GameEngine) ProcessAutomation(gs *models.GameState) {
+	auto := gs.Automation
+	if auto == nil || !auto.AnyEnabled() {
+		return
+	}
+
+	// Answer a pending floor event
+	if auto.FloorEvent.Enabled && gs.Session.ActiveFloorEvent != nil {
+		choice := auto.FloorEvent.Choice
+		if _, ok := models.FloorEventChoiceDisplayNames[choice]; !ok {
+			choice = models.FloorEventChoiceManaGen
+		}
+		e.ChooseFloorEvent(gs, choice)
+		e.logAutomation(gs, "Floor event: "+models.FloorEventChoiceDisplayNames[choice])
+	}
+
+	// Prestige once climbing has stalled
+	if auto.Prestige.Enabled && e.shouldAutoPrestige(gs) {
+		if e.ProcessPrestige(gs) {
+			e.logAutomation(gs, fmt.Sprintf("Ascended to Era %d", gs.PrestigeData.CurrentEra))
+			if auto.ReapplyPreset.Enabled && gs.ApplyLoadoutPreset(auto.ReapplyPreset.Preset) {
+				e.logAutomation(gs, "Loadout applied: "+auto.ReapplyPreset.Preset)
+			}
+			return
+		}
+	}
+
+	// Spend surplus mana on the cheapest upgrade
+	if auto.Upgrade.Enabled {
+		threshold := auto.Upgrade.ManaAbove * game.CalculateFloorCost(gs.Tower.CurrentFloor)
+		if gs.Tower.CurrentMana > threshold {
+			if spell := e.cheapestUpgrade(gs); spell != nil && e.UpgradeSpell(gs, spell) == nil {
+				e.logAutomation(gs, fmt.Sprintf("Upgraded %s to Lv.%d", spell.Name, spell.Level))
+			}
+		}
+	}
+}
+
+// shouldAutoPrestige checks the auto-prestige rule against the current floor
+// and the recent climb rate.
+func (e *GameEngine) shouldAutoPrestige(gs *models.GameState) bool {
+	rule := gs.Automation.Prestige
+	if !e.CanPrestige(gs) || gs.Tower.CurrentFloor < rule.MinFloor {
+		return false
+	}
+	rate, ok := gs.Automation.FloorsPerHour(e.nowMs())
+	return ok && rate < rule.MaxFloorsPerHour
+}
+
+// cheapestUpgrade returns the spell with the lowest upgrade cost, or nil if
+// every spell is maxed.
+func (e *GameEngine) cheapestUpgrade(gs *models.GameState) *models.Spell {
+	var cheapest *models.Spell
+	cheapestCost := 0.0
+	for _, spell := range gs.Spells {
+		if spell.Level >= game.SpellMaxLevel {
+			continue
+		}
+		cost := e.GetSpellUpgradeCost(spell)
+		if cheapest == nil || cost < cheapestCost {
+			cheapest, cheapestCost = spell, cost
+		}
+	}
+	return cheapest
+}
+
+// logAutomation reports an automation action as an event, and adds it to the
+// offline summary while one is being calculated.
+func (e *GameEngine) logAutomation(gs *models.GameState, action string) {
+	if e.offlineAutomation != nil {
+		*e.offlineAutomation = append(*e.offlineAutomation, action)
+	}
+	e.emit(gs, game.EventAutomationAction(action))
+}
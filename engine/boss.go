@@ -0,0 +1,252 @@
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// bossBlocksClimb returns true if gs is on a boss floor whose boss hasn't
+// been beaten yet.
+func bossBlocksClimb(gs *models.GameState) bool {
+	return game.IsBossFloor(gs.Tower.CurrentFloor) && !gs.Tower.BossDefeated
+}
+
+// sigilNeedsDamage returns true while casting still moves gs towards the
+// next floor: the sigil isn't charged, or a boss is up.
+func sigilNeedsDamage(gs *models.GameState) bool {
+	return !gs.Tower.IsSigilCharged() || gs.Boss != nil
+}
+
+// updateBoss runs the boss fight for one tick: it summons the floor's boss
+// once the sigil is charged, then counts down the time limit and applies
+// enrage. Running out of time drains the sigil.
+func (e *GameEngine) updateBoss(gs *models.GameState, elapsed time.Duration) {
+	fight := gs.Boss
+	if fight == nil {
+		if bossBlocksClimb(gs) && gs.Tower.IsSigilCharged() {
+			e.startBoss(gs)
+		}
+		return
+	}
+
+	boss := game.GetBoss(fight.BossID)
+	fight.ElapsedMs += elapsed.Milliseconds()
+	if boss != nil && boss.EnrageAfterMs > 0 && fight.ElapsedMs >= boss.EnrageAfterMs {
+		if !fight.Enraged {
+			fight.Enraged = true
+			e.emit(gs, game.EventBossEnrage(fight.BossID, fight.Floor))
+		}
+		fight.HP += fight.MaxHP * boss.EnrageRegenPerSec * elapsed.Seconds()
+		if fight.HP > fight.MaxHP {
+			fight.HP = fight.MaxHP
+		}
+	}
+
+	if fight.ElapsedMs >= fight.TimeLimitMs {
+		gs.Boss = nil
+		gs.Tower.SigilCharge = 0
+		e.emit(gs, game.EventBossLost(fight.BossID, fight.Floor, fight.HP))
+	}
+}
+
+// startBoss summons the boss guarding gs's floor.
+func (e *GameEngine) startBoss(gs *models.GameState) {
+	floor := gs.Tower.CurrentFloor
+	boss := game.BossForFloor(floor)
+	if boss == nil {
+		return
+	}
+
+	hp := game.CalculateBossHP(floor, boss.HPMultiplier)
+	fight := &models.BossEncounter{
+		BossID:      boss.ID,
+		Floor:       floor,
+		MaxHP:       hp,
+		HP:          hp,
+		TimeLimitMs: game.BossTimeLimitMs,
+		Damage:      map[string]float64{},
+	}
+	// A shield nothing can break would make the fight unwinnable
+	if boss.ShieldElement != "" && canCastElement(gs, boss.ShieldElement) {
+		fight.ShieldElement = boss.ShieldElement
+		fight.MaxShield = hp * boss.ShieldFraction
+		fight.Shield = fight.MaxShield
+	}
+	gs.Boss = fight
+	e.emit(gs, game.EventBossAppeared(boss.ID, floor, hp, fight.TimeLimitMs))
+}
+
+// canCastElement returns true if gs has a spell of element it's allowed
+// to cast.
+func canCastElement(gs *models.GameState, element models.Element) bool {
+	for _, mod := range challengeModifiers(gs) {
+		if mod.DisabledElement == element {
+			return false
+		}
+	}
+	for _, spell := range gs.Spells {
+		if spell.Element == element {
+			return true
+		}
+	}
+	return false
+}
+
+// BossDamageMultiplier returns how much of a spell's damage of element
+// reaches the current boss's HP: its resistance and artifact bonuses.
+// Returns 1 outside boss fights.
+func (e *GameEngine) BossDamageMultiplier(gs *models.GameState, element models.Element) float64 {
+	if gs.Boss == nil {
+		return 1
+	}
+	multiplier := 1 + artifactBossDamageBonus(gs)
+	if boss := game.GetBoss(gs.Boss.BossID); boss != nil {
+		multiplier *= 1 - boss.Resistance(element)
+	}
+	return multiplier
+}
+
+// damageBoss deals damage from spell to the current boss and returns how
+// much landed. While the boss is shielded only the shield's element hurts,
+// and the shield takes the damage before resistances apply.
+func (e *GameEngine) damageBoss(gs *models.GameState, spell *models.Spell, damage float64) float64 {
+	fight := gs.Boss
+	if fight.Shielded() {
+		if spell.Element != fight.ShieldElement {
+			return 0
+		}
+		absorbed := damage
+		if absorbed > fight.Shield {
+			absorbed = fight.Shield
+		}
+		fight.Shield -= absorbed
+		fight.Damage[spell.ID] += absorbed
+		damage -= absorbed
+		if damage <= 0 {
+			return absorbed
+		}
+		dealt := e.damageBossHP(gs, spell, damage)
+		return absorbed + dealt
+	}
+	return e.damageBossHP(gs, spell, damage)
+}
+
+// damageBossHP deals damage to the current boss's HP.
+func (e *GameEngine) damageBossHP(gs *models.GameState, spell *models.Spell, damage float64) float64 {
+	fight := gs.Boss
+	dealt := damage * e.BossDamageMultiplier(gs, spell.Element)
+	if dealt > fight.HP {
+		dealt = fight.HP
+	}
+	fight.HP -= dealt
+	fight.Damage[spell.ID] += dealt
+	return dealt
+}
+
+// defeatBoss ends the current fight in victory: the floor opens up, spells
+// share the boss's spell XP by damage dealt, and a first win drops the
+// boss's artifact.
+func (e *GameEngine) defeatBoss(gs *models.GameState) {
+	fight := gs.Boss
+	gs.Boss = nil
+	gs.Tower.BossDefeated = true
+	gs.PrestigeData.BossesDefeated++
+
+	xp := game.CalculateBossSpellXP(fight.Floor)
+	e.grantSpellXP(gs, fight.Damage, xp)
+
+	artifactID := ""
+	if boss := game.GetBoss(fight.BossID); boss != nil && boss.ArtifactID != "" {
+		if gs.PrestigeData.AddArtifact(boss.ArtifactID) {
+			artifactID = boss.ArtifactID
+		}
+	}
+	e.emit(gs, game.EventBossBeaten(fight.BossID, fight.Floor, fight.ElapsedMs, xp, artifactID))
+}
+
+// grantSpellXP shares xp between spells by the damage each dealt, leveling
+// them up as their XP allows.
+func (e *GameEngine) grantSpellXP(gs *models.GameState, damage map[string]float64, xp float64) {
+	total := 0.0
+	for _, dealt := range damage {
+		total += dealt
+	}
+	if total <= 0 {
+		return
+	}
+
+	// Level up in a fixed order, so events come out the same every time
+	ids := make([]string, 0, len(damage))
+	for id := range damage {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		spell := gs.GetSpellByID(id)
+		if spell == nil || spell.Level >= game.SpellMaxLevel {
+			continue
+		}
+		spell.XP += xp * damage[id] / total
+		for spell.Level < game.SpellMaxLevel && spell.XP >= game.CalculateSpellXPForLevel(spell.Level) {
+			spell.XP -= game.CalculateSpellXPForLevel(spell.Level)
+			spell.LevelUp(game.SpellMaxLevel)
+			if e.OnSpellUpgraded != nil {
+				e.OnSpellUpgraded(spell)
+			}
+			e.emit(gs, game.EventSpellLevelUp(spell.ID, spell.Level).WithData("spell_xp", true))
+		}
+		if spell.Level >= game.SpellMaxLevel {
+			spell.XP = 0
+		}
+	}
+}
+
+// artifacts returns the artifacts gs has collected.
+func artifacts(gs *models.GameState) []*models.Artifact {
+	if gs.PrestigeData == nil {
+		return nil
+	}
+	return game.GetArtifacts(gs.PrestigeData.Artifacts)
+}
+
+// artifactManaGenBonus returns the artifacts' mana generation bonus.
+func artifactManaGenBonus(gs *models.GameState) float64 {
+	bonus := 0.0
+	for _, artifact := range artifacts(gs) {
+		bonus += artifact.ManaGenBonus
+	}
+	return bonus
+}
+
+// artifactSigilChargeBonus returns the artifacts' sigil charge bonus.
+func artifactSigilChargeBonus(gs *models.GameState) float64 {
+	bonus := 0.0
+	for _, artifact := range artifacts(gs) {
+		bonus += artifact.SigilChargeBonus
+	}
+	return bonus
+}
+
+// artifactDamageBonus returns the artifacts' damage bonus for element.
+func artifactDamageBonus(gs *models.GameState, element models.Element) float64 {
+	bonus := 0.0
+	for _, artifact := range artifacts(gs) {
+		if artifact.Element == element {
+			bonus += artifact.ElementDamageBonus
+		}
+	}
+	return bonus
+}
+
+// artifactBossDamageBonus returns the artifacts' bonus damage to bosses.
+func artifactBossDamageBonus(gs *models.GameState) float64 {
+	bonus := 0.0
+	for _, artifact := range artifacts(gs) {
+		bonus += artifact.BossDamageBonus
+	}
+	return bonus
+}
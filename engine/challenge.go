@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// Challenge rule errors
+var (
+	ErrSpellDisabled         = errors.New("spell's element is disabled in this challenge")
+	ErrManualCastingDisabled = errors.New("manual casting is disabled in this challenge")
+	ErrNotChallengeStart     = errors.New("replay doesn't start a challenge run from scratch")
+)
+
+// challengeStream selects the PCG stream challenge runs draw from.
+const challengeStream = 0x4d616e61 // "Mana"
+
+// countingSource counts the values drawn from src, so a resumed run can
+// pick the stream up where it left off.
+type countingSource struct {
+	src   rand.Source
+	draws *int64
+}
+
+// Uint64 draws the next value.
+func (s countingSource) Uint64() uint64 {
+	*s.draws++
+	return s.src.Uint64()
+}
+
+// UseChallenge switches the engine to run's seeded RNG, skipping the values
+// the run has already drawn.
+func (e *GameEngine) UseChallenge(run *models.ChallengeRun) {
+	src := rand.NewPCG(uint64(run.Challenge.Seed), challengeStream)
+	for i := int64(0); i < run.RNGDraws; i++ {
+		src.Uint64()
+	}
+	e.rng = rand.New(countingSource{src: src, draws: &run.RNGDraws})
+}
+
+// challengeModifiers returns the modifiers of gs's challenge run, if any.
+func challengeModifiers(gs *models.GameState) []*models.ChallengeModifier {
+	if gs.Challenge == nil {
+		return nil
+	}
+	return game.GetChallengeModifiers(gs.Challenge.Challenge.Modifiers)
+}
+
+// challengeCastError returns the challenge rule that forbids casting spell,
+// or nil.
+func challengeCastError(gs *models.GameState, spell *models.Spell, manual bool) error {
+	for _, mod := range challengeModifiers(gs) {
+		if mod.DisabledElement != "" && mod.DisabledElement == spell.Element {
+			return ErrSpellDisabled
+		}
+		if manual && mod.NoManualCasting {
+			return ErrManualCastingDisabled
+		}
+	}
+	return nil
+}
+
+// challengeManaGenMultiplier returns the challenge's mana generation factor.
+func challengeManaGenMultiplier(gs *models.GameState) float64 {
+	multiplier := 1.0
+	for _, mod := range challengeModifiers(gs) {
+		if mod.ManaGenMultiplier > 0 {
+			multiplier *= mod.ManaGenMultiplier
+		}
+	}
+	return multiplier
+}
+
+// challengeSigilChargeMultiplier returns the challenge's sigil charge factor.
+func challengeSigilChargeMultiplier(gs *models.GameState) float64 {
+	multiplier := 1.0
+	for _, mod := range challengeModifiers(gs) {
+		if mod.SigilChargeMultiplier > 0 {
+			multiplier *= mod.SigilChargeMultiplier
+		}
+	}
+	return multiplier
+}
+
+// floorEventInterval returns the floors between floor events.
+func floorEventInterval(gs *models.GameState) int {
+	for _, mod := range challengeModifiers(gs) {
+		if mod.FloorEventInterval > 0 {
+			return mod.FloorEventInterval
+		}
+	}
+	return game.FloorEventIntervalFloors
+}
+
+// advanceChallenge adds a tick's play time to gs's challenge run and
+// completes it once the target floor is reached.
+func (e *GameEngine) advanceChallenge(gs *models.GameState, elapsed time.Duration) {
+	run := gs.Challenge
+	if run == nil || run.Complete() {
+		return
+	}
+	run.ElapsedMs += elapsed.Milliseconds()
+	run.Ticks++
+	if gs.Tower.CurrentFloor < run.Challenge.TargetFloor {
+		return
+	}
+
+	completedAt := e.now()
+	run.CompletedAt = &completedAt
+	run.ReplayHash = e.challengeReplayHash(gs)
+	if e.OnChallengeCompleted != nil {
+		e.OnChallengeCompleted(run.Result())
+	}
+	e.emit(gs, game.EventChallengeDone(run.Challenge.ID, gs.Tower.CurrentFloor, run.ElapsedMs, run.ReplayHash))
+}
+
+// challengeDigest is what a challenge's replay hash covers: the challenge
+// (seed and modifiers), how long the run took, the player's actions and
+// the state they led to.
+type challengeDigest struct {
+	Challenge models.Challenge `json:"challenge"`
+	ElapsedMs int64            `json:"elapsed_ms"`
+	Ticks     int64            `json:"ticks"`
+	RNGDraws  int64            `json:"rng_draws"`
+	Actions   string           `json:"actions"` // Hash of the action log
+	State     string           `json:"state"`   // StateHash of the final state
+}
+
+// challengeReplayHash hashes gs's challenge run with the actions taken
+// since it started. Replaying those actions from the challenge's seed must
+// arrive at the same hash. It's empty when the engine isn't recording or
+// replaying the run, as there's nothing to check it against.
+func (e *GameEngine) challengeReplayHash(gs *models.GameState) string {
+	actions, ok := e.actionLog()
+	if gs.Challenge == nil || !ok {
+		return ""
+	}
+	run := gs.Challenge
+	log, _ := json.Marshal(append([]models.ReplayAction{}, actions...))
+	logSum := sha256.Sum256(log)
+	digest := challengeDigest{
+		Challenge: run.Challenge,
+		ElapsedMs: run.ElapsedMs,
+		Ticks:     run.Ticks,
+		RNGDraws:  run.RNGDraws,
+		Actions:   hex.EncodeToString(logSum[:]),
+		State:     StateHash(gs),
+	}
+	data, _ := json.Marshal(digest)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewChallengeGame starts a run of c for playerUUID: a new game in the
+// challenge slot with only the base spells, as everyone starts.
+func NewChallengeGame(playerUUID string, c models.Challenge) *models.GameState {
+	gs := models.NewGameState(playerUUID, models.ChallengeSlot)
+	for _, spell := range game.GetBaseSpells() {
+		gs.AddSpell(spell)
+	}
+	gs.Challenge = models.NewChallengeRun(c)
+	return gs
+}
+
+// CheckChallengeStart returns ErrNotChallengeStart unless replay records a
+// challenge run from its very start: a new game (see NewChallengeGame) of
+// the challenge its seed makes, drawing from that seed.
+func CheckChallengeStart(replay *models.Replay) error {
+	gs := replay.Initial
+	if gs == nil || gs.Challenge == nil {
+		return ErrNotChallengeStart
+	}
+	run := gs.Challenge
+	c := game.NewChallenge(run.Challenge.ID, run.Challenge.Seed)
+	if replay.Seed != c.Seed || !reflect.DeepEqual(c, run.Challenge) {
+		return ErrNotChallengeStart
+	}
+
+	// Only the times a new game was made at may differ
+	fresh := NewChallengeGame(gs.PlayerUUID, c)
+	fresh.Challenge.StartedAt = run.StartedAt
+	if gs.Session != nil {
+		fresh.Session.SessionStartMs = gs.Session.SessionStartMs
+		fresh.Session.LastTickMs = gs.Session.LastTickMs
+	}
+	if StateHash(fresh) != StateHash(gs) {
+		return ErrNotChallengeStart
+	}
+	return nil
+}
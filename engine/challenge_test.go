@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// playChallenge plays gs for d with auto-cast off, casting its first spell
+// by hand until the sigil is charged, and returns the engine's recording.
+func playChallenge(t *testing.T, e *GameEngine, gs *models.GameState, d time.Duration) *models.Replay {
+	t.Helper()
+	gs.Session.AutoCastEnabled = false
+	for played := time.Duration(0); played < d && !gs.Challenge.Complete(); played += time.Second {
+		if !gs.Tower.IsSigilCharged() {
+			_ = e.CastSpell(gs, gs.Spells[0], true)
+		}
+		e.Advance(gs, time.Second)
+	}
+	return e.Recording(gs)
+}
+
+func TestChallengeReplayCarriesOnAfterResume(t *testing.T) {
+	gs := NewChallengeGame("player", models.Challenge{ID: "test", Seed: 42, TargetFloor: 3})
+	e := NewGameEngine()
+	e.Record(gs, 0)
+	replay := playChallenge(t, e, gs, 30*time.Second)
+
+	// Resume from the save in a new engine, as loading the game does
+	saved := gs.Clone()
+	resumed := NewGameEngine()
+	if err := resumed.ResumeRecording(saved, replay); err != nil {
+		t.Fatalf("ResumeRecording: %v", err)
+	}
+	replay = playChallenge(t, resumed, saved, time.Hour)
+	if !saved.Challenge.Complete() {
+		t.Fatalf("run ended on floor %d without completing", saved.Tower.CurrentFloor)
+	}
+	if saved.Challenge.ReplayHash == "" {
+		t.Fatal("recorded run has no replay hash")
+	}
+	if replay.Initial.Challenge.Ticks != 0 {
+		t.Errorf("replay starts after %d ticks, want the start of the run", replay.Initial.Challenge.Ticks)
+	}
+
+	replayed, err := Replay(replay)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if replayed.Challenge.ReplayHash != saved.Challenge.ReplayHash {
+		t.Errorf("replayed hash %.12s, recorded %.12s", replayed.Challenge.ReplayHash, saved.Challenge.ReplayHash)
+	}
+}
+
+func TestResumeRecordingRejectsAnEditedSave(t *testing.T) {
+	gs := NewChallengeGame("player", models.Challenge{ID: "test", Seed: 42, TargetFloor: 3})
+	e := NewGameEngine()
+	e.Record(gs, 0)
+	replay := playChallenge(t, e, gs, 10*time.Second)
+
+	saved := gs.Clone()
+	saved.Tower.CurrentMana += 1000
+	if err := NewGameEngine().ResumeRecording(saved, replay); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("ResumeRecording of an edited save = %v, want ErrReplayMismatch", err)
+	}
+}
+
+func TestCheckChallengeStart(t *testing.T) {
+	c := game.NewChallenge("2026-W42", 7)
+	record := func(gs *models.GameState) *models.Replay {
+		e := NewGameEngine()
+		e.Record(gs, 0)
+		return e.Recording(gs)
+	}
+
+	if err := CheckChallengeStart(record(NewChallengeGame("player", c))); err != nil {
+		t.Errorf("new run: %v", err)
+	}
+
+	boosted := NewChallengeGame("player", c)
+	boosted.Tower.CurrentFloor = c.TargetFloor - 1
+	if err := CheckChallengeStart(record(boosted)); !errors.Is(err, ErrNotChallengeStart) {
+		t.Errorf("run started near the goal: %v, want ErrNotChallengeStart", err)
+	}
+
+	easier := NewChallengeGame("player", c)
+	easier.Challenge.Challenge.Modifiers = nil
+	if err := CheckChallengeStart(record(easier)); !errors.Is(err, ErrNotChallengeStart) {
+		t.Errorf("run without the seed's modifiers: %v, want ErrNotChallengeStart", err)
+	}
+}
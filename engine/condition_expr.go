@@ -0,0 +1,865 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// Rotation condition expressions
+//
+// A rotation entry can use a small boolean expression instead of one of the
+// fixed RotationCondition values, for example:
+//
+//	mana_pct > 0.6 && !synergy(fire) && sigil_pct < 0.9 && cd("spell_inferno") < 2s
+//
+// Supported syntax:
+//   - literals: numbers (1.5), durations (500ms, 2s, 1m, evaluated in seconds),
+//     percentages (60% == 0.6), strings ("spell_inferno"), true/false and
+//     element names (fire, ice, thunder, arcane)
+//   - arithmetic: + - * / and unary minus
+//   - comparison: < <= > >= == !=
+//   - logic: ! && || and parentheses
+//
+// Expressions are type-checked when compiled so mistakes are reported before
+// the rotation ever runs.
+
+// ConditionExprError describes a problem in a condition expression.
+// Pos is the 1-based byte offset where the problem was found, which is its
+// column in ASCII source.
+type ConditionExprError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ConditionExprError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos, e.Msg)
+}
+
+// ConditionSymbol documents a variable or function available in expressions.
+type ConditionSymbol struct {
+	Name        string
+	Description string
+}
+
+// ConditionVariables lists the variables available in condition expressions.
+var ConditionVariables = []ConditionSymbol{
+	{"mana", "current mana"},
+	{"mana_max", "mana needed for the next floor"},
+	{"mana_pct", "mana / mana_max (0-1)"},
+	{"mana_per_sec", "current mana generation rate"},
+	{"available_mana", "mana left above the rotation reserve"},
+	{"cost", "effective mana cost of this spell"},
+	{"sigil", "current sigil charge"},
+	{"sigil_max", "sigil charge needed for the next floor"},
+	{"sigil_pct", "sigil / sigil_max (0-1)"},
+	{"sigil_ready", "true when the sigil is fully charged"},
+	{"floor", "current floor"},
+	{"era", "current era"},
+	{"rituals", "number of active rituals"},
+	{"synergy_active", "true while any element synergy is active"},
+	{"synergy_time", "seconds left on the element synergy"},
+	{"boss_active", "true during a boss fight"},
+	{"boss_hp_pct", "boss health left (0-1, 0 outside fights)"},
+	{"boss_time", "seconds left to beat the boss (0 outside fights)"},
+	{"boss_shielded", "true while the boss's shield is up"},
+}
+
+// ConditionFunctions lists the functions available in condition expressions.
+var ConditionFunctions = []ConditionSymbol{
+	{"synergy(elem)", "element synergy active for elem"},
+	{"ritual_synergy(elem)", "an active ritual synergy boosts elem"},
+	{"resonance(elem)", "auto-cast loadout grants elem resonance"},
+	{"streak(elem)", "current same-element cast streak for elem"},
+	{"cd(spell)", "seconds of cooldown left on spell"},
+	{"ready(spell)", "spell is owned and off cooldown"},
+	{"level(spell)", "level of spell (0 if not owned)"},
+}
+
+// conditionEnv is the evaluation context for a compiled expression.
+type conditionEnv struct {
+	engine        *GameEngine
+	gs            *models.GameState
+	spell         *models.Spell
+	availableMana float64
+	nowMs         int64
+}
+
+type exprType int
+
+const (
+	typeNumber exprType = iota
+	typeBool
+	typeString
+	typeElement
+)
+
+func (t exprType) String() string {
+	switch t {
+	case typeNumber:
+		return "number"
+	case typeBool:
+		return "boolean"
+	case typeString:
+		return "string"
+	case typeElement:
+		return "element"
+	default:
+		return "unknown"
+	}
+}
+
+// exprNode is a type-checked, compiled expression node.
+type exprNode struct {
+	typ  exprType
+	pos  int
+	num  func(*conditionEnv) float64
+	bool func(*conditionEnv) bool
+	str  string // constant value for string and element nodes
+}
+
+// ConditionExpr is a compiled rotation condition expression.
+type ConditionExpr struct {
+	Source string
+	root   *exprNode
+}
+
+// CompileConditionExpr parses and type-checks a condition expression.
+func CompileConditionExpr(src string) (*ConditionExpr, error) {
+	tokens, err := lexConditionExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &ConditionExprError{Pos: 1, Msg: "expression is empty"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &ConditionExprError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	if root.typ != typeBool {
+		return nil, &ConditionExprError{Pos: root.pos, Msg: fmt.Sprintf("expression must be true/false, got %s", root.typ)}
+	}
+	return &ConditionExpr{Source: src, root: root}, nil
+}
+
+// ValidateConditionExpr reports whether src is a valid condition expression.
+func ValidateConditionExpr(src string) error {
+	_, err := CompileConditionExpr(src)
+	return err
+}
+
+func (c *ConditionExpr) eval(env *conditionEnv) bool {
+	return c.root.bool(env)
+}
+
+// compiledCondition looks up (or compiles and caches) an expression.
+// Invalid expressions are cached too so they are only reported once.
+func (e *GameEngine) compiledCondition(src string) (*ConditionExpr, error) {
+	if e.conditionCache == nil {
+		e.conditionCache = make(map[string]conditionCacheEntry)
+	}
+	if entry, ok := e.conditionCache[src]; ok {
+		return entry.expr, entry.err
+	}
+	expr, err := CompileConditionExpr(src)
+	e.conditionCache[src] = conditionCacheEntry{expr: expr, err: err}
+	return expr, err
+}
+
+type conditionCacheEntry struct {
+	expr *ConditionExpr
+	err  error
+}
+
+// EvaluateConditionExpr evaluates an expression for a spell in the current state.
+// Invalid expressions evaluate to false.
+func (e *GameEngine) EvaluateConditionExpr(gs *models.GameState, spell *models.Spell, src string, availableMana float64) bool {
+	expr, err := e.compiledCondition(src)
+	if err != nil {
+		return false
+	}
+	return expr.eval(&conditionEnv{
+		engine:        e,
+		gs:            gs,
+		spell:         spell,
+		availableMana: availableMana,
+		nowMs:         e.nowMs(),
+	})
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func lexConditionExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		pos := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, exprToken{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case c == ')':
+			tokens = append(tokens, exprToken{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case c == ',':
+			tokens = append(tokens, exprToken{kind: tokComma, text: ",", pos: pos})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, &ConditionExprError{Pos: pos, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: src[i+1 : i+1+end], pos: pos})
+			i += end + 2
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			value, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, &ConditionExprError{Pos: pos, Msg: fmt.Sprintf("invalid number %q", src[start:i])}
+			}
+			// Optional unit suffix: durations evaluate to seconds, % to a fraction.
+			unitStart := i
+			for i < len(src) && (isLetter(src[i]) || src[i] == '%') {
+				i++
+			}
+			switch unit := src[unitStart:i]; unit {
+			case "":
+			case "ms":
+				value /= 1000
+			case "s":
+			case "m":
+				value *= 60
+			case "%":
+				value /= 100
+			default:
+				return nil, &ConditionExprError{Pos: unitStart + 1, Msg: fmt.Sprintf("unknown unit %q (use ms, s, m or %%)", unit)}
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: src[start:i], num: value, pos: pos})
+		case isLetter(c) || c == '_':
+			start := i
+			for i < len(src) && (isLetter(src[i]) || isDigit(src[i]) || src[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: src[start:i], pos: pos})
+		default:
+			op := ""
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "&&", "||", "<=", ">=", "==", "!=":
+					op = two
+				}
+			}
+			if op == "" {
+				switch c {
+				case '<', '>', '!', '+', '-', '*', '/':
+					op = string(c)
+				case '&', '|', '=':
+					return nil, &ConditionExprError{Pos: pos, Msg: fmt.Sprintf("unexpected %q (did you mean %q?)", string(c), strings.Repeat(string(c), 2))}
+				default:
+					return nil, &ConditionExprError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", string(c))}
+				}
+			}
+			tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+		}
+	}
+	tokens = append(tokens, exprToken{kind: tokEOF, text: "end of expression", pos: len(src) + 1})
+	return tokens, nil
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+// Parser
+
+type exprParser struct {
+	tokens []exprToken
+	idx    int
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.idx] }
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.idx]
+	if tok.kind != tokEOF {
+		p.idx++
+	}
+	return tok
+}
+
+func (p *exprParser) acceptOp(ops ...string) (exprToken, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return tok, false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.idx++
+			return tok, true
+		}
+	}
+	return tok, false
+}
+
+func expectType(n *exprNode, want exprType, context string) error {
+	if n.typ != want {
+		return &ConditionExprError{Pos: n.pos, Msg: fmt.Sprintf("%s needs a %s, got %s", context, want, n.typ)}
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (*exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := expectType(left, typeBool, "'||'"); err != nil {
+			return nil, err
+		}
+		if err := expectType(right, typeBool, "'||'"); err != nil {
+			return nil, err
+		}
+		l, r := left.bool, right.bool
+		left = &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return l(env) || r(env) }}
+	}
+}
+
+func (p *exprParser) parseAnd() (*exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := expectType(left, typeBool, "'&&'"); err != nil {
+			return nil, err
+		}
+		if err := expectType(right, typeBool, "'&&'"); err != nil {
+			return nil, err
+		}
+		l, r := left.bool, right.bool
+		left = &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return l(env) && r(env) }}
+	}
+}
+
+func (p *exprParser) parseNot() (*exprNode, error) {
+	if op, ok := p.acceptOp("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := expectType(operand, typeBool, "'!'"); err != nil {
+			return nil, err
+		}
+		inner := operand.bool
+		return &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return !inner(env) }}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (*exprNode, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if op.text == "==" || op.text == "!=" {
+		if left.typ != right.typ {
+			return nil, &ConditionExprError{Pos: op.pos, Msg: fmt.Sprintf("cannot compare %s with %s", left.typ, right.typ)}
+		}
+		negate := op.text == "!="
+		switch left.typ {
+		case typeNumber:
+			l, r := left.num, right.num
+			return &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return (l(env) == r(env)) != negate }}, nil
+		case typeBool:
+			l, r := left.bool, right.bool
+			return &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return (l(env) == r(env)) != negate }}, nil
+		default:
+			equal := left.str == right.str
+			return &exprNode{typ: typeBool, pos: op.pos, bool: func(*conditionEnv) bool { return equal != negate }}, nil
+		}
+	}
+
+	if err := expectType(left, typeNumber, fmt.Sprintf("'%s'", op.text)); err != nil {
+		return nil, err
+	}
+	if err := expectType(right, typeNumber, fmt.Sprintf("'%s'", op.text)); err != nil {
+		return nil, err
+	}
+	l, r := left.num, right.num
+	var cmp func(a, b float64) bool
+	switch op.text {
+	case "<":
+		cmp = func(a, b float64) bool { return a < b }
+	case "<=":
+		cmp = func(a, b float64) bool { return a <= b }
+	case ">":
+		cmp = func(a, b float64) bool { return a > b }
+	default:
+		cmp = func(a, b float64) bool { return a >= b }
+	}
+	return &exprNode{typ: typeBool, pos: op.pos, bool: func(env *conditionEnv) bool { return cmp(l(env), r(env)) }}, nil
+}
+
+func (p *exprParser) parseSum() (*exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left, err = arithmeticNode(op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parseTerm() (*exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left, err = arithmeticNode(op, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func arithmeticNode(op exprToken, left, right *exprNode) (*exprNode, error) {
+	context := fmt.Sprintf("'%s'", op.text)
+	if err := expectType(left, typeNumber, context); err != nil {
+		return nil, err
+	}
+	if err := expectType(right, typeNumber, context); err != nil {
+		return nil, err
+	}
+	l, r := left.num, right.num
+	var fn func(env *conditionEnv) float64
+	switch op.text {
+	case "+":
+		fn = func(env *conditionEnv) float64 { return l(env) + r(env) }
+	case "-":
+		fn = func(env *conditionEnv) float64 { return l(env) - r(env) }
+	case "*":
+		fn = func(env *conditionEnv) float64 { return l(env) * r(env) }
+	default:
+		fn = func(env *conditionEnv) float64 {
+			d := r(env)
+			if d == 0 {
+				return 0
+			}
+			return l(env) / d
+		}
+	}
+	return &exprNode{typ: typeNumber, pos: left.pos, num: fn}, nil
+}
+
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	if op, ok := p.acceptOp("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := expectType(operand, typeNumber, "unary '-'"); err != nil {
+			return nil, err
+		}
+		inner := operand.num
+		return &exprNode{typ: typeNumber, pos: op.pos, num: func(env *conditionEnv) float64 { return -inner(env) }}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		value := tok.num
+		return &exprNode{typ: typeNumber, pos: tok.pos, num: func(*conditionEnv) float64 { return value }}, nil
+
+	case tokString:
+		return &exprNode{typ: typeString, pos: tok.pos, str: tok.text}, nil
+
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &ConditionExprError{Pos: closing.pos, Msg: fmt.Sprintf("expected ')' but found %q", closing.text)}
+		}
+		return inner, nil
+
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return identNode(tok)
+
+	case tokEOF:
+		return nil, &ConditionExprError{Pos: tok.pos, Msg: "expression ends unexpectedly"}
+
+	default:
+		return nil, &ConditionExprError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+}
+
+func (p *exprParser) parseCall(name exprToken) (*exprNode, error) {
+	p.next() // consume '('
+	var args []*exprNode
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, &ConditionExprError{Pos: closing.pos, Msg: fmt.Sprintf("expected ')' but found %q", closing.text)}
+	}
+	return callNode(name, args)
+}
+
+// parseArg parses a call argument. A lone identifier that is not a known
+// name is treated as a string so spell IDs can be written without quotes.
+func (p *exprParser) parseArg() (*exprNode, error) {
+	tok := p.peek()
+	if tok.kind == tokIdent {
+		following := p.tokens[p.idx+1].kind
+		if (following == tokRParen || following == tokComma) && !isKnownIdent(tok.text) {
+			p.next()
+			return &exprNode{typ: typeString, pos: tok.pos, str: tok.text}, nil
+		}
+	}
+	return p.parseOr()
+}
+
+func isKnownIdent(name string) bool {
+	if name == "true" || name == "false" || isElementName(name) {
+		return true
+	}
+	_, isNum := numberVariables[name]
+	_, isBool := boolVariables[name]
+	return isNum || isBool
+}
+
+// identNode resolves a bare identifier to a literal or variable.
+func identNode(tok exprToken) (*exprNode, error) {
+	switch tok.text {
+	case "true", "false":
+		value := tok.text == "true"
+		return &exprNode{typ: typeBool, pos: tok.pos, bool: func(*conditionEnv) bool { return value }}, nil
+	}
+	if isElementName(tok.text) {
+		return &exprNode{typ: typeElement, pos: tok.pos, str: tok.text}, nil
+	}
+
+	if fn, ok := numberVariables[tok.text]; ok {
+		return &exprNode{typ: typeNumber, pos: tok.pos, num: fn}, nil
+	}
+	if fn, ok := boolVariables[tok.text]; ok {
+		return &exprNode{typ: typeBool, pos: tok.pos, bool: fn}, nil
+	}
+
+	msg := fmt.Sprintf("unknown variable %q", tok.text)
+	if suggestion := closestConditionName(tok.text, variableNames()); suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return nil, &ConditionExprError{Pos: tok.pos, Msg: msg}
+}
+
+var numberVariables = map[string]func(*conditionEnv) float64{
+	"mana":     func(env *conditionEnv) float64 { return env.gs.Tower.CurrentMana },
+	"mana_max": func(env *conditionEnv) float64 { return env.gs.Tower.MaxMana },
+	"mana_pct": func(env *conditionEnv) float64 {
+		if env.gs.Tower.MaxMana <= 0 {
+			return 0
+		}
+		return env.gs.Tower.CurrentMana / env.gs.Tower.MaxMana
+	},
+	"mana_per_sec":   func(env *conditionEnv) float64 { return env.engine.CalculateManaPerSecond(env.gs) },
+	"available_mana": func(env *conditionEnv) float64 { return env.availableMana },
+	"cost": func(env *conditionEnv) float64 {
+		return env.engine.CalculateEffectiveSpellManaCost(env.gs, env.spell, false)
+	},
+	"sigil":        func(env *conditionEnv) float64 { return env.gs.Tower.SigilCharge },
+	"sigil_max":    func(env *conditionEnv) float64 { return env.gs.Tower.SigilRequired },
+	"sigil_pct":    func(env *conditionEnv) float64 { return env.gs.Tower.GetSigilProgress() },
+	"floor":        func(env *conditionEnv) float64 { return float64(env.gs.Tower.CurrentFloor) },
+	"era":          func(env *conditionEnv) float64 { return float64(env.gs.PrestigeData.CurrentEra) },
+	"rituals":      func(env *conditionEnv) float64 { return float64(len(env.gs.GetActiveRituals())) },
+	"synergy_time": func(env *conditionEnv) float64 { return float64(env.gs.GetSynergyTimeRemainingAt(env.nowMs)) / 1000 },
+	"boss_hp_pct": func(env *conditionEnv) float64 {
+		if env.gs.Boss == nil {
+			return 0
+		}
+		return env.gs.Boss.HPProgress()
+	},
+	"boss_time": func(env *conditionEnv) float64 {
+		if env.gs.Boss == nil {
+			return 0
+		}
+		return float64(env.gs.Boss.RemainingMs()) / 1000
+	},
+}
+
+var boolVariables = map[string]func(*conditionEnv) bool{
+	"sigil_ready":    func(env *conditionEnv) bool { return env.gs.Tower.IsSigilCharged() },
+	"synergy_active": func(env *conditionEnv) bool { return env.gs.HasActiveSynergyAt(env.nowMs) },
+	"boss_active":    func(env *conditionEnv) bool { return env.gs.Boss != nil },
+	"boss_shielded":  func(env *conditionEnv) bool { return env.gs.Boss != nil && env.gs.Boss.Shielded() },
+}
+
+// callNode type-checks a function call and compiles it.
+func callNode(name exprToken, args []*exprNode) (*exprNode, error) {
+	argCheck := func(want exprType) error {
+		if len(args) != 1 {
+			return &ConditionExprError{Pos: name.pos, Msg: fmt.Sprintf("%s() takes exactly 1 argument, got %d", name.text, len(args))}
+		}
+		arg := args[0]
+		if want == typeElement {
+			if arg.typ == typeString && isElementName(arg.str) {
+				arg.typ = typeElement
+			}
+			if arg.typ != typeElement {
+				return &ConditionExprError{Pos: arg.pos, Msg: fmt.Sprintf("%s() needs an element (fire, ice, thunder, arcane)", name.text)}
+			}
+			return nil
+		}
+		// Spell arguments may be written as "spell_inferno", spell_inferno or inferno.
+		if arg.typ != typeString {
+			return &ConditionExprError{Pos: arg.pos, Msg: fmt.Sprintf("%s() needs a spell id such as \"spell_fireball\"", name.text)}
+		}
+		return nil
+	}
+
+	switch name.text {
+	case "synergy", "ritual_synergy", "resonance", "streak":
+		if err := argCheck(typeElement); err != nil {
+			return nil, err
+		}
+		elem := models.Element(args[0].str)
+		switch name.text {
+		case "synergy":
+			return &exprNode{typ: typeBool, pos: name.pos, bool: func(env *conditionEnv) bool {
+				return env.gs.GetActiveSynergyAt(env.nowMs) == elem
+			}}, nil
+		case "ritual_synergy":
+			return &exprNode{typ: typeBool, pos: name.pos, bool: func(env *conditionEnv) bool {
+				return env.engine.GetSynergyBonusForElement(env.gs, elem) > 0
+			}}, nil
+		case "resonance":
+			return &exprNode{typ: typeBool, pos: name.pos, bool: func(env *conditionEnv) bool {
+				return env.gs.GetAutoCastElementCounts()[elem] >= game.ElementalResonanceMinSpells
+			}}, nil
+		default:
+			return &exprNode{typ: typeNumber, pos: name.pos, num: func(env *conditionEnv) float64 {
+				streak := env.gs.Session.LastCastElements
+				if len(streak) == 0 || streak[len(streak)-1] != elem {
+					return 0
+				}
+				return float64(len(streak))
+			}}, nil
+		}
+
+	case "cd", "ready", "level":
+		if err := argCheck(typeString); err != nil {
+			return nil, err
+		}
+		spellID, err := resolveSpellID(args[0])
+		if err != nil {
+			return nil, err
+		}
+		switch name.text {
+		case "cd":
+			return &exprNode{typ: typeNumber, pos: name.pos, num: func(env *conditionEnv) float64 {
+				spell := env.gs.GetSpellByID(spellID)
+				if spell == nil {
+					return math.Inf(1)
+				}
+				return float64(spell.CooldownRemainingMs) / 1000
+			}}, nil
+		case "ready":
+			return &exprNode{typ: typeBool, pos: name.pos, bool: func(env *conditionEnv) bool {
+				spell := env.gs.GetSpellByID(spellID)
+				return spell != nil && spell.IsReady()
+			}}, nil
+		default:
+			return &exprNode{typ: typeNumber, pos: name.pos, num: func(env *conditionEnv) float64 {
+				spell := env.gs.GetSpellByID(spellID)
+				if spell == nil {
+					return 0
+				}
+				return float64(spell.Level)
+			}}, nil
+		}
+	}
+
+	msg := fmt.Sprintf("unknown function %q", name.text)
+	if suggestion := closestConditionName(name.text, functionNames()); suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return nil, &ConditionExprError{Pos: name.pos, Msg: msg}
+}
+
+// resolveSpellID maps a spell argument to a known spell definition ID.
+func resolveSpellID(arg *exprNode) (string, error) {
+	id := arg.str
+	if game.GetSpellDefinition(id) == nil && game.GetSpellDefinition("spell_"+id) != nil {
+		id = "spell_" + id
+	}
+	if game.GetSpellDefinition(id) == nil {
+		ids := []string{}
+		for _, def := range game.DefaultSpells() {
+			ids = append(ids, def.ID)
+		}
+		msg := fmt.Sprintf("unknown spell %q", arg.str)
+		if suggestion := closestConditionName(id, ids); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		return "", &ConditionExprError{Pos: arg.pos, Msg: msg}
+	}
+	return id, nil
+}
+
+func isElementName(name string) bool {
+	switch models.Element(name) {
+	case models.ElementFire, models.ElementIce, models.ElementThunder, models.ElementArcane:
+		return true
+	}
+	return false
+}
+
+func variableNames() []string {
+	names := make([]string, 0, len(ConditionVariables))
+	for _, v := range ConditionVariables {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func functionNames() []string {
+	names := make([]string, 0, len(ConditionFunctions))
+	for _, f := range ConditionFunctions {
+		names = append(names, f.Name[:strings.IndexByte(f.Name, '(')])
+	}
+	return names
+}
+
+// closestConditionName returns the candidate within edit distance 2 of name, if any.
+func closestConditionName(name string, candidates []string) string {
+	best := ""
+	bestDist := 3
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+	for _, candidate := range sorted {
+		if d := editDistance(name, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// conditionTestEnv is a game on floor 1 with Fireball 1.5s into its
+// cooldown and a fire synergy up.
+func conditionTestEnv() *conditionEnv {
+	gs := models.NewGameState("player", 0)
+	for _, spell := range game.GetBaseSpells() {
+		gs.AddSpell(spell)
+	}
+	gs.GetSpellByID("spell_fireball").CooldownRemainingMs = 1500
+	const nowMs = 1_000_000
+	gs.ActivateSynergyAt(models.ElementFire, 5000, nowMs)
+	return &conditionEnv{engine: NewGameEngine(), gs: gs, nowMs: nowMs}
+}
+
+func TestConditionExprEvaluates(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		// Precedence: * / over + -, comparison over !, ! over &&, && over ||
+		{"product before sum", "1 + 2 * 3 == 7", true},
+		{"parentheses first", "(1 + 2) * 3 == 9", true},
+		{"subtraction is left to right", "10 - 2 - 3 == 5", true},
+		{"division is left to right", "8 / 2 / 2 == 2", true},
+		{"unary minus", "-2 * 3 == -6", true},
+		{"division by zero", "1 / 0 == 0", true},
+		{"and before or", "true || false && false", true},
+		{"not before and", "!false && false", false},
+		{"not binds to one operand", "!true || true", true},
+		{"comparison inside not", "!(1 > 2)", true},
+		{"boolean equality", "(1 < 2) == true", true},
+		{"element equality", "fire != ice", true},
+
+		// Units
+		{"milliseconds", "500ms == 0.5", true},
+		{"seconds", "2s == 2", true},
+		{"minutes", "1.5m == 90", true},
+		{"percent", "60% == 0.6", true},
+		{"leading dot", ".5 == 50%", true},
+
+		// Functions and their argument forms
+		{"cd with quoted id", `cd("spell_fireball") == 1500ms`, true},
+		{"cd with bare id", "cd(spell_fireball) > 1s", true},
+		{"cd without prefix", "cd(fireball) < 2s", true},
+		{"cd of a spell not owned", "cd(inferno) > 1m", true},
+		{"ready", "ready(fireball)", false},
+		{"level", "level(fireball) == 1 && level(inferno) == 0", true},
+		{"synergy", "synergy(fire) && !synergy(ice)", true},
+		{"synergy with quoted element", `synergy("fire")`, true},
+		{"variables", "floor == 1 && synergy_active && synergy_time == 5s", true},
+		{"boss variables outside a fight", "!boss_active && !boss_shielded && boss_hp_pct == 0 && boss_time == 0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := CompileConditionExpr(tt.src)
+			if err != nil {
+				t.Fatalf("CompileConditionExpr(%q): %v", tt.src, err)
+			}
+			if got := expr.eval(conditionTestEnv()); got != tt.want {
+				t.Errorf("%q = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionExprErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string
+	}{
+		// Syntax
+		{"", 1, "expression is empty"},
+		{"mana >", 7, "expression ends unexpectedly"},
+		{"mana > 5 5", 10, `unexpected "5"`},
+		{"(mana > 5", 10, "expected ')'"},
+		{`cd("spell_fireball) < 1`, 4, "unterminated string"},
+		{"mana # 5", 6, `unexpected character "#"`},
+		{"mana > 5 & sigil_ready", 10, `unexpected "&" (did you mean "&&"?)`},
+		{"mana = 5", 6, `unexpected "=" (did you mean "=="?)`},
+		{"mana > 5h", 9, `unknown unit "h"`},
+
+		// Types
+		{"mana", 1, "expression must be true/false, got number"},
+		{"mana == true", 6, "cannot compare number with boolean"},
+		{"!mana", 2, "'!' needs a boolean, got number"},
+		{"mana + true > 1", 8, "'+' needs a number, got boolean"},
+		{"sigil_ready && 1", 16, "'&&' needs a boolean, got number"},
+
+		// Function arguments
+		{"cd() < 1", 1, "cd() takes exactly 1 argument, got 0"},
+		{"cd(fireball, inferno) < 1", 1, "cd() takes exactly 1 argument, got 2"},
+		{"cd(3) < 1", 4, `cd() needs a spell id such as "spell_fireball"`},
+		{"ready(fire)", 7, `ready() needs a spell id`},
+		{"synergy(5)", 9, "synergy() needs an element"},
+		{"synergy(water)", 9, "synergy() needs an element"},
+		{"cd(spell_firebal) < 1", 4, `unknown spell "spell_firebal" (did you mean "spell_fireball"?)`},
+
+		// Did you mean
+		{"mana_pc > 0.5", 1, `unknown variable "mana_pc" (did you mean "mana_pct"?)`},
+		{"boss_hp_pt < 0.5", 1, `unknown variable "boss_hp_pt" (did you mean "boss_hp_pct"?)`},
+		{"sigil_redy", 1, `unknown variable "sigil_redy" (did you mean "sigil_ready"?)`},
+		{"synergie(fire)", 1, `unknown function "synergie" (did you mean "synergy"?)`},
+		{"gold > 5", 1, `unknown variable "gold"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			err := ValidateConditionExpr(tt.src)
+			var exprErr *ConditionExprError
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("ValidateConditionExpr(%q) = %v, want a ConditionExprError", tt.src, err)
+			}
+			if exprErr.Pos != tt.pos || !strings.Contains(exprErr.Msg, tt.msg) {
+				t.Errorf("ValidateConditionExpr(%q) = %v, want col %d: %s", tt.src, err, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+func TestConditionExprDidYouMeanOnlyCloseNames(t *testing.T) {
+	err := ValidateConditionExpr("gold > 5")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("ValidateConditionExpr(%q) = %v, want no suggestion", "gold > 5", err)
+	}
+}
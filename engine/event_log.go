@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+)
+
+// EventFilter selects which entries the event log shows.
+type EventFilter int
+
+const (
+	FilterAll EventFilter = iota
+	FilterCasts
+	FilterCrits
+	FilterClimbs
+	FilterRituals
+	FilterSaves
+)
+
+// EventFilters lists filters in display order.
+var EventFilters = []EventFilter{FilterAll, FilterCasts, FilterCrits, FilterClimbs, FilterRituals, FilterSaves}
+
+// EventFilterNames provides display names for filters.
+var EventFilterNames = map[EventFilter]string{
+	FilterAll:     "All",
+	FilterCasts:   "Casts",
+	FilterCrits:   "Crits",
+	FilterClimbs:  "Climbs",
+	FilterRituals: "Rituals",
+	FilterSaves:   "Saves",
+}
+
+// Matches reports whether an event passes the filter.
+func (f EventFilter) Matches(event game.EventData) bool {
+	switch f {
+	case FilterCasts:
+		return event.Type == game.EventSpellCast
+	case FilterCrits:
+		crit, _ := event.Data["crit"].(bool)
+		return event.Type == game.EventSpellCast && crit
+	case FilterClimbs:
+		return event.Type == game.EventFloorClimbed || event.Type == game.EventPrestige
+	case FilterRituals:
+		return event.Type == game.EventRitualCreated || event.Type == game.EventRitualActivated || event.Type == game.EventRitualExpired
+	case FilterSaves:
+		return event.Type == game.EventGameSaved || event.Type == game.EventSaveFailed || event.Type == game.EventGameLoaded
+	}
+	return true
+}
+
+// EventLogEntry is an event with the time it was recorded.
+type EventLogEntry struct {
+	At    time.Time
+	Event game.EventData
+}
+
+// EventLog keeps a bounded history of engine events, oldest dropped first.
+// Readers follow it with their own cursor (see Since), so several sessions
+// can watch one game.
+type EventLog struct {
+	engine  *GameEngine
+	entries []EventLogEntry
+	next    int
+	size    int
+	total   uint64 // Events ever recorded
+}
+
+// NewEventLog subscribes a log keeping up to size events to e's events.
+func NewEventLog(e *GameEngine, size int) *EventLog {
+	l := &EventLog{
+		engine:  e,
+		entries: make([]EventLogEntry, 0, size),
+		size:    size,
+	}
+	e.Subscribe(l.Record)
+	return l
+}
+
+// Record adds an event to the log. Events from outside the engine (such as
+// saves) are recorded by calling it directly.
+func (l *EventLog) Record(event game.EventData) {
+	if l.size <= 0 {
+		return
+	}
+	l.total++
+	entry := EventLogEntry{At: l.engine.now(), Event: event}
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, entry)
+		return
+	}
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % l.size
+}
+
+// Len returns the number of events in the log.
+func (l *EventLog) Len() int {
+	return len(l.entries)
+}
+
+// Cursor returns a cursor past the newest event, for a reader that only
+// wants events from now on.
+func (l *EventLog) Cursor() uint64 {
+	return l.total
+}
+
+// Since returns the events recorded after cursor, oldest first, and the
+// cursor to pass next time. Events dropped before the reader got to them
+// are skipped.
+func (l *EventLog) Since(cursor uint64) ([]EventLogEntry, uint64) {
+	if cursor >= l.total {
+		return nil, l.total
+	}
+	n := len(l.entries)
+	if missed := l.total - cursor; missed < uint64(n) {
+		n = int(missed)
+	}
+	entries := make([]EventLogEntry, 0, n)
+	for i := len(l.entries) - n; i < len(l.entries); i++ {
+		entries = append(entries, l.entries[(l.next+i)%len(l.entries)])
+	}
+	return entries, l.total
+}
+
+// Entries returns the events passing filter, newest first.
+func (l *EventLog) Entries(filter EventFilter) []EventLogEntry {
+	var entries []EventLogEntry
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entry := l.entries[(l.next+i)%len(l.entries)]
+		if filter.Matches(entry.Event) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/Ltorre/ManaTTY/game"
+)
+
+func TestEventLogSinceGivesEachReaderEveryEvent(t *testing.T) {
+	l := NewEventLog(NewGameEngine(), 3)
+	first, second := l.Cursor(), l.Cursor()
+
+	l.Record(game.EventAutomationAction("one"))
+	var entries []EventLogEntry
+	entries, first = l.Since(first)
+	if len(entries) != 1 || entries[0].Event.Message != "Auto: one" {
+		t.Fatalf("first reader got %v, want the one event", entries)
+	}
+
+	// The second reader still gets it, and the first only what's new
+	l.Record(game.EventAutomationAction("two"))
+	entries, second = l.Since(second)
+	if len(entries) != 2 {
+		t.Errorf("second reader got %d events, want 2", len(entries))
+	}
+	entries, first = l.Since(first)
+	if len(entries) != 1 || entries[0].Event.Message != "Auto: two" {
+		t.Errorf("first reader got %v, want only the new event", entries)
+	}
+	if entries, _ = l.Since(first); len(entries) != 0 {
+		t.Errorf("first reader got %d events with nothing new", len(entries))
+	}
+
+	// A reader that falls behind gets what the log still holds, oldest first
+	for _, action := range []string{"three", "four", "five", "six"} {
+		l.Record(game.EventAutomationAction(action))
+	}
+	entries, second = l.Since(second)
+	if len(entries) != 3 || entries[0].Event.Message != "Auto: four" || entries[2].Event.Message != "Auto: six" {
+		t.Errorf("lagging reader got %v, want four to six", entries)
+	}
+	if second != l.Cursor() {
+		t.Errorf("lagging reader's cursor %d, want %d", second, l.Cursor())
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// Subscribe registers a handler that receives every event the engine emits.
+// Handlers run synchronously on the goroutine that drives the engine.
+func (e *GameEngine) Subscribe(handler func(game.EventData)) {
+	e.subscribers = append(e.subscribers, handler)
+}
+
+// TraceEvents logs every event the engine emits, with its data, at debug
+// level. Used for verbose tracing when debugging is enabled.
+func (e *GameEngine) TraceEvents(logger *utils.Logger) {
+	e.Subscribe(func(event game.EventData) {
+		keys := make([]string, 0, len(event.Data))
+		for key := range event.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		keyvals := []interface{}{"event", event.Type}
+		for _, key := range keys {
+			keyvals = append(keyvals, key, event.Data[key])
+		}
+		logger.Log(utils.LogDebug, event.Message, keyvals...)
+	})
+}
+
+// RecordSave emits the outcome of a save (game_saved or save_failed). Saves
+// run outside the engine, so the UI reports them here when they finish.
+func (e *GameEngine) RecordSave(gs *models.GameState, err error) {
+	if err != nil {
+		e.emit(gs, game.EventSaveError(gs.Slot, err))
+		return
+	}
+	e.emit(gs, game.EventSaved(gs.Slot))
+}
+
+// emit records an event in the game's stats ledger and graph samples, then
+// delivers it to all subscribers.
+func (e *GameEngine) emit(gs *models.GameState, event game.EventData) {
+	e.recordStats(gs, event)
+	e.recordSampleEvent(gs, event)
+	for _, handler := range e.subscribers {
+		handler(event)
+	}
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"math/rand/v2"
 	"time"
 
 	"github.com/Ltorre/ManaTTY/game"
@@ -17,23 +18,84 @@ type GameEngine struct {
 	OnSynergyActivated func(element models.Element)
 	OnSpellUpgraded    func(spell *models.Spell)
 
+	// Called when a challenge run reaches its target floor
+	OnChallengeCompleted func(result models.ChallengeResult)
+
+	// Event bus subscribers (see Subscribe)
+	subscribers []func(game.EventData)
+
 	// v1.4.0: Synergy caching
 	cachedSynergies     []models.RitualSynergy
 	synergyGeneration   int
 	lastRitualStateHash string
+
+	// Compiled rotation condition expressions, keyed by source
+	conditionCache map[string]conditionCacheEntry
+
+	// Clock and randomness; nil means wall clock and the global RNG.
+	// Simulations swap these for a virtual clock and a seeded RNG.
+	clock func() time.Time
+	rng   *rand.Rand
+
+	// Observes rotation decisions during dry-runs
+	rotationTrace func(spell *models.Spell, outcome rotationOutcome, manaBefore, sigilBefore float64)
+
+	// Player actions are recorded while set (see Record); actions the
+	// engine takes itself during a tick aren't
+	recorder *recorder
+	ticking  bool
+
+	// Automation actions collected while calculating offline progress
+	offlineAutomation *[]string
+
+	// The actions taken so far while running Replay
+	replayed  []models.ReplayAction
+	replaying bool
 }
 
 // NewGameEngine creates a new game engine instance.
 func NewGameEngine() *GameEngine {
 	return &GameEngine{
 		synergyGeneration: -1, // Force initial calculation
+		conditionCache:    make(map[string]conditionCacheEntry),
+	}
+}
+
+// now returns the engine's current time.
+func (e *GameEngine) now() time.Time {
+	if e.clock != nil {
+		return e.clock()
+	}
+	return time.Now()
+}
+
+// Now returns the game's current time: the wall clock, or while a replay
+// is recorded, the clock that only moves with its ticks. Views showing
+// game timers read it rather than time.Now.
+func (e *GameEngine) Now() time.Time {
+	return e.now()
+}
+
+// nowMs returns the engine's current time in Unix milliseconds.
+func (e *GameEngine) nowMs() int64 {
+	return e.now().UnixMilli()
+}
+
+// randFloat64 returns a random number in [0, 1) from the engine's RNG.
+func (e *GameEngine) randFloat64() float64 {
+	if e.rng != nil {
+		return e.rng.Float64()
 	}
+	return rand.Float64()
 }
 
 // Tick processes a single game tick, updating all game state.
 func (e *GameEngine) Tick(gs *models.GameState, elapsed time.Duration) {
+	e.ticking = true
+	defer func() { e.ticking = false }()
+
 	// Expire any timed floor event (vanishes with no bonus if unanswered)
-	gs.EnsureFloorEventExpiry(time.Now().UnixMilli())
+	gs.EnsureFloorEventExpiry(e.nowMs())
 	// Expire any floor-based buff if its floor window has passed
 	gs.MaybeExpireFloorEventBuff(gs.Tower.CurrentFloor)
 
@@ -56,10 +118,15 @@ func (e *GameEngine) Tick(gs *models.GameState, elapsed time.Duration) {
 		gs.Tower.SigilCharge = gs.Tower.SigilRequired
 	}
 
+	// Boss floors: a charged sigil summons the boss, which has to be beaten
+	e.updateBoss(gs, elapsed)
+
 	// Try to climb floors
 	for e.TryClimbFloor(gs) {
 		// Keep climbing
 	}
+	e.recordFloorGate(gs, elapsed)
+	e.advanceChallenge(gs, elapsed)
 
 	// Update spell cooldowns
 	e.UpdateSpellCooldowns(gs, elapsedMs)
@@ -72,8 +139,14 @@ func (e *GameEngine) Tick(gs *models.GameState, elapsed time.Duration) {
 		e.ProcessRotation(gs)
 	}
 
+	// Automation rules (auto-prestige, auto-upgrade, ...)
+	e.ProcessAutomation(gs)
+
+	// Time-series samples for graphs
+	e.sampleTick(gs)
+
 	// Update session data
-	gs.UpdateSession()
+	gs.UpdateSessionAt(e.now())
 }
 
 // CalculateManaPerSecond returns the current mana generation rate.
@@ -96,19 +169,33 @@ func (e *GameEngine) CalculateManaPerSecond(gs *models.GameState) float64 {
 		manaPerSec *= (1.0 + ritualManaGenBonus)
 	}
 
+	// Permanent achievement rewards
+	if gs.PassiveBonuses != nil && gs.PassiveBonuses.AchievementManaGen > 0 {
+		manaPerSec *= (1.0 + gs.PassiveBonuses.AchievementManaGen)
+	}
+
 	// Floor-event temporary bonus
 	if gs.GetActiveFloorBuffChoice(gs.Tower.CurrentFloor) == models.FloorEventChoiceManaGen {
 		manaPerSec *= (1.0 + game.FloorEventManaGenBonus)
 	}
 
+	// Artifacts dropped by bosses
+	if bonus := artifactManaGenBonus(gs); bonus > 0 {
+		manaPerSec *= (1.0 + bonus)
+	}
+
+	// Challenge modifiers
+	manaPerSec *= challengeManaGenMultiplier(gs)
+
 	return manaPerSec
 }
 
 // TryClimbFloor attempts to climb to the next floor.
-// Requires both sufficient mana AND a charged Ascension Sigil.
+// Requires both sufficient mana AND a charged Ascension Sigil, and on boss
+// floors a beaten boss.
 func (e *GameEngine) TryClimbFloor(gs *models.GameState) bool {
 	// Check sigil requirement first
-	if !gs.Tower.IsSigilCharged() {
+	if !gs.Tower.IsSigilCharged() || bossBlocksClimb(gs) {
 		return false
 	}
 
@@ -118,6 +205,9 @@ func (e *GameEngine) TryClimbFloor(gs *models.GameState) bool {
 		// Spend mana and climb
 		gs.Tower.SpendMana(requiredMana)
 		gs.Tower.ClimbFloor()
+		if gs.Automation != nil {
+			gs.Automation.RecordClimb(e.nowMs())
+		}
 
 		// Update the new requirements for next floor
 		gs.Tower.MaxMana = game.CalculateFloorCost(gs.Tower.CurrentFloor)
@@ -133,6 +223,7 @@ func (e *GameEngine) TryClimbFloor(gs *models.GameState) bool {
 		if e.OnFloorClimbed != nil {
 			e.OnFloorClimbed(gs.Tower.CurrentFloor)
 		}
+		e.emit(gs, game.EventFloorUp(gs.Tower.CurrentFloor).WithData("mana_cost", requiredMana))
 
 		return true
 	}
@@ -151,7 +242,7 @@ func (e *GameEngine) maybeStartFloorEvent(gs *models.GameState) {
 	if gs.Tower.CurrentFloor <= 0 {
 		return
 	}
-	if gs.Tower.CurrentFloor%game.FloorEventIntervalFloors != 0 {
+	if gs.Tower.CurrentFloor%floorEventInterval(gs) != 0 {
 		return
 	}
 	if gs.Session.LastFloorEventFloor == gs.Tower.CurrentFloor {
@@ -159,7 +250,32 @@ func (e *GameEngine) maybeStartFloorEvent(gs *models.GameState) {
 	}
 
 	gs.Session.LastFloorEventFloor = gs.Tower.CurrentFloor
-	gs.StartFloorEvent(gs.Tower.CurrentFloor, time.Now(), game.FloorEventTimeoutMs)
+	gs.StartFloorEvent(gs.Tower.CurrentFloor, e.now(), game.FloorEventTimeoutMs)
+	e.emit(gs, game.EventFloorEventAppeared(gs.Tower.CurrentFloor, game.FloorEventTimeoutMs))
+}
+
+// ChooseFloorEvent answers the pending floor event with a temporary bonus.
+// Returns false if no event is pending.
+func (e *GameEngine) ChooseFloorEvent(gs *models.GameState, choice models.FloorEventChoice) bool {
+	if gs.Session == nil || gs.Session.ActiveFloorEvent == nil {
+		return false
+	}
+	e.record(gs, models.ReplayAction{Type: models.ReplayFloorEvent, Choice: choice})
+	floor := gs.Session.ActiveFloorEvent.Floor
+	gs.ApplyFloorEventChoice(choice, gs.Tower.CurrentFloor, game.FloorEventBuffDurationFloors)
+	e.emit(gs, game.EventFloorBonus(floor, string(choice)))
+	return true
+}
+
+// DismissFloorEvent ignores the pending floor event, forgoing its bonus.
+// Returns false if no event is pending.
+func (e *GameEngine) DismissFloorEvent(gs *models.GameState) bool {
+	if gs.Session == nil || gs.Session.ActiveFloorEvent == nil {
+		return false
+	}
+	e.record(gs, models.ReplayAction{Type: models.ReplayDismissFloorEvent})
+	gs.ClearFloorEvent()
+	return true
 }
 
 // CheckSpellUnlocks checks if new spells should be unlocked at the current floor.
@@ -193,6 +309,7 @@ func (e *GameEngine) CheckSpellUnlocks(gs *models.GameState) {
 		if e.OnSpellUnlocked != nil {
 			e.OnSpellUnlocked(spell)
 		}
+		e.emit(gs, game.EventNewSpell(spell.ID, spell.Name))
 	}
 }
 
@@ -265,9 +382,9 @@ func (e *GameEngine) checkAutoCastCondition(gs *models.GameState, cond models.Au
 	case models.ConditionManaAbove75:
 		return gs.Tower.CurrentMana >= gs.Tower.MaxMana*0.75
 	case models.ConditionSigilNotFull:
-		return !gs.Tower.IsSigilCharged()
+		return sigilNeedsDamage(gs)
 	case models.ConditionSynergyActive:
-		return gs.HasActiveSynergy()
+		return gs.HasActiveSynergyAt(e.nowMs())
 	default:
 		return true
 	}
@@ -15,6 +15,7 @@ type OfflineProgress struct {
 	FinalFloor     int
 	FinalMana      float64
 	SpellsUnlocked []string
+	Automation     []string // Actions taken by automation rules
 }
 
 // CalculateOfflineProgress processes offline time and returns the results.
@@ -37,6 +38,11 @@ func (e *GameEngine) CalculateOfflineProgress(gs *models.GameState) *OfflineProg
 		}
 	}
 
+	// Collect what automation does for the summary
+	var automation []string
+	e.offlineAutomation = &automation
+	defer func() { e.offlineAutomation = nil }()
+
 	// Calculate mana generation rate (at time of disconnect)
 	manaPerSecond := e.CalculateManaPerSecond(gs)
 
@@ -63,6 +69,16 @@ func (e *GameEngine) CalculateOfflineProgress(gs *models.GameState) *OfflineProg
 				spellsUnlocked = append(spellsUnlocked, spell.ID)
 			}
 		}
+
+		// Automation reacts to each floor (floor events, upgrades)
+		if e.processOfflineAutomation(gs) {
+			spellsUnlocked = []string{}
+		}
+	}
+
+	// Final pass, e.g. to prestige a tower that stalled while offline
+	if e.processOfflineAutomation(gs) {
+		spellsUnlocked = []string{}
 	}
 
 	return &OfflineProgress{
@@ -72,11 +88,26 @@ func (e *GameEngine) CalculateOfflineProgress(gs *models.GameState) *OfflineProg
 		FinalFloor:     gs.Tower.CurrentFloor,
 		FinalMana:      gs.Tower.CurrentMana,
 		SpellsUnlocked: spellsUnlocked,
+		Automation:     automation,
 	}
 }
 
+// processOfflineAutomation runs automation rules and reports whether they
+// prestiged, which discards spells unlocked earlier in the offline run.
+func (e *GameEngine) processOfflineAutomation(gs *models.GameState) bool {
+	era := gs.PrestigeData.CurrentEra
+	e.ProcessAutomation(gs)
+	return gs.PrestigeData.CurrentEra != era
+}
+
 // ApplyOfflineProgress processes and applies offline progress to game state.
 func (e *GameEngine) ApplyOfflineProgress(gs *models.GameState) *OfflineProgress {
+	// Challenge runs only advance while they're played, and carry on from
+	// exactly where they were saved so their replay does too
+	if gs.Challenge != nil {
+		return &OfflineProgress{FinalFloor: gs.Tower.CurrentFloor, FinalMana: gs.Tower.CurrentMana}
+	}
+
 	progress := e.CalculateOfflineProgress(gs)
 
 	// Unlock any spells that should have been unlocked
@@ -88,8 +119,10 @@ func (e *GameEngine) ApplyOfflineProgress(gs *models.GameState) *OfflineProgress
 		}
 	}
 
-	// Reset session for new play session
-	gs.Session = models.NewSessionData()
+	// Reset session timing for the new play session, keeping the player's loadout
+	gs.Session = models.ResumeSessionData(gs.Session)
+
+	e.emit(gs, game.EventOffline(progress.ManaGenerated, progress.FloorsClimbed, progress.TimeOffline.Seconds()))
 
 	return progress
 }
@@ -38,16 +38,21 @@ func (e *GameEngine) ProcessPrestige(gs *models.GameState) bool {
 	if !e.CanPrestige(gs) {
 		return false
 	}
+	e.record(gs, models.ReplayAction{Type: models.ReplayPrestige})
 
 	// Get base spells for reset
 	baseSpells := game.GetBaseSpells()
 
 	// Process prestige (resets tower, applies bonuses)
 	gs.ResetForPrestige(baseSpells)
+	if gs.Automation != nil {
+		gs.Automation.ResetFloorRate(e.nowMs())
+	}
 
 	if e.OnPrestige != nil {
 		e.OnPrestige(gs.PrestigeData.CurrentEra)
 	}
+	e.emit(gs, game.EventPrestiged(gs.PrestigeData.CurrentEra, gs.PrestigeData.EraMultiplier))
 
 	return true
 }
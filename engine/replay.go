@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// Replay errors
+var (
+	ErrInvalidReplay      = errors.New("invalid replay")
+	ErrReplayActionFailed = errors.New("recorded action failed")
+	ErrReplayMismatch     = errors.New("replay ended in a different state")
+)
+
+// ReplayTick is the fixed tick length games are recorded at.
+const ReplayTick = 100 * time.Millisecond
+
+// replayStream selects the PCG stream recorded games draw from.
+const replayStream = 0x52706c79 // "Rply"
+
+// recorder collects a Replay while the engine runs a game.
+type recorder struct {
+	replay   *models.Replay
+	pending  time.Duration // Elapsed time not ticked yet
+	settings []byte        // Last recorded settings, as JSON
+}
+
+// Record starts recording gs. From here on the engine runs on a clock that
+// only moves with ticks (see Advance) and draws from an RNG seeded with seed
+// (a challenge run keeps its own), and every player action is recorded.
+func (e *GameEngine) Record(gs *models.GameState, seed int64) {
+	if gs.Challenge != nil {
+		seed = gs.Challenge.Challenge.Seed
+	}
+	replay := &models.Replay{
+		Version:   models.ReplayVersion,
+		Seed:      seed,
+		StartedAt: e.now().UTC().Round(0),
+		TickMs:    ReplayTick.Milliseconds(),
+		Initial:   gs.Clone(),
+		Actions:   []models.ReplayAction{},
+	}
+	r := &recorder{replay: replay}
+	r.settings, _ = json.Marshal(replaySettings(gs))
+	e.recorder = r
+	e.useReplayClock(replay.StartedAt, ReplayTick, &replay.Ticks)
+	e.seedReplayRNG(gs, seed)
+}
+
+// ResumeRecording carries on recording a challenge run from replay, which
+// must end in gs's state: the clock picks up at replay's last tick and new
+// actions are added to it. Only challenge runs can be resumed, as only
+// their RNG picks up where it left off.
+func (e *GameEngine) ResumeRecording(gs *models.GameState, replay *models.Replay) error {
+	if replay.Version != models.ReplayVersion || replay.Initial == nil || replay.TickMs != ReplayTick.Milliseconds() {
+		return ErrInvalidReplay
+	}
+	if gs.Challenge == nil || replay.Seed != gs.Challenge.Challenge.Seed {
+		return ErrInvalidReplay
+	}
+	if StateHash(gs) != replay.FinalHash {
+		return ErrReplayMismatch
+	}
+	if replay.Actions == nil {
+		replay.Actions = []models.ReplayAction{}
+	}
+	r := &recorder{replay: replay}
+	r.settings, _ = json.Marshal(replaySettings(gs))
+	e.recorder = r
+	e.useReplayClock(replay.StartedAt, ReplayTick, &replay.Ticks)
+	e.seedReplayRNG(gs, replay.Seed)
+	return nil
+}
+
+// Recording returns a copy of the replay recorded so far, ending at gs's
+// current state, or nil if the engine isn't recording.
+func (e *GameEngine) Recording(gs *models.GameState) *models.Replay {
+	if e.recorder == nil {
+		return nil
+	}
+	e.recorder.captureSettings(gs)
+	replay := *e.recorder.replay
+	replay.Actions = append([]models.ReplayAction{}, replay.Actions...)
+	replay.FinalHash = StateHash(gs)
+	return &replay
+}
+
+// Advance moves gs forward by elapsed. While recording, the engine ticks in
+// fixed ReplayTick steps and carries the remainder over to the next call,
+// so the recording replays tick for tick; otherwise it's a single Tick.
+func (e *GameEngine) Advance(gs *models.GameState, elapsed time.Duration) {
+	r := e.recorder
+	if r == nil {
+		e.Tick(gs, elapsed)
+		return
+	}
+	r.pending += elapsed
+	for r.pending >= ReplayTick {
+		r.pending -= ReplayTick
+		r.captureSettings(gs)
+		r.replay.Ticks++
+		e.Tick(gs, ReplayTick)
+	}
+}
+
+// useReplayClock sets the engine's clock to start plus *ticks steps.
+func (e *GameEngine) useReplayClock(start time.Time, step time.Duration, ticks *int64) {
+	e.clock = func() time.Time {
+		return start.Add(time.Duration(*ticks) * step)
+	}
+}
+
+// seedReplayRNG seeds the engine's RNG for a recording of gs.
+func (e *GameEngine) seedReplayRNG(gs *models.GameState, seed int64) {
+	if gs.Challenge != nil {
+		e.UseChallenge(gs.Challenge)
+		return
+	}
+	e.rng = rand.New(rand.NewPCG(uint64(seed), replayStream))
+}
+
+// actionLog returns the player actions taken so far in the recording or
+// replay the engine runs, and whether it runs one.
+func (e *GameEngine) actionLog() ([]models.ReplayAction, bool) {
+	if e.recorder != nil {
+		return e.recorder.replay.Actions, true
+	}
+	return e.replayed, e.replaying
+}
+
+// record adds a player action to the recording. Call it once the action
+// is known to succeed, before it changes anything. What the engine does by
+// itself while ticking (rotation casts, automation) isn't recorded: replays
+// do it again.
+func (e *GameEngine) record(gs *models.GameState, action models.ReplayAction) {
+	r := e.recorder
+	if r == nil || e.ticking {
+		return
+	}
+	r.captureSettings(gs)
+	action.Tick = r.replay.Ticks
+	r.replay.Actions = append(r.replay.Actions, action)
+}
+
+// captureSettings records gs's settings if the player changed them since
+// they were last recorded. Settings have no effect until the next tick or
+// action, so recording them just before either replays exactly.
+func (r *recorder) captureSettings(gs *models.GameState) {
+	data, err := json.Marshal(replaySettings(gs))
+	if err != nil || bytes.Equal(data, r.settings) {
+		return
+	}
+	r.settings = data
+	settings := &models.ReplaySettings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return
+	}
+	r.replay.Actions = append(r.replay.Actions, models.ReplayAction{
+		Tick:     r.replay.Ticks,
+		Type:     models.ReplaySettingsEdit,
+		Settings: settings,
+	})
+}
+
+// replaySettings returns gs's settings. They share memory with gs.
+func replaySettings(gs *models.GameState) *models.ReplaySettings {
+	settings := &models.ReplaySettings{Presets: gs.Presets}
+	if gs.Session != nil {
+		settings.AutoCastEnabled = gs.Session.AutoCastEnabled
+		settings.AutoCastSlots = gs.Session.AutoCastSlots
+		settings.AutoCastConfigs = gs.Session.AutoCastConfigs
+		settings.Rotation = gs.Session.Rotation
+	}
+	if gs.Automation != nil {
+		// Floor rate tracking changes as the game runs; only rules are settings
+		automation := *gs.Automation
+		automation.RecentClimbsMs = nil
+		settings.Automation = &automation
+	}
+	if gs.PassiveBonuses != nil {
+		settings.AchievementManaGen = gs.PassiveBonuses.AchievementManaGen
+	}
+	return settings
+}
+
+// applyReplaySettings puts recorded settings back on gs.
+func applyReplaySettings(gs *models.GameState, recorded *models.ReplaySettings) error {
+	// Copy, so the game doesn't write into the replay
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	settings := &models.ReplaySettings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return err
+	}
+
+	if gs.Session != nil {
+		gs.Session.AutoCastEnabled = settings.AutoCastEnabled
+		gs.Session.AutoCastSlots = settings.AutoCastSlots
+		gs.Session.AutoCastConfigs = settings.AutoCastConfigs
+		gs.Session.Rotation = settings.Rotation
+	}
+	switch {
+	case settings.Automation == nil:
+		gs.Automation = nil
+	case gs.Automation == nil:
+		gs.Automation = settings.Automation
+		gs.Automation.RecentClimbsMs = []int64{}
+	default:
+		gs.Automation.Prestige = settings.Automation.Prestige
+		gs.Automation.Upgrade = settings.Automation.Upgrade
+		gs.Automation.FloorEvent = settings.Automation.FloorEvent
+		gs.Automation.ReapplyPreset = settings.Automation.ReapplyPreset
+	}
+	gs.Presets = settings.Presets
+	if gs.PassiveBonuses != nil {
+		gs.PassiveBonuses.AchievementManaGen = settings.AchievementManaGen
+	}
+	return nil
+}
+
+// Replay runs replay's actions through a new engine from its initial state
+// and returns the state it ends in. If that state's hash isn't the recorded
+// one, the state is returned with ErrReplayMismatch.
+func Replay(replay *models.Replay) (*models.GameState, error) {
+	if replay.Version != models.ReplayVersion || replay.Initial == nil || replay.TickMs <= 0 {
+		return nil, ErrInvalidReplay
+	}
+	gs := replay.Initial.Clone()
+	if gs == nil {
+		return nil, ErrInvalidReplay
+	}
+
+	e := NewGameEngine()
+	var ticks int64
+	step := time.Duration(replay.TickMs) * time.Millisecond
+	e.useReplayClock(replay.StartedAt, step, &ticks)
+	e.seedReplayRNG(gs, replay.Seed)
+	e.replaying = true
+
+	actions := replay.Actions
+	for {
+		for len(actions) > 0 && actions[0].Tick == ticks {
+			if err := e.replayAction(gs, actions[0]); err != nil {
+				return gs, fmt.Errorf("tick %d, %s: %w", ticks, actions[0].Type, err)
+			}
+			actions = actions[1:]
+			e.replayed = replay.Actions[:len(replay.Actions)-len(actions)]
+		}
+		if ticks >= replay.Ticks {
+			break
+		}
+		ticks++
+		e.Tick(gs, step)
+	}
+	if len(actions) > 0 {
+		return gs, fmt.Errorf("%w: action at tick %d is out of order", ErrInvalidReplay, actions[0].Tick)
+	}
+
+	if StateHash(gs) != replay.FinalHash {
+		return gs, ErrReplayMismatch
+	}
+	return gs, nil
+}
+
+// replayAction takes a recorded action again.
+func (e *GameEngine) replayAction(gs *models.GameState, action models.ReplayAction) error {
+	spell := gs.GetSpellByID(action.SpellID)
+	switch action.Type {
+	case models.ReplayCast, models.ReplayUpgrade, models.ReplaySpecialize:
+		if spell == nil {
+			return ErrSpellNotFound
+		}
+	}
+
+	switch action.Type {
+	case models.ReplayCast:
+		return e.CastSpell(gs, spell, action.Manual)
+	case models.ReplayUpgrade:
+		return e.UpgradeSpell(gs, spell)
+	case models.ReplaySpecialize:
+		return e.Specialize(gs, spell, action.Spec)
+	case models.ReplayCreateRitual:
+		_, err := e.CreateRitual(gs, action.SpellIDs)
+		return err
+	case models.ReplayToggleRitual:
+		return e.ToggleRitual(gs, action.RitualID)
+	case models.ReplayRemoveRitual:
+		return e.RemoveRitual(gs, action.RitualID)
+	case models.ReplayResetRituals:
+		e.ResetRituals(gs)
+	case models.ReplayFloorEvent:
+		if !e.ChooseFloorEvent(gs, action.Choice) {
+			return ErrReplayActionFailed
+		}
+	case models.ReplayDismissFloorEvent:
+		if !e.DismissFloorEvent(gs) {
+			return ErrReplayActionFailed
+		}
+	case models.ReplayPrestige:
+		if !e.ProcessPrestige(gs) {
+			return ErrReplayActionFailed
+		}
+	case models.ReplaySettingsEdit:
+		if action.Settings == nil {
+			return ErrInvalidReplay
+		}
+		return applyReplaySettings(gs, action.Settings)
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidReplay, action.Type)
+	}
+	return nil
+}
+
+// StateHash hashes everything about gs that playing can change, leaving
+// out save bookkeeping (ID, version, save times). Two games with the same
+// hash are in the same state.
+func StateHash(gs *models.GameState) string {
+	clone := gs.Clone()
+	if clone == nil {
+		return ""
+	}
+	clone.ID = primitive.NilObjectID
+	clone.SavedAt = time.Time{}
+	clone.Version = 0
+	if clone.Session != nil {
+		clone.Session.LastSavedAt = time.Time{}
+	}
+	data, _ := json.Marshal(clone)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// newRecordedGame starts recording a new game with the base spells.
+func newRecordedGame(seed int64) (*GameEngine, *models.GameState) {
+	gs := models.NewGameState("player", 0)
+	for _, spell := range game.GetBaseSpells() {
+		gs.AddSpell(spell)
+	}
+	e := NewGameEngine()
+	e.Record(gs, seed)
+	return e, gs
+}
+
+// countActions counts replay's actions by type.
+func countActions(replay *models.Replay) map[models.ReplayActionType]int {
+	counts := map[models.ReplayActionType]int{}
+	for _, action := range replay.Actions {
+		counts[action.Type]++
+	}
+	return counts
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	e, gs := newRecordedGame(7)
+	// Auto-cast off so mana builds up for upgrades; recorded as a settings edit
+	gs.Session.AutoCastEnabled = false
+
+	var saves []*models.Replay
+	var savedHashes []string
+	for step := 1; step <= 2400; step++ {
+		// Steps that aren't a whole tick carry the rest over
+		e.Advance(gs, 250*time.Millisecond)
+		spell := gs.Spells[0]
+		if !gs.Tower.IsSigilCharged() {
+			_ = e.CastSpell(gs, spell, true)
+		} else {
+			_ = e.UpgradeSpell(gs, spell)
+		}
+		if step%400 == 0 {
+			e.RecordSave(gs, nil)
+			saves = append(saves, e.Recording(gs))
+			savedHashes = append(savedHashes, StateHash(gs))
+		}
+	}
+
+	final := saves[len(saves)-1]
+	counts := countActions(final)
+	for _, action := range []models.ReplayActionType{models.ReplayCast, models.ReplayUpgrade, models.ReplaySettingsEdit} {
+		if counts[action] == 0 {
+			t.Fatalf("recorded no %s actions: %v", action, counts)
+		}
+	}
+
+	// Every save's recording replays to the state saved
+	for i, replay := range saves {
+		replayed, err := Replay(replay)
+		if err != nil {
+			t.Fatalf("save %d: Replay: %v", i, err)
+		}
+		if got := StateHash(replayed); got != savedHashes[i] {
+			t.Errorf("save %d: replayed state %.12s, saved %.12s", i, got, savedHashes[i])
+		}
+	}
+}
+
+func TestReplayDetectsMissingActions(t *testing.T) {
+	e, gs := newRecordedGame(7)
+	gs.Session.AutoCastEnabled = false
+	for step := 0; step < 600; step++ {
+		e.Advance(gs, 100*time.Millisecond)
+		if !gs.Tower.IsSigilCharged() {
+			_ = e.CastSpell(gs, gs.Spells[0], true)
+		}
+	}
+	replay := e.Recording(gs)
+
+	// Drop the last cast, as if the player never made it
+	for i := len(replay.Actions) - 1; i >= 0; i-- {
+		if replay.Actions[i].Type == models.ReplayCast {
+			replay.Actions = append(replay.Actions[:i], replay.Actions[i+1:]...)
+			break
+		}
+	}
+	if _, err := Replay(replay); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("Replay without a cast = %v, want ErrReplayMismatch", err)
+	}
+}
@@ -47,6 +47,8 @@ func (e *GameEngine) CreateRitual(gs *models.GameState, spellIDs []string) (*mod
 		}
 	}
 
+	e.record(gs, models.ReplayAction{Type: models.ReplayCreateRitual, SpellIDs: spellIDs})
+
 	// Compute ritual combo effects (v1.2.0)
 	comboInfo := game.ComputeRitualCombo(spellIDs)
 
@@ -63,6 +65,10 @@ func (e *GameEngine) CreateRitual(gs *models.GameState, spellIDs []string) (*mod
 	gs.Rituals = append(gs.Rituals, ritual)
 	gs.ActiveRitualCount = len(gs.GetActiveRituals())
 
+	e.emit(gs, game.EventNewRitual(ritual.ID, spellIDs).
+		WithData("name", ritual.Name).
+		WithData("signature", comboInfo.SignatureName))
+
 	return ritual, nil
 }
 
@@ -70,6 +76,7 @@ func (e *GameEngine) CreateRitual(gs *models.GameState, spellIDs []string) (*mod
 func (e *GameEngine) RemoveRitual(gs *models.GameState, ritualID string) error {
 	for i, ritual := range gs.Rituals {
 		if ritual.ID == ritualID {
+			e.record(gs, models.ReplayAction{Type: models.ReplayRemoveRitual, RitualID: ritualID})
 			// Remove from slice
 			gs.Rituals = append(gs.Rituals[:i], gs.Rituals[i+1:]...)
 			gs.ActiveRitualCount = len(gs.GetActiveRituals())
@@ -82,6 +89,7 @@ func (e *GameEngine) RemoveRitual(gs *models.GameState, ritualID string) error {
 // ResetRituals removes all rituals from the save slot.
 // This is a safety valve to prevent players from permanently locking their ritual capacity.
 func (e *GameEngine) ResetRituals(gs *models.GameState) {
+	e.record(gs, models.ReplayAction{Type: models.ReplayResetRituals})
 	gs.Rituals = []*models.Ritual{}
 	gs.ActiveRitualCount = 0
 }
@@ -90,8 +98,10 @@ func (e *GameEngine) ResetRituals(gs *models.GameState) {
 func (e *GameEngine) ToggleRitual(gs *models.GameState, ritualID string) error {
 	for _, ritual := range gs.Rituals {
 		if ritual.ID == ritualID {
+			e.record(gs, models.ReplayAction{Type: models.ReplayToggleRitual, RitualID: ritualID})
 			ritual.IsActive = !ritual.IsActive
 			gs.ActiveRitualCount = len(gs.GetActiveRituals())
+			e.emit(gs, game.EventRitualToggled(ritual.ID, ritual.IsActive))
 			return nil
 		}
 	}
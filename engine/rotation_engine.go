@@ -20,9 +20,68 @@ func (e *GameEngine) ProcessRotation(gs *models.GameState) int {
 	rotation := gs.Session.Rotation
 
 	// Calculate mana reserve threshold
+	availableMana := rotationAvailableMana(gs, rotation)
+
+	for _, group := range e.rotationPriorityGroups(gs, rotation) {
+		for _, config := range group {
+			spell := gs.GetSpellByID(config.SpellID)
+			if spell == nil || !spell.IsReady() {
+				continue
+			}
+
+			// Check rotation condition
+			if !e.checkRotationConfig(gs, spell, config, availableMana) {
+				e.traceRotation(spell, rotationBlockedCondition, 0, 0)
+				continue
+			}
+
+			// Try to cast
+			manaBefore, sigilBefore := gs.Tower.CurrentMana, gs.Tower.SigilCharge
+			if err := e.CastSpell(gs, spell, false); err == ErrInsufficientMana {
+				e.traceRotation(spell, rotationBlockedMana, 0, 0)
+				skipped++
+				continue
+			} else if err == ErrNeedsSpecialization {
+				e.traceRotation(spell, rotationBlockedSpec, 0, 0)
+				continue
+			} else if err == ErrSpellDisabled {
+				e.traceRotation(spell, rotationBlockedChallenge, 0, 0)
+				continue
+			} else if err != nil {
+				// Skip if other error (cooldown, etc.)
+				continue
+			}
+			e.traceRotation(spell, rotationCast, manaBefore, sigilBefore)
+
+			// If OptimizeForIdle is enabled, only cast one spell per tick
+			// This spreads out casts for sustained DPS rather than burst
+			if rotation.OptimizeForIdle {
+				gs.Session.AutoCastSkipCount += skipped
+				return skipped
+			}
+		}
+	}
+
+	gs.Session.AutoCastSkipCount += skipped
+	return skipped
+}
+
+// traceRotation reports a rotation decision to the dry-run observer, if any.
+func (e *GameEngine) traceRotation(spell *models.Spell, outcome rotationOutcome, manaBefore, sigilBefore float64) {
+	if e.rotationTrace != nil {
+		e.rotationTrace(spell, outcome, manaBefore, sigilBefore)
+	}
+}
+
+// rotationAvailableMana returns the mana left after the rotation's reserve.
+func rotationAvailableMana(gs *models.GameState, rotation *models.SpellRotation) float64 {
 	manaReserve := gs.Tower.MaxMana * rotation.ManaThreshold
-	availableMana := gs.Tower.CurrentMana - manaReserve
+	return gs.Tower.CurrentMana - manaReserve
+}
 
+// rotationPriorityGroups returns enabled rotation entries grouped by priority
+// (high, medium, low) in the order the rotation will try them.
+func (e *GameEngine) rotationPriorityGroups(gs *models.GameState, rotation *models.SpellRotation) [][]models.RotationSpellConfig {
 	// Group spells by priority
 	highPriority := []models.RotationSpellConfig{}
 	mediumPriority := []models.RotationSpellConfig{}
@@ -46,10 +105,11 @@ func (e *GameEngine) ProcessRotation(gs *models.GameState) int {
 	// Process high priority first, then medium, then low (filler)
 	priorityGroups := [][]models.RotationSpellConfig{highPriority, mediumPriority, lowPriority}
 
-	for _, group := range priorityGroups {
-		if rotation.CooldownWeaving {
-			// Sort by cooldown remaining (cast spell with shortest CD first)
-			sort.Slice(group, func(i, j int) bool {
+	if rotation.CooldownWeaving {
+		for _, group := range priorityGroups {
+			// Sort by cooldown remaining (cast spell with shortest CD first).
+			// Stable so equal cooldowns keep the configured order.
+			sort.SliceStable(group, func(i, j int) bool {
 				spellI := gs.GetSpellByID(group[i].SpellID)
 				spellJ := gs.GetSpellByID(group[j].SpellID)
 				if spellI == nil || spellJ == nil {
@@ -58,38 +118,70 @@ func (e *GameEngine) ProcessRotation(gs *models.GameState) int {
 				return spellI.CooldownRemainingMs < spellJ.CooldownRemainingMs
 			})
 		}
+	}
 
+	return priorityGroups
+}
+
+// RotationCastPreview describes which spell the rotation would cast next.
+type RotationCastPreview struct {
+	SpellID string
+	WaitMs  int64  // 0 if it would cast on the next tick
+	Reason  string // Why nothing can cast right now (empty if SpellID is set)
+}
+
+// PreviewNextRotationCast predicts the next rotation cast without changing state.
+// If nothing can cast now, it reports the soonest spell coming off cooldown whose
+// condition and mana currently pass, or why the rotation is stalled.
+func (e *GameEngine) PreviewNextRotationCast(gs *models.GameState) RotationCastPreview {
+	rotation := gs.Session.Rotation
+	if rotation == nil || !rotation.Enabled {
+		return RotationCastPreview{Reason: "Rotation disabled (legacy auto-cast in use)"}
+	}
+
+	availableMana := rotationAvailableMana(gs, rotation)
+	var waiting *RotationCastPreview
+	blockedReason := "No enabled spells in rotation"
+
+	for _, group := range e.rotationPriorityGroups(gs, rotation) {
 		for _, config := range group {
 			spell := gs.GetSpellByID(config.SpellID)
-			if spell == nil || !spell.IsReady() {
+			if spell == nil {
 				continue
 			}
-
-			// Check rotation condition
-			if !e.checkRotationCondition(gs, spell, config.Condition, availableMana) {
+			if _, needs := spell.NeedsSpecialization(); needs {
+				blockedReason = spell.Name + " needs a specialization"
 				continue
 			}
-
-			// Try to cast
-			if err := e.CastSpell(gs, spell, false); err == ErrInsufficientMana {
-				skipped++
+			if !e.checkRotationConfig(gs, spell, config, availableMana) {
+				blockedReason = spell.Name + " blocked by its condition"
 				continue
-			} else if err != nil {
-				// Skip if other error (cooldown, needs specialization, etc.)
+			}
+			if gs.Tower.CurrentMana < e.CalculateEffectiveSpellManaCost(gs, spell, false) {
+				blockedReason = spell.Name + " needs more mana"
 				continue
 			}
-
-			// If OptimizeForIdle is enabled, only cast one spell per tick
-			// This spreads out casts for sustained DPS rather than burst
-			if rotation.OptimizeForIdle {
-				gs.Session.AutoCastSkipCount += skipped
-				return skipped
+			if spell.IsReady() {
+				return RotationCastPreview{SpellID: spell.ID}
+			}
+			if waiting == nil || spell.CooldownRemainingMs < waiting.WaitMs {
+				waiting = &RotationCastPreview{SpellID: spell.ID, WaitMs: spell.CooldownRemainingMs}
 			}
 		}
 	}
 
-	gs.Session.AutoCastSkipCount += skipped
-	return skipped
+	if waiting != nil {
+		return *waiting
+	}
+	return RotationCastPreview{Reason: blockedReason}
+}
+
+// checkRotationConfig evaluates a rotation entry's condition, including custom expressions.
+func (e *GameEngine) checkRotationConfig(gs *models.GameState, spell *models.Spell, config models.RotationSpellConfig, availableMana float64) bool {
+	if config.Condition == models.RotationConditionExpression {
+		return e.EvaluateConditionExpr(gs, spell, config.Expression, availableMana)
+	}
+	return e.checkRotationCondition(gs, spell, config.Condition, availableMana)
 }
 
 // checkRotationCondition evaluates advanced rotation conditions.
@@ -105,10 +197,10 @@ func (e *GameEngine) checkRotationCondition(gs *models.GameState, spell *models.
 		return gs.Tower.CurrentMana >= gs.Tower.MaxMana*0.75
 
 	case models.RotationConditionSigilNotFull:
-		return !gs.Tower.IsSigilCharged()
+		return sigilNeedsDamage(gs)
 
 	case models.RotationConditionSynergyActive:
-		return gs.HasActiveSynergy()
+		return gs.HasActiveSynergyAt(e.nowMs())
 
 	case models.RotationConditionManaEfficient:
 		// Only cast if current mana / spell cost > 2.0 (efficient)
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// Rotation optimizer: greedy coordinate ascent over each spell's priority and
+// condition, scoring candidates with the same dry-run simulation used by the
+// rotation preview (so rituals, synergies and resonance are all accounted for).
+
+// OptimizeObjective selects what the optimizer maximizes.
+type OptimizeObjective string
+
+const (
+	ObjectiveSigilPerSecond OptimizeObjective = "sigil"  // Sigil charge per second
+	ObjectiveFloorsPerHour  OptimizeObjective = "floors" // Floors climbed per hour
+)
+
+// OptimizeObjectiveLabels maps objectives to display labels.
+var OptimizeObjectiveLabels = map[OptimizeObjective]string{
+	ObjectiveSigilPerSecond: "sigil/s",
+	ObjectiveFloorsPerHour:  "floors/hr",
+}
+
+// ParseOptimizeObjective parses an objective name, defaulting to sigil per second.
+func ParseOptimizeObjective(s string) (OptimizeObjective, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "sigil", "sigil_dps", "dps":
+		return ObjectiveSigilPerSecond, nil
+	case "floors", "floors_per_hour", "fph":
+		return ObjectiveFloorsPerHour, nil
+	default:
+		return "", fmt.Errorf("unknown objective %q (use sigil or floors)", s)
+	}
+}
+
+const (
+	// Simulation settings per objective. Floors need a longer window to see climbs.
+	optimizeSigilHorizon  = 2 * time.Minute
+	optimizeFloorsHorizon = 10 * time.Minute
+	optimizeStep          = 250 * time.Millisecond
+	optimizeMaxPasses     = 3
+)
+
+// optimizeConditions are the conditions the optimizer tries for each spell.
+// Filler/high-priority are covered by priority tiers and are not searched.
+var optimizeConditions = []models.RotationCondition{
+	models.RotationConditionAlways,
+	models.RotationConditionManaAbove50,
+	models.RotationConditionManaAbove75,
+	models.RotationConditionSigilNotFull,
+	models.RotationConditionSynergyActive,
+	models.RotationConditionManaEfficient,
+	models.RotationConditionDuringSynergy,
+	models.RotationConditionSigilAlmostFull,
+}
+
+var optimizePriorities = []models.RotationPriority{
+	models.PriorityHigh,
+	models.PriorityMedium,
+	models.PriorityLow,
+}
+
+// OptimizeResult compares the current rotation with the optimized one.
+type OptimizeResult struct {
+	Objective      OptimizeObjective
+	Rotation       *models.SpellRotation // Optimized rotation (enabled)
+	Current        *RotationPlan
+	Optimized      *RotationPlan
+	CurrentScore   float64
+	OptimizedScore float64
+	Evaluations    int
+}
+
+// Improvement returns the relative score improvement (0.1 = +10%).
+func (r *OptimizeResult) Improvement() float64 {
+	if r.CurrentScore <= 0 {
+		if r.OptimizedScore > 0 {
+			return 1
+		}
+		return 0
+	}
+	return r.OptimizedScore/r.CurrentScore - 1
+}
+
+// OptimizeRotation searches priority and condition assignments for every
+// unlocked spell to maximize objective, simulating from the engine's
+// current time. gs is not modified.
+func (e *GameEngine) OptimizeRotation(gs *models.GameState, objective OptimizeObjective) *OptimizeResult {
+	return OptimizeRotationAt(gs, e.now(), objective)
+}
+
+// OptimizeRotationAt is OptimizeRotation simulating from start. It touches
+// no engine, so it can search in the background on a snapshot of a game
+// that goes on running; read start under the game's lock.
+func OptimizeRotationAt(gs *models.GameState, start time.Time, objective OptimizeObjective) *OptimizeResult {
+	horizon := optimizeSigilHorizon
+	if objective == ObjectiveFloorsPerHour {
+		horizon = optimizeFloorsHorizon
+	}
+
+	base := gs.Clone()
+	if base == nil {
+		return &OptimizeResult{Objective: objective}
+	}
+	base.EnsureRotation()
+	current := cloneRotation(base.Session.Rotation)
+	if !current.Enabled || len(current.Spells) == 0 {
+		// The live game is using legacy auto-cast; compare against its equivalent
+		legacy := base.Clone()
+		legacy.Session.Rotation = nil
+		legacy.ConvertAutoCastToRotation()
+		current = legacy.Session.Rotation
+	}
+
+	evaluations := 0
+	evaluate := func(rotation *models.SpellRotation) (float64, *RotationPlan) {
+		evaluations++
+		plan := simulateRotation(base.Clone(), rotation, start, horizon, optimizeStep)
+		return scorePlan(plan, objective), plan
+	}
+
+	currentScore, currentPlan := evaluate(current)
+
+	// Start from the current rotation, with any unlocked spells it's missing
+	// added as disabled entries so the search can switch them on.
+	best := cloneRotation(current)
+	for _, spell := range base.Spells {
+		if rotationIndex(best, spell.ID) < 0 {
+			best.Spells = append(best.Spells, models.RotationSpellConfig{
+				SpellID:   spell.ID,
+				Priority:  models.PriorityMedium,
+				Condition: models.RotationConditionAlways,
+				Enabled:   false,
+			})
+		}
+	}
+	bestScore, bestPlan := evaluate(best)
+
+	// Also consider the simple "everything, always" rotation as a starting point
+	simple := cloneRotation(best)
+	for i := range simple.Spells {
+		simple.Spells[i] = models.RotationSpellConfig{
+			SpellID:   simple.Spells[i].SpellID,
+			Priority:  models.PriorityMedium,
+			Condition: models.RotationConditionAlways,
+			Enabled:   true,
+		}
+	}
+	if score, plan := evaluate(simple); score > bestScore {
+		best, bestScore, bestPlan = simple, score, plan
+	}
+
+	for pass := 0; pass < optimizeMaxPasses; pass++ {
+		improved := false
+		for i := range best.Spells {
+			for _, candidate := range spellAlternatives(best.Spells[i]) {
+				trial := cloneRotation(best)
+				trial.Spells[i] = candidate
+				if score, plan := evaluate(trial); score > bestScore {
+					best, bestScore, bestPlan = trial, score, plan
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	// Keep the current rotation if nothing beat it
+	if bestScore <= currentScore {
+		best, bestScore, bestPlan = cloneRotation(current), currentScore, currentPlan
+	}
+	best.Enabled = true
+
+	return &OptimizeResult{
+		Objective:      objective,
+		Rotation:       best,
+		Current:        currentPlan,
+		Optimized:      bestPlan,
+		CurrentScore:   currentScore,
+		OptimizedScore: bestScore,
+		Evaluations:    evaluations,
+	}
+}
+
+// scorePlan converts a plan into the objective's units.
+func scorePlan(plan *RotationPlan, objective OptimizeObjective) float64 {
+	if objective == ObjectiveFloorsPerHour {
+		hours := float64(plan.HorizonMs) / float64(time.Hour.Milliseconds())
+		if hours <= 0 {
+			return 0
+		}
+		return plan.FloorProgress() / hours
+	}
+	return plan.SigilPerSecond()
+}
+
+// spellAlternatives lists every other setting the optimizer tries for one entry.
+func spellAlternatives(config models.RotationSpellConfig) []models.RotationSpellConfig {
+	alternatives := []models.RotationSpellConfig{}
+	if config.Enabled {
+		off := config
+		off.Enabled = false
+		alternatives = append(alternatives, off)
+	}
+	for _, priority := range optimizePriorities {
+		for _, cond := range optimizeConditions {
+			if config.Enabled && priority == config.Priority && cond == config.Condition {
+				continue
+			}
+			alternatives = append(alternatives, models.RotationSpellConfig{
+				SpellID:   config.SpellID,
+				Priority:  priority,
+				Condition: cond,
+				Enabled:   true,
+			})
+		}
+	}
+	return alternatives
+}
+
+func cloneRotation(rotation *models.SpellRotation) *models.SpellRotation {
+	clone := *rotation
+	clone.Spells = append([]models.RotationSpellConfig(nil), rotation.Spells...)
+	return &clone
+}
+
+func rotationIndex(rotation *models.SpellRotation, spellID string) int {
+	for i := range rotation.Spells {
+		if rotation.Spells[i].SpellID == spellID {
+			return i
+		}
+	}
+	return -1
+}
+
+// FormatOptimizeResult returns a plain-text comparison of the current and
+// optimized rotations.
+func FormatOptimizeResult(gs *models.GameState, result *OptimizeResult) string {
+	var b strings.Builder
+	label := OptimizeObjectiveLabels[result.Objective]
+
+	fmt.Fprintf(&b, "Objective: %s (%d simulations)\n\n", label, result.Evaluations)
+	fmt.Fprintf(&b, "%-18s %12s %12s\n", "", "Current", "Optimized")
+	fmt.Fprintf(&b, "%-18s %12s %12s\n", label,
+		utils.FormatNumber(result.CurrentScore), utils.FormatNumber(result.OptimizedScore))
+	if result.Current != nil && result.Optimized != nil {
+		if result.Objective != ObjectiveSigilPerSecond {
+			fmt.Fprintf(&b, "%-18s %12s %12s\n", "sigil/s",
+				utils.FormatNumber(result.Current.SigilPerSecond()), utils.FormatNumber(result.Optimized.SigilPerSecond()))
+		}
+		fmt.Fprintf(&b, "%-18s %12d %12d\n", "Casts", len(result.Current.Casts), len(result.Optimized.Casts))
+		fmt.Fprintf(&b, "%-18s %12s %12s\n", "Mana spent",
+			utils.FormatNumber(result.Current.ManaSpent), utils.FormatNumber(result.Optimized.ManaSpent))
+		fmt.Fprintf(&b, "%-18s %12d %12d\n", "Floors climbed", result.Current.FloorsClimbed, result.Optimized.FloorsClimbed)
+	}
+	fmt.Fprintf(&b, "\nImprovement: %+.1f%%\n\nOptimized rotation:\n", result.Improvement()*100)
+
+	for i, config := range result.Rotation.Spells {
+		name := config.SpellID
+		if spell := gs.GetSpellByID(config.SpellID); spell != nil {
+			name = spell.Name
+		}
+		if !config.Enabled {
+			fmt.Fprintf(&b, "  %2d. %-16s (disabled)\n", i+1, name)
+			continue
+		}
+		fmt.Fprintf(&b, "  %2d. %-16s [%s] %s\n", i+1, name,
+			models.GetPriorityLabel(config.Priority), models.GetConditionDescription(config.Condition))
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+)
+
+// The optimizer runs in the background while the game goes on; run with
+// -race to check it doesn't read the live engine.
+func TestOptimizeRotationAtWhileGameRuns(t *testing.T) {
+	e, gs := newRecordedGame(7)
+	var mu sync.Mutex // The loop's lock
+
+	stop := make(chan struct{})
+	running := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ticks := 0; ; ticks++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			mu.Lock()
+			e.Advance(gs, ReplayTick)
+			mu.Unlock()
+			if ticks == 10 {
+				close(running)
+			}
+		}
+	}()
+
+	<-running
+	mu.Lock()
+	snapshot, start := gs.Clone(), e.Now()
+	mu.Unlock()
+	// Search in a goroutine of its own, as the TUI's commands do
+	done := make(chan *OptimizeResult)
+	go func() { done <- OptimizeRotationAt(snapshot, start, ObjectiveSigilPerSecond) }()
+	result := <-done
+	close(stop)
+	wg.Wait()
+
+	if result.Rotation == nil || result.Evaluations == 0 {
+		t.Fatalf("optimizer returned %+v", result)
+	}
+	// Same snapshot and start, same search
+	again := OptimizeRotationAt(snapshot, start, ObjectiveSigilPerSecond)
+	if again.OptimizedScore != result.OptimizedScore {
+		t.Errorf("optimized score %v, then %v for the same snapshot", result.OptimizedScore, again.OptimizedScore)
+	}
+}
@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// Rotation dry-run: simulate the rotation on a copy of the game state to
+// predict upcoming casts without touching the live save.
+
+const (
+	// DefaultPlanHorizon is how far ahead the rotation preview looks.
+	DefaultPlanHorizon = 60 * time.Second
+	// planStep matches the UI tick rate so predictions line up with real play.
+	planStep = 100 * time.Millisecond
+	// planSeed keeps crit rolls stable between previews of the same state.
+	planSeed = 0x5eed
+)
+
+// rotationOutcome is what happened to a rotation entry on a given tick.
+type rotationOutcome int
+
+const (
+	rotationCast rotationOutcome = iota
+	rotationBlockedCondition
+	rotationBlockedMana
+	rotationBlockedSpec
+	rotationBlockedChallenge
+)
+
+// String returns a short label for a blocked outcome.
+func (o rotationOutcome) String() string {
+	switch o {
+	case rotationBlockedCondition:
+		return "condition"
+	case rotationBlockedMana:
+		return "mana"
+	case rotationBlockedSpec:
+		return "needs specialization"
+	case rotationBlockedChallenge:
+		return "challenge rule"
+	default:
+		return "cast"
+	}
+}
+
+// PlannedCast is a single predicted cast.
+type PlannedCast struct {
+	AtMs     int64 // Offset from the start of the plan
+	SpellID  string
+	Element  models.Element
+	Mana     float64 // Mana left after the cast
+	SigilPct float64 // Sigil progress after the cast (0-1)
+}
+
+// PlanSample is the predicted tower state at a point in time.
+type PlanSample struct {
+	AtMs     int64
+	ManaPct  float64
+	SigilPct float64
+}
+
+// BlockedCast summarizes how long a ready spell was held back and why.
+type BlockedCast struct {
+	SpellID   string
+	Reason    string
+	FirstAtMs int64
+	Ticks     int
+	BlockedMs int64
+}
+
+// RotationPlan is the result of a rotation dry-run.
+type RotationPlan struct {
+	HorizonMs     int64
+	Casts         []PlannedCast
+	Samples       []PlanSample // One per second
+	Blocked       []BlockedCast
+	FloorsClimbed int
+	SigilGained   float64 // Total sigil charge from casts
+	ManaSpent     float64
+	FinalFloor    int
+	FinalMana     float64
+	FinalSigilPct float64
+}
+
+// SigilPerSecond returns average sigil charge gained per second.
+func (p *RotationPlan) SigilPerSecond() float64 {
+	if p.HorizonMs <= 0 {
+		return 0
+	}
+	return p.SigilGained / (float64(p.HorizonMs) / 1000)
+}
+
+// FloorProgress returns floors climbed plus sigil progress on the last floor,
+// a smooth measure of how far the plan got.
+func (p *RotationPlan) FloorProgress() float64 {
+	return float64(p.FloorsClimbed) + p.FinalSigilPct
+}
+
+// newSimulationEngine creates an engine with a virtual clock and seeded RNG
+// and no event handlers, for dry-runs.
+func newSimulationEngine(start time.Time, seed uint64) (*GameEngine, *time.Time) {
+	sim := NewGameEngine()
+	clock := start
+	sim.clock = func() time.Time { return clock }
+	sim.rng = rand.New(rand.NewPCG(seed, seed))
+	return sim, &clock
+}
+
+// PlanRotation predicts the next horizon of casts for rotation without
+// mutating gs. The rotation is simulated as if it were enabled.
+func (e *GameEngine) PlanRotation(gs *models.GameState, rotation *models.SpellRotation, horizon time.Duration) *RotationPlan {
+	return simulateRotation(gs.Clone(), rotation, e.now(), horizon, planStep)
+}
+
+// simulateRotation runs rotation on sim (a disposable copy of the game state)
+// in fixed steps from start and records the resulting plan. It runs on an
+// engine of its own, so it may run alongside the live game.
+func simulateRotation(sim *models.GameState, rotation *models.SpellRotation, start time.Time, horizon, step time.Duration) *RotationPlan {
+	plan := &RotationPlan{HorizonMs: horizon.Milliseconds()}
+	if sim == nil || rotation == nil {
+		return plan
+	}
+	simRotation := *rotation
+	simRotation.Enabled = true
+	simRotation.Spells = append([]models.RotationSpellConfig(nil), rotation.Spells...)
+	sim.Session.Rotation = &simRotation
+	sim.Session.AutoCastEnabled = true
+	if sim.Automation != nil {
+		// Plans show the rotation alone, without automation prestiging or spending mana
+		sim.Automation.DisableAll()
+	}
+
+	simEngine, clock := newSimulationEngine(start, planSeed)
+
+	var elapsedMs int64
+	blocked := map[string]*BlockedCast{}
+	simEngine.rotationTrace = func(spell *models.Spell, outcome rotationOutcome, manaBefore, sigilBefore float64) {
+		if outcome == rotationCast {
+			plan.Casts = append(plan.Casts, PlannedCast{
+				AtMs:     elapsedMs,
+				SpellID:  spell.ID,
+				Element:  spell.Element,
+				Mana:     sim.Tower.CurrentMana,
+				SigilPct: sim.Tower.GetSigilProgress(),
+			})
+			plan.ManaSpent += manaBefore - sim.Tower.CurrentMana
+			plan.SigilGained += sim.Tower.SigilCharge - sigilBefore
+			return
+		}
+		key := spell.ID + "|" + outcome.String()
+		entry, ok := blocked[key]
+		if !ok {
+			entry = &BlockedCast{SpellID: spell.ID, Reason: outcome.String(), FirstAtMs: elapsedMs}
+			blocked[key] = entry
+		}
+		entry.Ticks++
+		entry.BlockedMs += step.Milliseconds()
+	}
+	simEngine.OnFloorClimbed = func(int) { plan.FloorsClimbed++ }
+
+	plan.Samples = append(plan.Samples, planSample(sim, 0))
+	nextSampleMs := int64(1000)
+	for elapsedMs < plan.HorizonMs {
+		*clock = clock.Add(step)
+		elapsedMs += step.Milliseconds()
+		simEngine.Tick(sim, step)
+		if elapsedMs >= nextSampleMs {
+			plan.Samples = append(plan.Samples, planSample(sim, elapsedMs))
+			nextSampleMs += 1000
+		}
+	}
+
+	for _, entry := range blocked {
+		plan.Blocked = append(plan.Blocked, *entry)
+	}
+	sort.Slice(plan.Blocked, func(i, j int) bool {
+		if plan.Blocked[i].BlockedMs != plan.Blocked[j].BlockedMs {
+			return plan.Blocked[i].BlockedMs > plan.Blocked[j].BlockedMs
+		}
+		return plan.Blocked[i].SpellID < plan.Blocked[j].SpellID
+	})
+
+	plan.FinalFloor = sim.Tower.CurrentFloor
+	plan.FinalMana = sim.Tower.CurrentMana
+	plan.FinalSigilPct = sim.Tower.GetSigilProgress()
+	return plan
+}
+
+func planSample(gs *models.GameState, atMs int64) PlanSample {
+	manaPct := 0.0
+	if gs.Tower.MaxMana > 0 {
+		manaPct = gs.Tower.CurrentMana / gs.Tower.MaxMana
+	}
+	return PlanSample{AtMs: atMs, ManaPct: manaPct, SigilPct: gs.Tower.GetSigilProgress()}
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// recordSampleEvent accumulates sigil charge from spell casts for the sample
+// in progress.
+func (e *GameEngine) recordSampleEvent(gs *models.GameState, event game.EventData) {
+	if gs == nil || gs.Session == nil || event.Type != game.EventSpellCast {
+		return
+	}
+	charge, _ := event.Data["sigil_charge"].(float64)
+	gs.EnsureSamples(game.MaxSamples, e.nowMs()).SigilSinceSample += charge
+}
+
+// sampleTick records a sample once every SampleIntervalSec of game time.
+func (e *GameEngine) sampleTick(gs *models.GameState) {
+	nowMs := e.nowMs()
+	buf := gs.EnsureSamples(game.MaxSamples, nowMs)
+
+	intervalMs := int64(game.SampleIntervalSec * 1000)
+	elapsedMs := nowMs - buf.LastSampleMs
+	if elapsedMs < intervalMs {
+		return
+	}
+	if elapsedMs > 2*intervalMs {
+		// The game wasn't running (offline, or a loaded save); start a fresh
+		// window rather than averaging over the gap
+		buf.LastSampleMs = nowMs
+		buf.SigilSinceSample = 0
+		return
+	}
+
+	buf.Add(models.Sample{
+		AtMs:       nowMs,
+		ManaPerSec: e.CalculateManaPerSecond(gs),
+		SigilDPS:   buf.SigilSinceSample / (float64(elapsedMs) / 1000),
+		Floor:      gs.Tower.CurrentFloor,
+	})
+	buf.LastSampleMs = nowMs
+	buf.SigilSinceSample = 0
+}
@@ -2,7 +2,6 @@ package engine
 
 import (
 	"errors"
-	"math/rand/v2"
 
 	"github.com/Ltorre/ManaTTY/game"
 	"github.com/Ltorre/ManaTTY/models"
@@ -16,6 +15,8 @@ var (
 	ErrSpellNotUnlocked    = errors.New("spell not unlocked")
 	ErrSpellMaxLevel       = errors.New("spell already at max level")
 	ErrNeedsSpecialization = errors.New("spell needs specialization choice")
+	ErrNoSpecialization    = errors.New("spell has no specialization to choose")
+	ErrWrongSpecialization = errors.New("specialization isn't offered at this tier")
 )
 
 // CalculateEffectiveSpellManaCost computes the complete mana cost for a spell including all bonuses.
@@ -49,7 +50,7 @@ func (e *GameEngine) CalculateEffectiveSpellManaCost(gs *models.GameState, spell
 	}
 
 	// Apply synergy bonus if active and matching element
-	if gs.HasActiveSynergy() && gs.GetActiveSynergy() == spell.Element {
+	if gs.GetActiveSynergyAt(e.nowMs()) == spell.Element {
 		manaCost *= (1.0 - game.ElementSynergyBonus) // 20% cheaper
 	}
 
@@ -69,6 +70,11 @@ func (e *GameEngine) CastSpell(gs *models.GameState, spell *models.Spell, manual
 	resCounts := gs.GetAutoCastElementCounts()
 	hasResonance := resCounts[spell.Element] >= game.ElementalResonanceMinSpells
 
+	// Challenge rules (disabled elements, no manual casting)
+	if err := challengeCastError(gs, spell, manual); err != nil {
+		return err
+	}
+
 	// Check cooldown
 	if !spell.IsReady() {
 		return ErrSpellOnCooldown
@@ -82,6 +88,8 @@ func (e *GameEngine) CastSpell(gs *models.GameState, spell *models.Spell, manual
 		return ErrInsufficientMana
 	}
 
+	e.record(gs, models.ReplayAction{Type: models.ReplayCast, SpellID: spell.ID, Manual: manual})
+
 	// Deduct mana
 	gs.Tower.SpendMana(manaCost)
 
@@ -109,7 +117,7 @@ func (e *GameEngine) CastSpell(gs *models.GameState, spell *models.Spell, manual
 	cooldownReduction += e.GetTotalRitualCooldownReductionWithSynergies(gs)
 
 	// Apply synergy bonus to cooldown if active
-	if gs.HasActiveSynergy() && gs.GetActiveSynergy() == spell.Element {
+	if gs.GetActiveSynergyAt(e.nowMs()) == spell.Element {
 		cooldownReduction += game.ElementSynergyBonus // Additional 20% reduction
 	}
 
@@ -141,17 +149,24 @@ func (e *GameEngine) CastSpell(gs *models.GameState, spell *models.Spell, manual
 	}
 
 	// Apply Crit Chance specialization (15% chance for 2x damage)
+	crit := false
 	if spell.HasSpecialization(models.SpecCritChance) {
-		if rand.Float64() < game.SpecCritChanceBonus {
+		if e.randFloat64() < game.SpecCritChanceBonus {
 			damage *= game.SpecCritDamageMulti
+			crit = true
 		}
 	}
 
 	// Apply synergy bonus to damage if active
-	if gs.HasActiveSynergy() && gs.GetActiveSynergy() == spell.Element {
+	if gs.GetActiveSynergyAt(e.nowMs()) == spell.Element {
 		damage *= (1.0 + game.ElementSynergyBonus) // +20% damage during synergy
 	}
 
+	// Artifacts dropped by bosses
+	if bonus := artifactDamageBonus(gs, spell.Element); bonus > 0 {
+		damage *= (1.0 + bonus)
+	}
+
 	// Arcane resonance: small bonus to sigil charge for Arcane spells
 	sigilCharge := damage
 	if hasResonance && spell.Element == models.ElementArcane {
@@ -167,17 +182,42 @@ func (e *GameEngine) CastSpell(gs *models.GameState, spell *models.Spell, manual
 	if floorBuff == models.FloorEventChoiceSigilChargeRate {
 		sigilCharge *= (1.0 + game.FloorEventSigilChargeRateBonus)
 	}
-	gs.Tower.AddSigilCharge(sigilCharge)
+	if bonus := artifactSigilChargeBonus(gs); bonus > 0 {
+		sigilCharge *= (1.0 + bonus)
+	}
+	sigilCharge *= challengeSigilChargeMultiplier(gs)
+
+	// While a boss is up, damage goes to the boss instead of the sigil
+	bossDamage := 0.0
+	if gs.Boss != nil {
+		bossDamage = e.damageBoss(gs, spell, damage)
+		sigilCharge = 0
+	} else {
+		gs.Tower.AddSigilCharge(sigilCharge)
+	}
+
+	e.emit(gs, game.EventSpellCasted(spell.ID, manaCost).
+		WithData("element", string(spell.Element)).
+		WithData("damage", damage).
+		WithData("sigil_charge", sigilCharge).
+		WithData("boss_damage", bossDamage).
+		WithData("crit", crit).
+		WithData("manual", manual))
+
+	if gs.Boss != nil && gs.Boss.Defeated() {
+		e.defeatBoss(gs)
+	}
 
 	// Record for element synergy tracking
 	gs.RecordSpellCast(spell.Element)
 
 	// Check if synergy should trigger
 	if synergy := gs.CheckElementSynergy(); synergy != "" {
-		gs.ActivateSynergy(synergy, int64(game.ElementSynergyDuration*1000))
+		gs.ActivateSynergyAt(synergy, int64(game.ElementSynergyDuration*1000), e.nowMs())
 		if e.OnSynergyActivated != nil {
 			e.OnSynergyActivated(synergy)
 		}
+		e.emit(gs, game.EventSynergyStarted(string(synergy)))
 	}
 
 	return nil
@@ -270,6 +310,7 @@ func (e *GameEngine) ToggleSpellAutoCast(gs *models.GameState, spellID string) (
 	result := gs.ToggleSpellAutoCast(spellID)
 	switch result {
 	case models.AutoCastAdded:
+		e.emit(gs, game.EventSpellSlotted(spellID))
 		return true, nil
 	case models.AutoCastRemoved:
 		return false, nil
@@ -291,6 +332,7 @@ func (e *GameEngine) AddSpellToAutoCast(gs *models.GameState, spellID string) er
 	if !gs.AddSpellToAutoCast(spellID) {
 		return errors.New("no auto-cast slots available")
 	}
+	e.emit(gs, game.EventSpellSlotted(spellID))
 	return nil
 }
 
@@ -349,16 +391,49 @@ func (e *GameEngine) UpgradeSpell(gs *models.GameState, spell *models.Spell) err
 		return ErrInsufficientMana
 	}
 
+	e.record(gs, models.ReplayAction{Type: models.ReplayUpgrade, SpellID: spell.ID})
 	gs.Tower.SpendMana(cost)
 	spell.LevelUp(game.SpellMaxLevel)
 
 	if e.OnSpellUpgraded != nil {
 		e.OnSpellUpgraded(spell)
 	}
+	e.emit(gs, game.EventSpellLevelUp(spell.ID, spell.Level).WithData("mana_cost", cost))
 
 	return nil
 }
 
+// SpecializationChoices lists the specializations offered at tier.
+func SpecializationChoices(tier int) []models.SpellSpecialization {
+	if tier == 2 {
+		return []models.SpellSpecialization{models.SpecBurstDamage, models.SpecRapidCast}
+	}
+	return []models.SpellSpecialization{models.SpecCritChance, models.SpecManaEfficiency}
+}
+
+// Specialize picks spec for the milestone spell is waiting on.
+func (e *GameEngine) Specialize(gs *models.GameState, spell *models.Spell, spec models.SpellSpecialization) error {
+	tier, needs := spell.NeedsSpecialization()
+	if !needs {
+		return ErrNoSpecialization
+	}
+	offered := false
+	for _, choice := range SpecializationChoices(tier) {
+		offered = offered || choice == spec
+	}
+	if !offered {
+		return ErrWrongSpecialization
+	}
+
+	e.record(gs, models.ReplayAction{Type: models.ReplaySpecialize, SpellID: spell.ID, Spec: spec})
+	if tier == 1 {
+		spell.Tier1Spec = spec
+	} else {
+		spell.Tier2Spec = spec
+	}
+	return nil
+}
+
 // GetSpellEffectiveStats returns the effective stats for a spell at its current level.
 type SpellEffectiveStats struct {
 	ManaCost    float64
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// recordStats updates the game's stats ledger from an engine event.
+func (e *GameEngine) recordStats(gs *models.GameState, event game.EventData) {
+	if gs == nil || gs.PrestigeData == nil {
+		return
+	}
+	ledger := gs.EnsureLedger(e.now())
+	era := ledger.Current
+
+	switch event.Type {
+	case game.EventSpellCast:
+		era.Casts++
+		if crit, _ := event.Data["crit"].(bool); crit {
+			era.Crits++
+		}
+		damage, _ := event.Data["damage"].(float64)
+		if element, ok := event.Data["element"].(string); ok {
+			era.DamageByElement[models.Element(element)] += damage
+		}
+		if damage > era.HighestCast {
+			era.HighestCast = damage
+		}
+		cost, _ := event.Data["mana_cost"].(float64)
+		era.ManaOnCasts += cost
+	case game.EventLevelUp:
+		cost, _ := event.Data["mana_cost"].(float64)
+		era.ManaOnUpgrades += cost
+	case game.EventFloorClimbed:
+		cost, _ := event.Data["mana_cost"].(float64)
+		era.ManaOnFloors += cost
+		era.FloorsClimbed++
+		if floor, _ := event.Data["floor"].(int); floor > era.HighestFloor {
+			era.HighestFloor = floor
+		}
+	case game.EventPrestige:
+		ledger.StartEra(gs.PrestigeData.CurrentEra, e.now())
+	}
+}
+
+// recordFloorGate attributes elapsed time to whichever floor requirement is
+// holding the climb back: the sigil (or its floor's boss) or the mana cost.
+func (e *GameEngine) recordFloorGate(gs *models.GameState, elapsed time.Duration) {
+	era := gs.EnsureLedger(e.now()).Current
+
+	sigilProgress := gs.Tower.GetSigilProgress()
+	manaProgress := gs.Tower.GetFloorProgress()
+	sigilReady := gs.Tower.IsSigilCharged() && !bossBlocksClimb(gs)
+	manaReady := manaProgress >= 1
+
+	switch {
+	case sigilReady && manaReady:
+		// About to climb; nothing is gating
+	case !sigilReady && (manaReady || sigilProgress <= manaProgress):
+		era.SigilGatedMs += elapsed.Milliseconds()
+	default:
+		era.ManaGatedMs += elapsed.Milliseconds()
+	}
+}
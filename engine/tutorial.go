@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// Tutorial walks a new player through the core mechanics, advancing as the
+// engine reports each one being used. Progress is kept on the player.
+type Tutorial struct {
+	engine *GameEngine
+	player *models.Player
+}
+
+// NewTutorial subscribes a tutorial for player to e's events. Players whose
+// save already shows progress (from before the tutorial existed) skip it.
+func NewTutorial(e *GameEngine, player *models.Player, gs *models.GameState) *Tutorial {
+	if player.Tutorial == nil {
+		player.Tutorial = models.NewTutorialState()
+		if hasPlayed(gs) {
+			player.Tutorial.Skip()
+		}
+	}
+	t := &Tutorial{engine: e, player: player}
+	e.Subscribe(t.handleEvent)
+	return t
+}
+
+// hasPlayed reports whether gs shows progress beyond a fresh game.
+func hasPlayed(gs *models.GameState) bool {
+	if gs == nil {
+		return false
+	}
+	if gs.Tower.MaxFloorReached > 1 || gs.PrestigeData.TotalAscensions > 0 {
+		return true
+	}
+	for _, spell := range gs.Spells {
+		if spell.CastCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// State returns the player's tutorial progress.
+func (t *Tutorial) State() *models.TutorialState {
+	return t.player.Tutorial
+}
+
+// Skip hides the tutorial.
+func (t *Tutorial) Skip() {
+	t.player.Tutorial.Skip()
+}
+
+// Restart starts the tutorial over.
+func (t *Tutorial) Restart() {
+	t.player.Tutorial.Restart()
+}
+
+// handleEvent completes the step an event demonstrates, and emits an event
+// for the UI to announce it.
+func (t *Tutorial) handleEvent(event game.EventData) {
+	var step models.TutorialStep
+	switch event.Type {
+	case game.EventSpellCast:
+		if manual, _ := event.Data["manual"].(bool); !manual {
+			return
+		}
+		step = models.TutorialCast
+	case game.EventAutoCastSlotted:
+		step = models.TutorialAutoCast
+	case game.EventLevelUp:
+		step = models.TutorialUpgrade
+	case game.EventRitualCreated:
+		step = models.TutorialRitual
+	case game.EventFloorEventChosen:
+		step = models.TutorialFloorEvent
+	default:
+		return
+	}
+
+	if t.player.Tutorial.Complete(step, t.engine.now()) {
+		// Progress is the player's, so there's no game for the stats ledger
+		t.engine.emit(nil, game.EventTutorialStepDone(string(step)))
+	}
+}
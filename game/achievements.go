@@ -0,0 +1,49 @@
+package game
+
+import "github.com/Ltorre/ManaTTY/models"
+
+// DefaultAchievements returns every achievement definition in display order.
+func DefaultAchievements() []*models.AchievementDefinition {
+	return []*models.AchievementDefinition{
+		// FLOORS
+		{ID: "floor_25", Name: "First Ascent", Description: "Reach floor 25", Category: models.AchievementFloors, Stat: models.StatHighestFloor, Target: 25, RewardManaGen: 0.01},
+		{ID: "floor_100", Name: "Centurion", Description: "Reach floor 100", Category: models.AchievementFloors, Stat: models.StatHighestFloor, Target: 100, RewardManaGen: 0.02},
+		{ID: "floor_250", Name: "Sky Piercer", Description: "Reach floor 250", Category: models.AchievementFloors, Stat: models.StatHighestFloor, Target: 250, RewardManaGen: 0.03},
+		{ID: "floor_500", Name: "Tower Sovereign", Description: "Reach floor 500", Category: models.AchievementFloors, Stat: models.StatHighestFloor, Target: 500, RewardManaGen: 0.05},
+
+		// ERAS
+		{ID: "era_1", Name: "Ascendant", Description: "Prestige for the first time", Category: models.AchievementEras, Stat: models.StatAscensions, Target: 1, RewardManaGen: 0.01},
+		{ID: "era_5", Name: "Era Walker", Description: "Prestige 5 times", Category: models.AchievementEras, Stat: models.StatAscensions, Target: 5, RewardManaGen: 0.02},
+		{ID: "era_10", Name: "Timeless", Description: "Prestige 10 times", Category: models.AchievementEras, Stat: models.StatAscensions, Target: 10, RewardManaGen: 0.05},
+
+		// SPELLS
+		{ID: "casts_100", Name: "Spellslinger", Description: "Cast 100 spells", Category: models.AchievementSpells, Stat: models.StatCasts, Target: 100, RewardManaGen: 0.01},
+		{ID: "casts_1000", Name: "Arcane Adept", Description: "Cast 1,000 spells", Category: models.AchievementSpells, Stat: models.StatCasts, Target: 1000, RewardManaGen: 0.01},
+		{ID: "casts_10000", Name: "Archmage", Description: "Cast 10,000 spells", Category: models.AchievementSpells, Stat: models.StatCasts, Target: 10000, RewardManaGen: 0.02},
+		{ID: "crits_10", Name: "Lucky Strike", Description: "Land 10 critical hits", Category: models.AchievementSpells, Stat: models.StatCrits, Target: 10, RewardManaGen: 0.01},
+		{ID: "crits_100", Name: "Critical Mass", Description: "Land 100 critical hits", Category: models.AchievementSpells, Stat: models.StatCrits, Target: 100, RewardManaGen: 0.01},
+		{ID: "crits_1000", Name: "Devastator", Description: "Land 1,000 critical hits", Category: models.AchievementSpells, Stat: models.StatCrits, Target: 1000, RewardManaGen: 0.02},
+		{ID: "synergy_50", Name: "In Harmony", Description: "Trigger 50 element synergies", Category: models.AchievementSpells, Stat: models.StatElementSynergies, Target: 50, RewardManaGen: 0.01},
+
+		// RITUALS
+		{ID: "ritual_trinity", Name: "Elemental Trinity", Description: "Form the Elemental Trinity ritual", Category: models.AchievementRituals, Stat: models.SignatureRitualStat("Elemental Trinity"), Target: 1, RewardManaGen: 0.01},
+		{ID: "ritual_convergence", Name: "Convergence", Description: "Form the Convergence ritual", Category: models.AchievementRituals, Stat: models.SignatureRitualStat("Convergence"), Target: 1, RewardManaGen: 0.02},
+		{ID: "ritual_apocalypse", Name: "Apocalypse Now", Description: "Form the Apocalypse ritual", Category: models.AchievementRituals, Stat: models.SignatureRitualStat("Apocalypse"), Target: 1, RewardManaGen: 0.03},
+		{ID: "ritual_all_synergies", Name: "Grand Confluence", Description: "Have every ritual synergy active at once", Category: models.AchievementRituals, Stat: models.StatRitualSynergies, Target: float64(len(models.SynergyDefinitions)), RewardManaGen: 0.05},
+
+		// SPEED (time from the start of an era to floor 100)
+		{ID: "speed_100_4h", Name: "Swift Climber", Description: "Reach floor 100 within 4 hours of starting an era", Category: models.AchievementSpeed, Stat: models.StatFastestFloor100Ms, Target: 4 * 3600 * 1000, LowerIsBetter: true, RewardManaGen: 0.01},
+		{ID: "speed_100_1h", Name: "Speedrunner", Description: "Reach floor 100 within 1 hour of starting an era", Category: models.AchievementSpeed, Stat: models.StatFastestFloor100Ms, Target: 3600 * 1000, LowerIsBetter: true, RewardManaGen: 0.02},
+		{ID: "speed_100_15m", Name: "Blitz", Description: "Reach floor 100 within 15 minutes of starting an era", Category: models.AchievementSpeed, Stat: models.StatFastestFloor100Ms, Target: 15 * 60 * 1000, LowerIsBetter: true, RewardManaGen: 0.05},
+	}
+}
+
+// GetAchievementDefinition returns an achievement definition by ID.
+func GetAchievementDefinition(id string) *models.AchievementDefinition {
+	for _, def := range DefaultAchievements() {
+		if def.ID == id {
+			return def
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package game
+
+import "github.com/Ltorre/ManaTTY/models"
+
+// DefaultBosses returns every boss definition, in the order they guard
+// boss floors (floor 10, 20, ...). The order repeats after the last one.
+func DefaultBosses() []*models.Boss {
+	return []*models.Boss{
+		{
+			ID:                "stone_warden",
+			Name:              "Stone Warden",
+			Description:       "Enrages after 60s and starts to mend",
+			HPMultiplier:      0.8,
+			EnrageAfterMs:     60000,
+			EnrageRegenPerSec: 0.01,
+			ArtifactID:        "warden_heart",
+		},
+		{
+			ID:           "frost_wyrm",
+			Name:         "Frost Wyrm",
+			Description:  "Resists ice, weak to fire",
+			HPMultiplier: 1.0,
+			Resistances:  map[models.Element]float64{models.ElementIce: 0.5, models.ElementFire: -0.25},
+			ArtifactID:   "wyrm_scale",
+		},
+		{
+			ID:             "storm_herald",
+			Name:           "Storm Herald",
+			Description:    "Resists thunder; an arcane ward must be broken first",
+			HPMultiplier:   1.0,
+			Resistances:    map[models.Element]float64{models.ElementThunder: 0.5},
+			ShieldElement:  models.ElementArcane,
+			ShieldFraction: 0.25,
+			ArtifactID:     "herald_feather",
+		},
+		{
+			ID:                "void_arbiter",
+			Name:              "Void Arbiter",
+			Description:       "Resists arcane; a thunder ward must be broken first; enrages after 60s",
+			HPMultiplier:      1.1,
+			Resistances:       map[models.Element]float64{models.ElementArcane: 0.5},
+			ShieldElement:     models.ElementThunder,
+			ShieldFraction:    0.3,
+			EnrageAfterMs:     60000,
+			EnrageRegenPerSec: 0.01,
+			ArtifactID:        "void_lens",
+		},
+		{
+			ID:                "inferno_tyrant",
+			Name:              "Inferno Tyrant",
+			Description:       "Resists fire, weak to ice; an ice ward must be broken first; enrages after 75s",
+			HPMultiplier:      1.2,
+			Resistances:       map[models.Element]float64{models.ElementFire: 0.6, models.ElementIce: -0.25},
+			ShieldElement:     models.ElementIce,
+			ShieldFraction:    0.3,
+			EnrageAfterMs:     75000,
+			EnrageRegenPerSec: 0.015,
+			ArtifactID:        "tyrant_crown",
+		},
+	}
+}
+
+// DefaultArtifacts returns every artifact definition.
+func DefaultArtifacts() []*models.Artifact {
+	return []*models.Artifact{
+		{ID: "warden_heart", Name: "Warden's Heart", Description: "+5% mana/sec", ManaGenBonus: 0.05},
+		{ID: "wyrm_scale", Name: "Wyrm Scale", Description: "+10% fire damage", Element: models.ElementFire, ElementDamageBonus: 0.10},
+		{ID: "herald_feather", Name: "Herald's Feather", Description: "+10% arcane damage", Element: models.ElementArcane, ElementDamageBonus: 0.10},
+		{ID: "void_lens", Name: "Void Lens", Description: "+5% sigil charge", SigilChargeBonus: 0.05},
+		{ID: "tyrant_crown", Name: "Tyrant's Crown", Description: "+15% damage to bosses", BossDamageBonus: 0.15},
+	}
+}
+
+var bossOrder = DefaultBosses()
+
+var bossesByID = func() map[string]*models.Boss {
+	byID := map[string]*models.Boss{}
+	for _, boss := range bossOrder {
+		byID[boss.ID] = boss
+	}
+	return byID
+}()
+
+var artifactsByID = func() map[string]*models.Artifact {
+	byID := map[string]*models.Artifact{}
+	for _, artifact := range DefaultArtifacts() {
+		byID[artifact.ID] = artifact
+	}
+	return byID
+}()
+
+// GetBoss returns a boss definition by ID, or nil.
+func GetBoss(id string) *models.Boss {
+	return bossesByID[id]
+}
+
+// BossForFloor returns the boss guarding floor, or nil if it isn't a boss
+// floor.
+func BossForFloor(floor int) *models.Boss {
+	if !IsBossFloor(floor) {
+		return nil
+	}
+	return bossOrder[(floor/BossFloorInterval-1)%len(bossOrder)]
+}
+
+// GetArtifact returns an artifact definition by ID, or nil.
+func GetArtifact(id string) *models.Artifact {
+	return artifactsByID[id]
+}
+
+// GetArtifacts returns the definitions of ids, skipping unknown ones.
+func GetArtifacts(ids []string) []*models.Artifact {
+	artifacts := make([]*models.Artifact, 0, len(ids))
+	for _, id := range ids {
+		if artifact := GetArtifact(id); artifact != nil {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return artifacts
+}
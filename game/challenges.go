@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// ChallengeTargetFloors are the floors a weekly challenge can ask for.
+var ChallengeTargetFloors = []int{10, 15, 20}
+
+// DefaultChallengeModifiers returns every challenge modifier definition.
+// Fire is never disabled, since every run starts with only Fireball.
+func DefaultChallengeModifiers() []*models.ChallengeModifier {
+	return []*models.ChallengeModifier{
+		{ID: "no_ice", Name: "Thaw", Description: "Ice spells disabled", Group: "element", DisabledElement: models.ElementIce},
+		{ID: "no_thunder", Name: "Still Air", Description: "Thunder spells disabled", Group: "element", DisabledElement: models.ElementThunder},
+		{ID: "no_arcane", Name: "Mundane", Description: "Arcane spells disabled", Group: "element", DisabledElement: models.ElementArcane},
+		{ID: "glass_sigil", Name: "Glass Sigil", Description: "Sigil charge x2, mana generation x0.5", Group: "economy", ManaGenMultiplier: 0.5, SigilChargeMultiplier: 2},
+		{ID: "mana_flood", Name: "Mana Flood", Description: "Mana generation x2, sigil charge x0.5", Group: "economy", ManaGenMultiplier: 2, SigilChargeMultiplier: 0.5},
+		{ID: "no_manual", Name: "Hands Off", Description: "No manual casting", Group: "casting", NoManualCasting: true},
+		{ID: "restless_tower", Name: "Restless Tower", Description: "Floor events every 5 floors", Group: "events", FloorEventInterval: 5},
+	}
+}
+
+var challengeModifiersByID = func() map[string]*models.ChallengeModifier {
+	byID := map[string]*models.ChallengeModifier{}
+	for _, mod := range DefaultChallengeModifiers() {
+		byID[mod.ID] = mod
+	}
+	return byID
+}()
+
+// GetChallengeModifier returns a modifier definition by ID, or nil.
+func GetChallengeModifier(id string) *models.ChallengeModifier {
+	return challengeModifiersByID[id]
+}
+
+// GetChallengeModifiers returns the definitions of ids, skipping unknown ones.
+func GetChallengeModifiers(ids []string) []*models.ChallengeModifier {
+	mods := make([]*models.ChallengeModifier, 0, len(ids))
+	for _, id := range ids {
+		if mod := GetChallengeModifier(id); mod != nil {
+			mods = append(mods, mod)
+		}
+	}
+	return mods
+}
+
+// WeeklyChallenge returns the challenge for the ISO week containing t. It
+// only depends on the week, so everyone gets the same one.
+func WeeklyChallenge(t time.Time) models.Challenge {
+	year, week := t.UTC().ISOWeek()
+	id := fmt.Sprintf("%d-W%02d", year, week)
+	h := fnv.New64a()
+	h.Write([]byte("manatty-weekly-" + id))
+	return NewChallenge(id, int64(h.Sum64()>>1))
+}
+
+// NewChallenge derives a challenge's modifiers and target floor from seed.
+func NewChallenge(id string, seed int64) models.Challenge {
+	// Draws use Uint64 directly, whose output is fixed for a PCG seed
+	src := rand.NewPCG(uint64(seed), 0)
+	pick := func(n int) int { return int(src.Uint64() % uint64(n)) }
+
+	c := models.Challenge{ID: id, Seed: seed}
+	pool := DefaultChallengeModifiers()
+	groups := map[string]bool{}
+	for len(c.Modifiers) < ChallengeModifierCount && len(pool) > 0 {
+		i := pick(len(pool))
+		mod := pool[i]
+		pool = append(pool[:i], pool[i+1:]...)
+		if groups[mod.Group] {
+			continue
+		}
+		groups[mod.Group] = true
+		c.Modifiers = append(c.Modifiers, mod.ID)
+	}
+	c.TargetFloor = ChallengeTargetFloors[pick(len(ChallengeTargetFloors))]
+	return c
+}
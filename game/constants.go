@@ -78,6 +78,13 @@ const (
 	// UI
 	ProgressBarWidth = 20 // Characters in progress bar
 
+	// Time-series sampler (Graphs view and tower sparklines)
+	SampleIntervalSec = 5   // Seconds between samples
+	MaxSamples        = 360 // Samples kept (30 minutes at the default interval)
+
+	// Event log
+	EventLogSize = 500 // Engine events kept for the event log view
+
 	// Floor Events (Lightweight)
 	// Every FloorEventIntervalFloors floors, a timed choice appears.
 	// If not chosen within FloorEventTimeoutMs, it vanishes with no bonus.
@@ -87,4 +94,17 @@ const (
 	FloorEventManaGenBonus         = 0.10 // +10% mana/sec for duration
 	FloorEventSigilChargeRateBonus = 0.10 // +10% sigil charge for duration
 	FloorEventCooldownReduction    = 0.10 // -10% spell cooldown for duration
+
+	// Challenges
+	ChallengeModifierCount = 2 // Modifiers drawn for each weekly challenge
+
+	// Boss Floors
+	// Every BossFloorInterval floors, a charged sigil summons a boss that
+	// must be beaten within BossTimeLimitMs. Failing drains the sigil.
+	BossFloorInterval   = 10
+	BossTimeLimitMs     = int64(2 * 60 * 1000) // 2 minutes
+	BossHPFraction      = 0.10                 // Boss HP as a fraction of the floor's sigil requirement
+	BossSpellXPPerFloor = 20.0                 // Spell XP per boss floor, shared by damage dealt
+	SpellXPBase         = 100.0                // Spell XP needed for level 2
+	SpellXPExponent     = 1.5                  // Spell XP scaling per level
 )
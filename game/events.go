@@ -17,8 +17,57 @@ const (
 	EventOfflineProgress
 	EventLevelUp
 	EventAchievement
+	EventSynergyActivated
+	EventAutoCastSlotted
+	EventFloorEventChosen
+	EventFloorEventStarted
+	EventSaveFailed
+	EventChallengeCompleted
+	EventBossStarted
+	EventBossEnraged
+	EventBossDefeated
+	EventBossFailed
+	EventAutomation
+	EventTutorialStep
 )
 
+// EventNames maps events to stable names (used in logs and hooks).
+var EventNames = map[GameEvent]string{
+	EventNone:               "none",
+	EventFloorClimbed:       "floor_climbed",
+	EventSpellUnlocked:      "spell_unlocked",
+	EventSpellCast:          "spell_cast",
+	EventRitualCreated:      "ritual_created",
+	EventRitualActivated:    "ritual_activated",
+	EventRitualExpired:      "ritual_expired",
+	EventPrestige:           "prestige",
+	EventGameSaved:          "game_saved",
+	EventGameLoaded:         "game_loaded",
+	EventOfflineProgress:    "offline_progress",
+	EventLevelUp:            "level_up",
+	EventAchievement:        "achievement",
+	EventSynergyActivated:   "synergy_activated",
+	EventAutoCastSlotted:    "autocast_slotted",
+	EventFloorEventChosen:   "floor_event_chosen",
+	EventFloorEventStarted:  "floor_event_started",
+	EventSaveFailed:         "save_failed",
+	EventChallengeCompleted: "challenge_completed",
+	EventBossStarted:        "boss_started",
+	EventBossEnraged:        "boss_enraged",
+	EventBossDefeated:       "boss_defeated",
+	EventBossFailed:         "boss_failed",
+	EventAutomation:         "automation",
+	EventTutorialStep:       "tutorial_step",
+}
+
+// String returns the event's stable name.
+func (g GameEvent) String() string {
+	if name, ok := EventNames[g]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // EventData contains information about a game event.
 type EventData struct {
 	Type    GameEvent
@@ -77,3 +126,102 @@ func EventOffline(manaEarned float64, floorsClimbed int, timeOffline float64) Ev
 		WithData("floors_climbed", floorsClimbed).
 		WithData("time_offline_seconds", timeOffline)
 }
+
+func EventSpellLevelUp(spellID string, level int) EventData {
+	return NewEvent(EventLevelUp, "Spell upgraded").
+		WithData("spell_id", spellID).
+		WithData("level", level)
+}
+
+func EventRitualToggled(ritualID string, active bool) EventData {
+	return NewEvent(EventRitualActivated, "Ritual toggled").
+		WithData("ritual_id", ritualID).
+		WithData("active", active)
+}
+
+func EventSynergyStarted(element string) EventData {
+	return NewEvent(EventSynergyActivated, "Element synergy activated").
+		WithData("element", element)
+}
+
+func EventAchievementUnlocked(achievementID string, name string) EventData {
+	return NewEvent(EventAchievement, "Achievement unlocked: "+name).
+		WithData("achievement_id", achievementID).
+		WithData("name", name)
+}
+
+func EventAutomationAction(action string) EventData {
+	return NewEvent(EventAutomation, "Auto: "+action).
+		WithData("action", action)
+}
+
+func EventTutorialStepDone(step string) EventData {
+	return NewEvent(EventTutorialStep, "Tutorial step completed").
+		WithData("step", step)
+}
+
+func EventSaved(slot int) EventData {
+	return NewEvent(EventGameSaved, "Game saved").
+		WithData("slot", slot)
+}
+
+func EventSpellSlotted(spellID string) EventData {
+	return NewEvent(EventAutoCastSlotted, "Spell added to auto-cast").
+		WithData("spell_id", spellID)
+}
+
+func EventFloorBonus(floor int, choice string) EventData {
+	return NewEvent(EventFloorEventChosen, "Floor event bonus chosen").
+		WithData("floor", floor).
+		WithData("choice", choice)
+}
+
+func EventFloorEventAppeared(floor int, timeoutMs int64) EventData {
+	return NewEvent(EventFloorEventStarted, "Floor event: choose a bonus").
+		WithData("floor", floor).
+		WithData("timeout_seconds", timeoutMs/1000)
+}
+
+func EventSaveError(slot int, err error) EventData {
+	return NewEvent(EventSaveFailed, "Save failed").
+		WithData("slot", slot).
+		WithData("error", err.Error())
+}
+
+func EventChallengeDone(challengeID string, floor int, elapsedMs int64, replayHash string) EventData {
+	return NewEvent(EventChallengeCompleted, "Challenge completed").
+		WithData("challenge_id", challengeID).
+		WithData("floor", floor).
+		WithData("elapsed_ms", elapsedMs).
+		WithData("replay_hash", replayHash)
+}
+
+func EventBossAppeared(bossID string, floor int, hp float64, timeLimitMs int64) EventData {
+	return NewEvent(EventBossStarted, "A boss blocks the way").
+		WithData("boss_id", bossID).
+		WithData("floor", floor).
+		WithData("hp", hp).
+		WithData("time_limit_seconds", timeLimitMs/1000)
+}
+
+func EventBossEnrage(bossID string, floor int) EventData {
+	return NewEvent(EventBossEnraged, "The boss is enraged").
+		WithData("boss_id", bossID).
+		WithData("floor", floor)
+}
+
+func EventBossBeaten(bossID string, floor int, elapsedMs int64, spellXP float64, artifactID string) EventData {
+	return NewEvent(EventBossDefeated, "Boss defeated").
+		WithData("boss_id", bossID).
+		WithData("floor", floor).
+		WithData("elapsed_ms", elapsedMs).
+		WithData("spell_xp", spellXP).
+		WithData("artifact_id", artifactID)
+}
+
+func EventBossLost(bossID string, floor int, hpLeft float64) EventData {
+	return NewEvent(EventBossFailed, "The boss held its ground").
+		WithData("boss_id", bossID).
+		WithData("floor", floor).
+		WithData("hp_left", hpLeft)
+}
@@ -69,6 +69,28 @@ func CalculateSigilRequired(floor int) float64 {
 	return SigilBaseDamage * math.Pow(float64(floor), SigilScaleExponent) * SigilFloorFactor
 }
 
+// IsBossFloor returns true if a boss guards the climb out of floor.
+func IsBossFloor(floor int) bool {
+	return floor > 0 && floor%BossFloorInterval == 0
+}
+
+// CalculateBossHP returns the HP of a boss on floor.
+// Formula: SigilRequired(floor) * BossHPFraction * hpMultiplier
+func CalculateBossHP(floor int, hpMultiplier float64) float64 {
+	return CalculateSigilRequired(floor) * BossHPFraction * hpMultiplier
+}
+
+// CalculateBossSpellXP returns the spell XP a boss on floor grants.
+func CalculateBossSpellXP(floor int) float64 {
+	return BossSpellXPPerFloor * float64(floor)
+}
+
+// CalculateSpellXPForLevel returns the spell XP needed to level a spell up
+// from level. Formula: SpellXPBase * level^SpellXPExponent
+func CalculateSpellXPForLevel(level int) float64 {
+	return SpellXPBase * math.Pow(float64(level), SpellXPExponent)
+}
+
 // CalculateEraMultiplier returns the multiplier for a given era.
 func CalculateEraMultiplier(era int) float64 {
 	return EraMultiplierBase + (EraMultiplierPerEra * float64(era))
@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Delivery limits
+const (
+	maxResponseBytes = 64 << 10 // Response body read (and discarded) from URL hooks
+	maxOutputChars   = 200      // Command output kept in error messages
+	envPrefix        = "MANATTY_"
+)
+
+// postJSON POSTs payload to url, failing on a non-2xx response.
+func postJSON(ctx context.Context, url string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ManaTTY")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded %s", url, resp.Status)
+	}
+	return nil
+}
+
+// runCommand runs argv with the payload in its environment, failing on a
+// non-zero exit.
+func runCommand(ctx context.Context, argv []string, payload Payload) error {
+	env, err := payloadEnv(payload)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	// Don't wait on output pipes held open by a background child
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if text := strings.TrimSpace(string(output)); text != "" {
+			return fmt.Errorf("%w: %s", err, truncate(text, maxOutputChars))
+		}
+		return err
+	}
+	return nil
+}
+
+// payloadEnv returns the payload as environment variables: MANATTY_EVENT,
+// MANATTY_MESSAGE, MANATTY_TIME, MANATTY_JSON (the whole payload) and
+// MANATTY_<KEY> for each data key.
+func payloadEnv(payload Payload) ([]string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	env := []string{
+		envPrefix + "EVENT=" + payload.Event,
+		envPrefix + "MESSAGE=" + payload.Message,
+		envPrefix + "TIME=" + payload.Time.Format(time.RFC3339),
+		envPrefix + "JSON=" + string(body),
+	}
+	keys := make([]string, 0, len(payload.Data))
+	for key := range payload.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, envPrefix+strings.ToUpper(key)+"="+envValue(payload.Data[key]))
+	}
+	return env, nil
+}
+
+// envValue formats an event data value for the environment.
+func envValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// truncate shortens s to n characters, marking the cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
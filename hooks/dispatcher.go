@@ -0,0 +1,205 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// ErrUnknownEvent is returned for a hook event name the game doesn't emit.
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Delivery settings
+const (
+	queueSize  = 32          // Events waiting per hook before new ones are dropped
+	retryDelay = time.Second // Wait before the first retry, doubled after each
+)
+
+// Payload is the event delivered to a hook.
+type Payload struct {
+	Event   string                 `json:"event"`
+	Message string                 `json:"message"`
+	Time    time.Time              `json:"time"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+// hook is a configured hook with its delivery queue.
+type hook struct {
+	config.Hook
+	events map[game.GameEvent]bool
+	queue  chan Payload
+}
+
+// Dispatcher delivers engine events to hooks. Each hook has its own queue
+// and goroutine, so a slow hook delays neither the game nor other hooks.
+type Dispatcher struct {
+	hooks  []*hook
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewDispatcher starts delivering to defs. Call Attach to feed it events and
+// Close to stop it.
+func NewDispatcher(defs []config.Hook) (*Dispatcher, error) {
+	byName := make(map[string]game.GameEvent, len(game.EventNames))
+	for event, name := range game.EventNames {
+		byName[name] = event
+	}
+
+	d := &Dispatcher{}
+	for _, def := range defs {
+		h := &hook{Hook: def, events: map[game.GameEvent]bool{}, queue: make(chan Payload, queueSize)}
+		for _, name := range def.Events {
+			event, ok := byName[name]
+			if !ok || event == game.EventNone {
+				return nil, fmt.Errorf("hook %q: %w %q", def.Label(), ErrUnknownEvent, name)
+			}
+			h.events[event] = true
+		}
+		d.hooks = append(d.hooks, h)
+	}
+
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	for _, h := range d.hooks {
+		d.wg.Add(1)
+		go d.run(h)
+	}
+	return d, nil
+}
+
+// Attach subscribes the dispatcher to e's events.
+func (d *Dispatcher) Attach(e *engine.GameEngine) {
+	e.Subscribe(d.handleEvent)
+}
+
+// handleEvent queues event for every hook that wants it. It never blocks:
+// if a hook has fallen behind, the event is dropped for that hook.
+func (d *Dispatcher) handleEvent(event game.EventData) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+
+	var payload *Payload
+	for _, h := range d.hooks {
+		if !h.wants(event) {
+			continue
+		}
+		if payload == nil {
+			payload = newPayload(event)
+		}
+		select {
+		case h.queue <- *payload:
+		default:
+			utils.Log(utils.LogWarn, "hook queue full, dropping event", "hook", h.Label(), "event", event.Type)
+		}
+	}
+}
+
+// wants reports whether the hook fires for event.
+func (h *hook) wants(event game.EventData) bool {
+	if !h.events[event.Type] {
+		return false
+	}
+	if event.Type == game.EventFloorClimbed && h.EveryFloors > 0 {
+		floor, _ := event.Data["floor"].(int)
+		return floor%h.EveryFloors == 0
+	}
+	return true
+}
+
+// newPayload copies event for delivery on another goroutine.
+func newPayload(event game.EventData) *Payload {
+	data := make(map[string]interface{}, len(event.Data))
+	for key, value := range event.Data {
+		data[key] = value
+	}
+	return &Payload{
+		Event:   event.Type.String(),
+		Message: event.Message,
+		Time:    time.Now(),
+		Data:    data,
+	}
+}
+
+// run delivers h's queued events until the queue is closed.
+func (d *Dispatcher) run(h *hook) {
+	defer d.wg.Done()
+	for payload := range h.queue {
+		d.deliver(h, payload)
+	}
+}
+
+// deliver tries a hook up to its attempt limit, backing off between tries.
+func (d *Dispatcher) deliver(h *hook, payload Payload) {
+	delay := retryDelay
+	attempts := h.MaxAttempts()
+	for attempt := 1; ; attempt++ {
+		err := d.attempt(h, payload)
+		if err == nil {
+			utils.Log(utils.LogDebug, "hook delivered", "hook", h.Label(), "event", payload.Event, "attempt", attempt)
+			return
+		}
+		if attempt >= attempts || d.ctx.Err() != nil {
+			utils.Log(utils.LogWarn, "hook failed", "hook", h.Label(), "event", payload.Event, "attempts", attempt, "err", err)
+			return
+		}
+		utils.Log(utils.LogDebug, "hook failed, retrying", "hook", h.Label(), "event", payload.Event, "attempt", attempt, "err", err)
+		select {
+		case <-time.After(delay):
+		case <-d.ctx.Done():
+			return
+		}
+		delay *= 2
+	}
+}
+
+// attempt delivers payload once, within the hook's timeout.
+func (d *Dispatcher) attempt(h *hook, payload Payload) error {
+	ctx, cancel := context.WithTimeout(d.ctx, h.TimeoutDuration())
+	defer cancel()
+	if h.URL != "" {
+		return postJSON(ctx, h.URL, payload)
+	}
+	return runCommand(ctx, h.Command, payload)
+}
+
+// Close stops accepting events and waits for queued ones to be delivered.
+// Deliveries still running when ctx is done are cancelled.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, h := range d.hooks {
+			close(h.queue)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,3 @@
+// Package hooks runs user-configured webhooks and scripts when the game
+// emits events, such as a floor event appearing or a save failing.
+package hooks
@@ -0,0 +1,5 @@
+// Package lobby serves ManaTTY over SSH. Players log in with a public key
+// listed in the lobby's authorized keys file, which names the player each
+// key plays as. Every SSH session runs its own TUI; a player's sessions
+// share one running game, loaded from and saved to the shared stores.
+package lobby
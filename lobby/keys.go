@@ -0,0 +1,113 @@
+package lobby
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Files in the data directory
+const (
+	HostKeyFileName        = "lobby_host_key"
+	AuthorizedKeysFileName = "lobby_authorized_keys"
+)
+
+// Errors
+var (
+	ErrNoKeys     = errors.New("no authorized keys")
+	ErrBadKeyLine = errors.New("bad authorized key")
+	ErrUnknownKey = errors.New("unknown public key")
+)
+
+// LoadOrCreateHostKey reads the server's host key from path, generating an
+// ed25519 key there on first use.
+func LoadOrCreateHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return ssh.ParsePrivateKey(data)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(key, "manatty lobby")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(key)
+}
+
+// AuthorizedKeys maps public keys, in wire format, to the nickname of the
+// player each one logs in as.
+type AuthorizedKeys map[string]string
+
+// ParseAuthorizedKeys reads authorized_keys lines whose comment is the
+// player's nickname, such as "ssh-ed25519 AAAA... Merlin". Blank lines and
+// lines starting with # are skipped.
+func ParseAuthorizedKeys(data []byte) (AuthorizedKeys, error) {
+	keys := AuthorizedKeys{}
+	for n, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		key, nickname, _, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w on line %d: %v", ErrBadKeyLine, n+1, err)
+		}
+		if nickname == "" {
+			return nil, fmt.Errorf("%w on line %d: the comment must be the player's nickname", ErrBadKeyLine, n+1)
+		}
+		wire := string(key.Marshal())
+		if other, ok := keys[wire]; ok && other != nickname {
+			return nil, fmt.Errorf("%w on line %d: key already belongs to %s", ErrBadKeyLine, n+1, other)
+		}
+		keys[wire] = nickname
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return keys, nil
+}
+
+// LoadAuthorizedKeys reads the authorized keys file at path.
+func LoadAuthorizedKeys(path string) (AuthorizedKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s doesn't exist", ErrNoKeys, path)
+		}
+		return nil, err
+	}
+	keys, err := ParseAuthorizedKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Nickname returns the player key logs in as.
+func (k AuthorizedKeys) Nickname(key ssh.PublicKey) (string, error) {
+	nickname, ok := k[string(key.Marshal())]
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownKey, ssh.FingerprintSHA256(key))
+	}
+	return nickname, nil
+}
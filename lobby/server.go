@@ -0,0 +1,313 @@
+package lobby
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Ltorre/ManaTTY/daemon"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// nicknameExtension carries the authenticated player's nickname from the
+// key check to the sessions of the connection.
+const nicknameExtension = "manatty-nickname"
+
+// Game is a player's loaded game. All of the player's sessions share it.
+type Game struct {
+	Loop     *daemon.Loop
+	NewModel func(r *lipgloss.Renderer) tea.Model // Creates the TUI for one session, drawn with r
+}
+
+// OpenFunc loads the game of the player with the given nickname, creating
+// the player if needed.
+type OpenFunc func(nickname string) (*Game, error)
+
+// running is a game with sessions attached. Its loop runs until the last
+// session ends, then saves.
+type running struct {
+	loaded   chan struct{} // Closed once game (or err) is set
+	game     *Game
+	err      error // Why the game couldn't be loaded
+	sessions int
+	stop     context.CancelFunc
+	done     chan struct{} // Closed once the final save finished
+}
+
+// Server accepts SSH connections and runs a TUI for each session.
+type Server struct {
+	addr     string
+	keysPath string
+	open     OpenFunc
+	config   *ssh.ServerConfig
+	listener net.Listener
+
+	mu       sync.Mutex
+	closed   bool
+	games    map[string]*running // By nickname
+	programs map[*tea.Program]bool
+	conns    map[*ssh.ServerConn]bool
+	playing  sync.WaitGroup // Sessions running a TUI
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a server on addr. Logins are checked against the
+// authorized keys file at keysPath, re-read on every login so players can
+// be added while the server runs. open loads a player's game when their
+// first session starts.
+func NewServer(addr string, hostKey ssh.Signer, keysPath string, open OpenFunc) *Server {
+	s := &Server{
+		addr:     addr,
+		keysPath: keysPath,
+		open:     open,
+		games:    map[string]*running{},
+		programs: map[*tea.Program]bool{},
+		conns:    map[*ssh.ServerConn]bool{},
+	}
+	s.config = &ssh.ServerConfig{
+		PublicKeyCallback: s.authenticate,
+		ServerVersion:     "SSH-2.0-ManaTTY",
+	}
+	s.config.AddHostKey(hostKey)
+	return s
+}
+
+// Listen opens the server's address.
+func (s *Server) Listen() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.addr = listener.Addr().String()
+	return nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Serve accepts connections until Close is called.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections and ends every session, waiting up to
+// ctx for the players' games to be saved.
+func (s *Server) Close(ctx context.Context) error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	for p := range s.programs {
+		p.Quit()
+	}
+	s.mu.Unlock()
+
+	// Let TUIs save and tell their clients they're over before hanging up
+	if err := wait(ctx, &s.playing); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	if waitErr := wait(ctx, &s.wg); waitErr != nil {
+		return waitErr
+	}
+	return err
+}
+
+// wait waits for wg, giving up when ctx is done.
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Players returns the nicknames of the players with a session open.
+func (s *Server) Players() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var players []string
+	for nickname, r := range s.games {
+		if r.sessions > 0 {
+			players = append(players, nickname)
+		}
+	}
+	return players
+}
+
+// authenticate accepts keys listed in the authorized keys file, recording
+// the player they belong to.
+func (s *Server) authenticate(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	keys, err := LoadAuthorizedKeys(s.keysPath)
+	if err != nil {
+		utils.Log(utils.LogError, "can't read lobby keys", "err", err)
+		return nil, err
+	}
+	nickname, err := keys.Nickname(key)
+	if err != nil {
+		utils.Log(utils.LogInfo, "lobby login refused", "user", meta.User(), "remote", meta.RemoteAddr().String(), "err", err)
+		return nil, err
+	}
+	return &ssh.Permissions{Extensions: map[string]string{nicknameExtension: nickname}}, nil
+}
+
+// handleConn runs the SSH handshake and serves the connection's sessions.
+func (s *Server) handleConn(netConn net.Conn) {
+	conn, channels, requests, err := ssh.NewServerConn(netConn, s.config)
+	if err != nil {
+		utils.Log(utils.LogDebug, "lobby handshake failed", "remote", netConn.RemoteAddr().String(), "err", err)
+		netConn.Close()
+		return
+	}
+	defer conn.Close()
+	nickname := conn.Permissions.Extensions[nicknameExtension]
+	utils.Log(utils.LogInfo, "lobby login", "nickname", nickname, "remote", conn.RemoteAddr().String())
+
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	go ssh.DiscardRequests(requests)
+	var sessions sync.WaitGroup
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		sessions.Add(1)
+		go func() {
+			defer sessions.Done()
+			defer channel.Close()
+			s.session(nickname, channel, channelRequests)
+		}()
+	}
+	sessions.Wait()
+}
+
+// startPlaying registers a session about to run a TUI. It returns false if
+// the server is closing.
+func (s *Server) startPlaying() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.playing.Add(1)
+	return true
+}
+
+// acquire returns nickname's game, loading it and starting its loop if this
+// is the player's first session.
+func (s *Server) acquire(nickname string) (*Game, error) {
+	s.mu.Lock()
+	for {
+		r, ok := s.games[nickname]
+		if !ok {
+			break
+		}
+		if r.sessions > 0 {
+			r.sessions++
+			s.mu.Unlock()
+			// The player's first session may still be loading the game
+			<-r.loaded
+			return r.game, r.err
+		}
+		// The last session just ended; load the game again once it's saved
+		s.mu.Unlock()
+		<-r.done
+		s.mu.Lock()
+	}
+
+	// Claim the game, then load it without the lock so other players can
+	// log in meanwhile
+	r := &running{loaded: make(chan struct{}), sessions: 1, done: make(chan struct{})}
+	s.games[nickname] = r
+	s.mu.Unlock()
+
+	game, err := s.open(nickname)
+	if err != nil {
+		s.mu.Lock()
+		delete(s.games, nickname)
+		s.mu.Unlock()
+		r.err = err
+		close(r.loaded)
+		return nil, err
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	r.game, r.stop = game, stop
+	close(r.loaded)
+	go func() {
+		defer close(r.done)
+		if err := game.Loop.Run(ctx); err != nil {
+			utils.Log(utils.LogError, "lobby final save failed", "nickname", nickname, "err", err)
+		}
+		s.mu.Lock()
+		delete(s.games, nickname)
+		s.mu.Unlock()
+	}()
+	return game, nil
+}
+
+// release ends one of nickname's sessions, stopping and saving the game
+// after the last one.
+func (s *Server) release(nickname string) {
+	s.mu.Lock()
+	r := s.games[nickname]
+	r.sessions--
+	last := r.sessions == 0
+	s.mu.Unlock()
+	if last {
+		r.stop()
+		<-r.done
+	}
+}
@@ -0,0 +1,229 @@
+package lobby
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/daemon"
+	"github.com/Ltorre/ManaTTY/engine"
+)
+
+// playerModel is a stand-in TUI that shows whose game it runs and quits on
+// the first key.
+type playerModel struct {
+	nickname string
+}
+
+func (m playerModel) Init() tea.Cmd { return nil }
+
+func (m playerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m playerModel) View() string { return "playing as " + m.nickname }
+
+// newPlayerKey generates a key and its authorized keys line for nickname.
+func newPlayerKey(t *testing.T, nickname string) (ssh.Signer, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + nickname + "\n"
+	return signer, line
+}
+
+// startServer runs a lobby on a random local port for the players in
+// keys, and returns it with the nicknames of the games it opened.
+func startServer(t *testing.T, keys string) (*Server, ssh.PublicKey, func() []string) {
+	t.Helper()
+	dir := t.TempDir()
+	hostKey, err := LoadOrCreateHostKey(filepath.Join(dir, HostKeyFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keysPath := filepath.Join(dir, AuthorizedKeysFileName)
+	if err := os.WriteFile(keysPath, []byte(keys), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var opened []string
+	open := func(nickname string) (*Game, error) {
+		mu.Lock()
+		opened = append(opened, nickname)
+		mu.Unlock()
+		return &Game{
+			Loop:     daemon.NewLoop(nil, nil, engine.NewGameEngine(), nil, nil, config.DefaultConfig()),
+			NewModel: func(r *lipgloss.Renderer) tea.Model { return playerModel{nickname: nickname} },
+		}, nil
+	}
+
+	server := NewServer("127.0.0.1:0", hostKey, keysPath, open)
+	if err := server.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve() }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Close(ctx); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	})
+
+	return server, hostKey.PublicKey(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), opened...)
+	}
+}
+
+// syncBuffer collects a session's output.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// play logs in with key, waits for the TUI to show want, then quits it.
+func play(t *testing.T, addr string, hostKey ssh.PublicKey, key ssh.Signer, want string) {
+	t.Helper()
+	login(t, addr, hostKey, key, want)()
+}
+
+// login logs in with key and waits for the TUI to show want. The returned
+// function quits the TUI and waits for the session to end.
+func login(t *testing.T, addr string, hostKey ssh.PublicKey, key ssh.Signer, want string) func() {
+	t.Helper()
+	client, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            "anyone",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	var out syncBuffer
+	session.Stdout = &out
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.RequestPty("xterm-256color", 24, 80, ssh.TerminalModes{}); err != nil {
+		t.Fatalf("RequestPty: %v", err)
+	}
+	if err := session.Shell(); err != nil {
+		t.Fatalf("Shell: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("never saw %q; got %q", want, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return func() {
+		t.Helper()
+		if _, err := io.WriteString(stdin, "q"); err != nil {
+			t.Fatal(err)
+		}
+		if err := session.Wait(); err != nil {
+			t.Fatalf("session ended with %v", err)
+		}
+	}
+}
+
+func TestServerGivesEachKeyItsGame(t *testing.T) {
+	merlin, merlinLine := newPlayerKey(t, "Merlin")
+	morgana, morganaLine := newPlayerKey(t, "Morgana")
+	server, hostKey, opened := startServer(t, merlinLine+morganaLine)
+
+	play(t, server.Addr(), hostKey, merlin, "playing as Merlin")
+	play(t, server.Addr(), hostKey, morgana, "playing as Morgana")
+
+	got := opened()
+	if len(got) != 2 || got[0] != "Merlin" || got[1] != "Morgana" {
+		t.Errorf("opened games %v, want [Merlin Morgana]", got)
+	}
+}
+
+func TestServerSharesAPlayersGame(t *testing.T) {
+	merlin, merlinLine := newPlayerKey(t, "Merlin")
+	server, hostKey, opened := startServer(t, merlinLine)
+
+	quitFirst := login(t, server.Addr(), hostKey, merlin, "playing as Merlin")
+	quitSecond := login(t, server.Addr(), hostKey, merlin, "playing as Merlin")
+	if got := server.Players(); len(got) != 1 || got[0] != "Merlin" {
+		t.Errorf("Players() = %v, want [Merlin]", got)
+	}
+	quitFirst()
+	quitSecond()
+
+	if got := opened(); len(got) != 1 {
+		t.Errorf("opened games %v, want Merlin's once", got)
+	}
+}
+
+func TestServerRefusesUnknownKeys(t *testing.T) {
+	_, merlinLine := newPlayerKey(t, "Merlin")
+	stranger, _ := newPlayerKey(t, "Stranger")
+	server, hostKey, opened := startServer(t, merlinLine)
+
+	_, err := ssh.Dial("tcp", server.Addr(), &ssh.ClientConfig{
+		User:            "Merlin",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(stranger)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         5 * time.Second,
+	})
+	if err == nil {
+		t.Fatal("unknown key logged in")
+	}
+	if got := opened(); len(got) != 0 {
+		t.Errorf("opened games %v for an unknown key", got)
+	}
+}
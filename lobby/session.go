@@ -0,0 +1,157 @@
+package lobby
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// Terminal size assumed when a client doesn't report one
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// ptyRequest is the payload of a "pty-req" request (RFC 4254 6.2).
+type ptyRequest struct {
+	Term          string
+	Columns, Rows uint32
+	Width, Height uint32 // Pixels
+	Modes         string
+}
+
+// windowChange is the payload of a "window-change" request (RFC 4254 6.7).
+type windowChange struct {
+	Columns, Rows uint32
+	Width, Height uint32 // Pixels
+}
+
+// exitStatus is the payload of an "exit-status" request (RFC 4254 6.10).
+type exitStatus struct {
+	Status uint32
+}
+
+// session serves one SSH session: it waits for a terminal and a shell, then
+// runs the player's TUI until they quit or disconnect.
+func (s *Server) session(nickname string, channel ssh.Channel, requests <-chan *ssh.Request) {
+	var pty *ptyRequest
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			var p ptyRequest
+			if err := ssh.Unmarshal(req.Payload, &p); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			pty = &p
+			_ = req.Reply(true, nil)
+		case "shell":
+			_ = req.Reply(true, nil)
+			if !s.startPlaying() {
+				fmt.Fprint(channel, "The server is shutting down\r\n")
+				sendExitStatus(channel, 1)
+				return
+			}
+			defer s.playing.Done()
+			if pty == nil {
+				fmt.Fprint(channel, "ManaTTY needs a terminal; connect with ssh -t\r\n")
+				sendExitStatus(channel, 1)
+				return
+			}
+			sendExitStatus(channel, s.play(nickname, channel, requests, pty))
+			return
+		default:
+			// Commands, subsystems and forwarding aren't offered
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+// play runs a TUI for nickname's game over channel and returns the exit
+// status. Window size changes arrive on requests.
+func (s *Server) play(nickname string, channel ssh.Channel, requests <-chan *ssh.Request, pty *ptyRequest) uint32 {
+	game, err := s.acquire(nickname)
+	if err != nil {
+		utils.Log(utils.LogError, "lobby can't load game", "nickname", nickname, "err", err)
+		fmt.Fprintf(channel, "Can't load your game: %v\r\n", err)
+		return 1
+	}
+	defer s.release(nickname)
+
+	// Each TUI renders for its own terminal's colors
+	renderer := lipgloss.NewRenderer(channel)
+	renderer.SetColorProfile(colorProfile(pty.Term))
+
+	p := tea.NewProgram(game.NewModel(renderer),
+		tea.WithInput(channel),
+		tea.WithOutput(channel),
+		tea.WithAltScreen(),
+		tea.WithoutSignalHandler(),
+	)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return 1
+	}
+	s.programs[p] = true
+	s.mu.Unlock()
+	utils.Log(utils.LogInfo, "lobby session started", "nickname", nickname, "term", pty.Term,
+		"width", pty.Columns, "height", pty.Rows)
+
+	go func() {
+		// Requests stop when the client closes the session
+		defer p.Quit()
+		width, height := int(pty.Columns), int(pty.Rows)
+		if width == 0 || height == 0 {
+			// Not every client reports its size up front
+			width, height = defaultWidth, defaultHeight
+		}
+		p.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		for req := range requests {
+			if req.Type != "window-change" {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			var size windowChange
+			if err := ssh.Unmarshal(req.Payload, &size); err == nil {
+				p.Send(tea.WindowSizeMsg{Width: int(size.Columns), Height: int(size.Rows)})
+			}
+		}
+	}()
+
+	_, err = p.Run()
+	s.mu.Lock()
+	delete(s.programs, p)
+	s.mu.Unlock()
+	utils.Log(utils.LogInfo, "lobby session ended", "nickname", nickname)
+	if err != nil {
+		utils.Log(utils.LogWarn, "lobby TUI stopped", "nickname", nickname, "err", err)
+		return 1
+	}
+	return 0
+}
+
+// sendExitStatus tells the client the session is over.
+func sendExitStatus(channel ssh.Channel, status uint32) {
+	_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(exitStatus{Status: status}))
+}
+
+// colorProfile guesses the color support of a terminal from its TERM.
+func colorProfile(term string) termenv.Profile {
+	switch {
+	case term == "" || term == "dumb":
+		return termenv.Ascii
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"), strings.Contains(term, "direct"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	default:
+		return termenv.ANSI
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/lobby"
+	"github.com/Ltorre/ManaTTY/ui"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// runLobby runs "manatty lobby [flags]", which serves the game over SSH to
+// the players listed in the lobby's authorized keys file until interrupted.
+func runLobby(args []string) int {
+	cfg, code, ok := loadCommandConfig("lobby", args)
+	if !ok {
+		return code
+	}
+
+	hostKey, err := lobby.LoadOrCreateHostKey(filepath.Join(cfg.DataDir, lobby.HostKeyFileName))
+	if err != nil {
+		utils.Error("Can't load the lobby host key: %v", err)
+		return 1
+	}
+	keysPath := filepath.Join(cfg.DataDir, lobby.AuthorizedKeysFileName)
+	if _, err := lobby.LoadAuthorizedKeys(keysPath); err != nil {
+		// Keys are read on every login, so players can be added later
+		utils.Warn("Nobody can log in yet: %v", err)
+		utils.Info("Add a line per player to %s: <public key> <nickname>", keysPath)
+	}
+
+	b := openBackend(cfg)
+	defer b.close()
+
+	// Each session shows its player's theme; warn about a bad default now
+	if cfg.Theme != "" {
+		if _, err := ui.ThemeByName(cfg.Theme); err != nil {
+			utils.Warn("Ignoring theme: %v", err)
+		}
+	}
+
+	server := lobby.NewServer(cfg.LobbyAddr, hostKey, keysPath, func(nickname string) (*lobby.Game, error) {
+		run := b.loadGame(nickname, config.SlotLatest, false)
+		return &lobby.Game{
+			Loop:     run.loop,
+			NewModel: func(r *lipgloss.Renderer) tea.Model { return newLobbyModel(run, r) },
+		}, nil
+	})
+	if err := server.Listen(); err != nil {
+		utils.Error("Can't start the lobby: %v", err)
+		return 1
+	}
+	go func() {
+		if err := server.Serve(); err != nil {
+			utils.Log(utils.LogError, "lobby stopped accepting", "err", err)
+		}
+	}()
+
+	utils.Info("Lobby listening on %s (host key %s)", server.Addr(), ssh.FingerprintSHA256(hostKey.PublicKey()))
+	if _, port, err := net.SplitHostPort(server.Addr()); err == nil {
+		utils.Info("Connect with: ssh -p %s <host>", port)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	// Ending the sessions saves every game
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := server.Close(shutdownCtx); err != nil {
+		utils.Error("Lobby shutdown: %v", err)
+	}
+	utils.Info("Lobby stopped")
+	return 0
+}
+
+// newLobbyModel creates a TUI for one SSH session of run's player, drawn
+// with r. Theme, symbols, notifications and confirmations changed in the
+// session last until it ends (the theme is saved with the profile); the tick
+// rate and autosave interval are the server's, as its loop runs the game.
+// The server's config file and log stay private.
+func newLobbyModel(run *gameRun, r *lipgloss.Renderer) *ui.Model {
+	model := run.newModel()
+	model.SetRenderer(r)
+	cfg := run.cfg.Clone()
+	cfg.Path = ""
+	model.SetSessionConfig(cfg)
+	model.SetLogFile("")
+	return model
+}
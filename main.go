@@ -3,17 +3,25 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/uuid"
 
+	"github.com/Ltorre/ManaTTY/api"
 	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/daemon"
 	"github.com/Ltorre/ManaTTY/engine"
 	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/hooks"
+	"github.com/Ltorre/ManaTTY/metrics"
 	"github.com/Ltorre/ManaTTY/models"
 	"github.com/Ltorre/ManaTTY/storage"
 	"github.com/Ltorre/ManaTTY/ui"
@@ -21,37 +29,363 @@ import (
 )
 
 func main() {
+	// Subcommands (e.g. "manatty optimize") run without the TUI
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	fs, flags := gameFlags()
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	// Load configuration (flag > env > file > default)
+	cfg, err := config.Load(flags)
+	if err != nil {
+		utils.Warn("Ignoring config file: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// A daemon already running the game owns it; attach instead of starting
+	// a second copy
+	socket := socketPath(cfg)
+	if _, err := daemon.QueryStatus(socket); err == nil {
+		os.Exit(attach(socket))
+	}
+
 	// Use ASCII-friendly symbols on legacy Windows CMD
 	if ui.SupportsEmoji() {
 		fmt.Println("🏰 Mage Tower Ascension")
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━")
 	} else {
 		fmt.Println("# Mage Tower Ascension")
-		fmt.Println("-----------------------")
 	}
 
-	// Prompt for nickname
-	nickname := promptNickname()
+	// Prompt for nickname unless one was configured
+	nickname := cfg.Nickname
+	if nickname == "" {
+		nickname = promptNickname()
+	}
 
 	fmt.Println("Loading...")
+	os.Exit(openGame(cfg, nickname).play())
+}
 
-	// Load configuration
-	cfg, err := config.Load()
+// play runs the game's TUI in this terminal, then closes the game. It
+// returns the process exit code.
+func (r *gameRun) play() int {
+	// Log only to the file so nothing scribbles over the TUI
+	p := tea.NewProgram(r.newModel(), tea.WithAltScreen())
+	utils.SetLogOutput(io.Discard)
+	_, err := p.Run()
+	utils.SetLogOutput(os.Stdout)
+	r.close()
 	if err != nil {
-		utils.Error("Failed to load config: %v", err)
+		utils.Error("Error running program: %v", err)
+		return 1
 	}
+	utils.Log(utils.LogInfo, "exiting")
+
+	fmt.Println("\nThanks for playing Mage Tower Ascension!")
+	return 0
+}
+
+// backend is the logging, storage and key bindings shared by every game the
+// process runs.
+type backend struct {
+	cfg         *config.Config
+	db          *storage.Database // May be nil
+	saveStore   storage.SaveStore
+	playerStore storage.PlayerStore
+	keys        *ui.KeyMap
+	logFile     *utils.RotatingFile // Nil if file logging is off
+}
+
+// openBackend starts logging and connects to storage.
+func openBackend(cfg *config.Config) *backend {
+	b := &backend{cfg: cfg}
 
-	// Set log level
+	// Set log level (debug mode logs everything, including engine traces)
 	utils.SetLogLevel(utils.ParseLogLevel(cfg.LogLevel))
+	if cfg.Debug {
+		utils.SetLogLevel(utils.LogDebug)
+	}
+
+	// Keep a log file to inspect afterwards, since the TUI hides the console
+	b.logFile = openLogFile(cfg)
+	utils.Log(utils.LogInfo, "starting", "storage", cfg.StorageMode, "debug", cfg.Debug)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	// Initialize storage based on config
-	var saveStore storage.SaveStore
-	var playerStore storage.PlayerStore
-	var db *storage.Database
+	b.saveStore, b.playerStore, b.db = openStorage(ctx, cfg)
+
+	b.keys = loadKeyMap()
+	return b
+}
+
+// close disconnects storage and closes the log file.
+func (b *backend) close() {
+	if b.db != nil {
+		_ = b.db.Disconnect(context.Background())
+	}
+	if b.logFile != nil {
+		_ = b.logFile.Close()
+	}
+}
+
+// gameRun is a loaded game and the services running alongside it.
+type gameRun struct {
+	*backend
+	gameState    *models.GameState
+	player       *models.Player
+	engine       *engine.GameEngine
+	loop         *daemon.Loop
+	achievements *engine.AchievementTracker
+	eventLog     *engine.EventLog
+	tutorial     *engine.Tutorial
+	exporter     *metrics.Exporter // Nil if metrics are off
+	dispatcher   *hooks.Dispatcher // Nil without hooks
+	apiServer    *api.Server       // Nil if the API is off
+}
+
+// openGame opens the backend and loads nickname's game with its services.
+func openGame(cfg *config.Config, nickname string) *gameRun {
+	return openBackend(cfg).loadGame(nickname, cfg.Slot, true)
+}
+
+// loadGame loads nickname's game (creating it if needed) and applies offline
+// progress. A standalone game is the only one in the process: it reports
+// offline progress on the console and starts metrics, hooks and the HTTP
+// API.
+func (b *backend) loadGame(nickname string, slot int, standalone bool) *gameRun {
+	run := &gameRun{backend: b}
+	cfg := b.cfg
+	utils.Log(utils.LogInfo, "loading game", "nickname", nickname)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Create or load game state
+	run.gameState, run.player = initializeGame(ctx, b.saveStore, b.playerStore, nickname, slot)
+	// TUIs fall back to the default theme; say why before they start
+	if theme := run.theme(); standalone && theme != "" {
+		if _, err := ui.ThemeByName(theme); err != nil {
+			utils.Warn("Ignoring theme: %v", err)
+		}
+	}
+
+	// Track achievements from engine events (including offline progress).
+	// Challenge runs play by their own rules, so they don't count towards
+	// achievements; they draw from the challenge's RNG and keep their best
+	// result on the profile instead.
+	run.engine = engine.NewGameEngine()
+	if cfg.Debug {
+		run.engine.TraceEvents(utils.DefaultLogger)
+	}
+	if challenge := run.gameState.Challenge; challenge != nil {
+		run.engine.UseChallenge(challenge)
+		run.engine.OnChallengeCompleted = func(result models.ChallengeResult) {
+			run.player.RecordChallengeResult(result)
+		}
+	} else {
+		run.achievements = engine.NewAchievementTracker(run.engine, run.player, run.gameState)
+		run.tutorial = engine.NewTutorial(run.engine, run.player, run.gameState)
+	}
+	run.eventLog = engine.NewEventLog(run.engine, game.EventLogSize)
+	if standalone {
+		run.exporter = startMetrics(cfg, run.engine)
+	}
+
+	// Apply offline progress if we loaded a save (the event log keeps a
+	// summary for games that don't report it here)
+	if run.gameState.SavedAt.After(time.Time{}) {
+		offlineProgress := run.engine.ApplyOfflineProgress(run.gameState)
+		if standalone && offlineProgress.TimeOffline > time.Minute {
+			if ui.SupportsEmoji() {
+				fmt.Printf("\n📊 Offline Progress: %s\n", engine.FormatOfflineProgress(offlineProgress))
+			} else {
+				fmt.Printf("\n* Offline Progress: %s\n", engine.FormatOfflineProgress(offlineProgress))
+			}
+			if offlineProgress.ManaGenerated > 0 {
+				fmt.Printf("   Mana earned: %s\n", utils.FormatNumber(offlineProgress.ManaGenerated))
+			}
+			if offlineProgress.FloorsClimbed > 0 {
+				fmt.Printf("   Floors climbed: %d\n", offlineProgress.FloorsClimbed)
+			}
+			for _, action := range offlineProgress.Automation {
+				fmt.Printf("   Automation: %s\n", action)
+			}
+			fmt.Println()
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	// The loop ticks and saves the game; the TUI and the API lock it
+	run.loop = daemon.NewLoop(run.gameState, run.player, run.engine, b.saveStore, b.playerStore, cfg)
+	// Challenge runs always record a replay, so a result can be checked
+	if cfg.RecordReplays || run.gameState.Challenge != nil {
+		run.loop.Record()
+	}
+	if !standalone {
+		return run
+	}
+
+	// Run hooks on game events from here on, so offline progress doesn't
+	// fire a burst of stale ones
+	run.dispatcher = startHooks(cfg, run.engine)
+
+	if run.exporter != nil {
+		run.loop.SetMetrics(run.exporter)
+	}
+	run.apiServer = startAPI(cfg, run.loop)
+	return run
+}
+
+// newModel creates a TUI for the game. Several may share one game.
+func (r *gameRun) newModel() *ui.Model {
+	model := ui.NewModel()
+	model.SetGameState(r.gameState)
+	model.SetPlayer(r.player)
+	model.SetEngine(r.engine)
+	model.SetLoop(r.loop)
+	model.SetSaveStore(r.saveStore)
+	model.SetPlayerStore(r.playerStore)
+	model.SetAchievementTracker(r.achievements)
+	model.SetEventLog(r.eventLog)
+	model.SetTutorial(r.tutorial)
+	model.SetDatabase(r.db) // Keep for backward compatibility (may be nil)
+	model.SetKeyMap(r.keys)
+	model.SetConfig(r.cfg)
+	if err := model.SetTheme(r.theme()); err != nil {
+		utils.Log(utils.LogWarn, "ignoring theme", "err", err)
+	}
+	if r.logFile != nil {
+		model.SetLogFile(r.logFile.Path())
+	}
+	return model
+}
+
+// theme returns the player's theme, or the configured default.
+func (r *gameRun) theme() string {
+	if r.player.Theme != "" {
+		return r.player.Theme
+	}
+	return r.cfg.Theme
+}
+
+// close stops the services started by loadGame and closes the backend.
+func (r *gameRun) close() {
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelShutdown()
+	if r.apiServer != nil {
+		_ = r.apiServer.Shutdown(shutdownCtx)
+	}
+	if r.exporter != nil {
+		_ = r.exporter.Shutdown(shutdownCtx)
+	}
+	if r.dispatcher != nil {
+		_ = r.dispatcher.Close(shutdownCtx)
+	}
+	r.backend.close()
+}
+
+// loadKeyMap reads ~/.manatty/keys.toml, falling back to the default bindings
+// when the file is invalid.
+func loadKeyMap() *ui.KeyMap {
+	path, err := ui.DefaultKeyMapPath()
+	if err != nil {
+		return ui.DefaultKeyMap()
+	}
+	km, err := ui.LoadKeyMap(path)
+	if err != nil {
+		utils.Warn("Ignoring key bindings in %s: %v", path, err)
+		return ui.DefaultKeyMap()
+	}
+	return km
+}
+
+// openLogFile opens the rotating log file in <data dir>/logs and attaches it
+// to the default logger. It returns nil if the file can't be opened.
+func openLogFile(cfg *config.Config) *utils.RotatingFile {
+	path := filepath.Join(cfg.DataDir, "logs", utils.LogFileName)
+	logFile, err := utils.OpenRotatingFile(path, utils.LogFileMaxSize, utils.LogFileBackups)
+	if err != nil {
+		utils.Warn("Logging to the console only: %v", err)
+		return nil
+	}
+	utils.SetLogFile(logFile)
+	return logFile
+}
+
+// startAPI serves the local HTTP API when cfg.APIAddr is set, running each
+// request with the game loop locked. It returns nil if the API is off or
+// can't start.
+func startAPI(cfg *config.Config, loop *daemon.Loop) *api.Server {
+	if cfg.APIAddr == "" {
+		return nil
+	}
+	token := cfg.APIToken
+	if token == "" {
+		path := filepath.Join(cfg.DataDir, api.TokenFileName)
+		var err error
+		if token, err = api.LoadOrCreateToken(path); err != nil {
+			utils.Warn("API disabled, can't create a token: %v", err)
+			return nil
+		}
+	}
+	server := api.NewServer(cfg.APIAddr, token, func(c api.Call) { loop.Do(c.Run) })
+	if err := server.Start(); err != nil {
+		utils.Warn("API disabled: %v", err)
+		return nil
+	}
+	utils.Info("API listening on http://%s", server.Addr())
+	return server
+}
+
+// startMetrics serves Prometheus metrics when cfg.MetricsAddr is set. It
+// returns nil if metrics are off or can't start.
+func startMetrics(cfg *config.Config, e *engine.GameEngine) *metrics.Exporter {
+	if cfg.MetricsAddr == "" {
+		return nil
+	}
+	exporter := metrics.NewExporter(e)
+	if err := exporter.Start(cfg.MetricsAddr); err != nil {
+		utils.Warn("Metrics disabled: %v", err)
+		return nil
+	}
+	utils.Info("Metrics at http://%s/metrics", exporter.Addr())
+	return exporter
+}
+
+// startHooks runs the configured hooks on e's events. It returns nil if
+// there are no hooks or they can't start.
+func startHooks(cfg *config.Config, e *engine.GameEngine) *hooks.Dispatcher {
+	if len(cfg.Hooks) == 0 {
+		return nil
+	}
+	dispatcher, err := hooks.NewDispatcher(cfg.Hooks)
+	if err != nil {
+		utils.Warn("Hooks disabled: %v", err)
+		return nil
+	}
+	dispatcher.Attach(e)
+	return dispatcher
+}
+
+// openStorage connects to MongoDB when configured, falling back to local JSON storage.
+func openStorage(ctx context.Context, cfg *config.Config) (storage.SaveStore, storage.PlayerStore, *storage.Database) {
+	var saveStore storage.SaveStore
+	var playerStore storage.PlayerStore
+	var db *storage.Database
+
 	if cfg.StorageMode == "mongodb" && cfg.MongoDBURI != "" {
 		// Use MongoDB storage
 		db = storage.NewDatabase()
@@ -80,66 +414,21 @@ func main() {
 
 	// Fall back to local storage if MongoDB not available
 	if cfg.StorageMode == "local" || saveStore == nil {
-		utils.Info("Using local storage (~/.manatty/)")
+		utils.Info("Using local storage (%s)", cfg.DataDir)
 		var err error
-		saveStore, err = storage.NewJSONSaveStore()
+		saveStore, err = storage.NewJSONSaveStore(cfg.DataDir)
 		if err != nil {
 			utils.Error("Failed to create local save store: %v", err)
 			os.Exit(1)
 		}
-		playerStore, err = storage.NewJSONPlayerStore()
+		playerStore, err = storage.NewJSONPlayerStore(cfg.DataDir)
 		if err != nil {
 			utils.Error("Failed to create local player store: %v", err)
 			os.Exit(1)
 		}
 	}
 
-	// Create or load game state
-	gameState, player := initializeGame(ctx, saveStore, playerStore, nickname)
-
-	// Apply offline progress if we loaded a save
-	gameEngine := engine.NewGameEngine()
-	if gameState.SavedAt.After(time.Time{}) {
-		offlineProgress := gameEngine.ApplyOfflineProgress(gameState)
-		if offlineProgress.TimeOffline > time.Minute {
-			if ui.SupportsEmoji() {
-				fmt.Printf("\n📊 Offline Progress: %s\n", engine.FormatOfflineProgress(offlineProgress))
-			} else {
-				fmt.Printf("\n* Offline Progress: %s\n", engine.FormatOfflineProgress(offlineProgress))
-			}
-			if offlineProgress.ManaGenerated > 0 {
-				fmt.Printf("   Mana earned: %s\n", utils.FormatNumber(offlineProgress.ManaGenerated))
-			}
-			if offlineProgress.FloorsClimbed > 0 {
-				fmt.Printf("   Floors climbed: %d\n", offlineProgress.FloorsClimbed)
-			}
-			fmt.Println()
-			time.Sleep(2 * time.Second)
-		}
-	}
-
-	// Create UI model
-	model := ui.NewModel()
-	model.SetGameState(gameState)
-	model.SetPlayer(player)
-	model.SetEngine(gameEngine)
-	model.SetSaveStore(saveStore)
-	model.SetDatabase(db) // Keep for backward compatibility (may be nil)
-
-	// Run the TUI
-	p := tea.NewProgram(model, tea.WithAltScreen())
-
-	if _, err := p.Run(); err != nil {
-		utils.Error("Error running program: %v", err)
-		os.Exit(1)
-	}
-
-	// Cleanup
-	if db != nil {
-		_ = db.Disconnect(context.Background())
-	}
-
-	fmt.Println("\nThanks for playing Mage Tower Ascension!")
+	return saveStore, playerStore, db
 }
 
 // promptNickname asks the user for their nickname to load or create a save.
@@ -155,26 +444,41 @@ func promptNickname() string {
 	return nickname
 }
 
-// initializeGame creates or loads a game state for the given nickname.
-func initializeGame(ctx context.Context, saveStore storage.SaveStore, playerStore storage.PlayerStore, nickname string) (*models.GameState, *models.Player) {
+// initializeGame loads the nickname's save in slot (config.SlotLatest for the
+// most recent one), creating the player or save if needed.
+func initializeGame(ctx context.Context, saveStore storage.SaveStore, playerStore storage.PlayerStore, nickname string, slot int) (*models.GameState, *models.Player) {
 	// Try to find existing player by username
 	player, err := playerStore.GetByUsername(ctx, nickname)
-	if err == nil && player != nil {
-		// Load their latest save
-		gameState, err := saveStore.LoadLatest(ctx, player.UUID)
+	if err != nil {
+		player = nil
+	}
+	if player != nil {
+		// Load their latest save, or the requested slot
+		var gameState *models.GameState
+		if slot == config.SlotLatest {
+			gameState, err = saveStore.LoadLatest(ctx, player.UUID)
+		} else {
+			gameState, err = saveStore.Load(ctx, player.UUID, slot)
+		}
 		if err == nil {
 			utils.Info("Loaded save for %s (Floor %d)", player.Username, gameState.Tower.CurrentFloor)
 			return gameState, player
 		}
 	}
 
+	if slot == config.SlotLatest {
+		slot = 0
+	}
+
 	// Create new game for this nickname
-	utils.Info("Creating new game for %s...", nickname)
+	utils.Info("Creating new game for %s (slot %d)...", nickname, slot)
 
-	playerUUID := uuid.New().String()
-	player = models.NewPlayer(playerUUID, nickname)
+	newPlayer := player == nil
+	if newPlayer {
+		player = models.NewPlayer(uuid.New().String(), nickname)
+	}
 
-	gameState := models.NewGameState(playerUUID, 0)
+	gameState := models.NewGameState(player.UUID, slot)
 
 	// Add starting spells
 	for _, spell := range game.GetBaseSpells() {
@@ -182,8 +486,10 @@ func initializeGame(ctx context.Context, saveStore storage.SaveStore, playerStor
 	}
 
 	// Save new player and game
-	if err := playerStore.Create(ctx, player); err != nil {
-		utils.Warn("Failed to create player: %v", err)
+	if newPlayer {
+		if err := playerStore.Create(ctx, player); err != nil {
+			utils.Warn("Failed to create player: %v", err)
+		}
 	}
 
 	if err := saveStore.Save(ctx, gameState); err != nil {
@@ -0,0 +1,2 @@
+// Package metrics exports game metrics in the Prometheus text format.
+package metrics
@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// saveBuckets are the upper bounds, in seconds, of the save latency histogram.
+var saveBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// castKey labels cast counters.
+type castKey struct {
+	spell   string
+	element string
+}
+
+// Exporter collects game metrics and serves them at /metrics. Casts, crits
+// and climbs come from engine events; gauges are sampled from the game loop
+// with Sample; saves are recorded with ObserveSave. It is safe for
+// concurrent use.
+type Exporter struct {
+	mu sync.Mutex
+
+	// Gauges (last sample)
+	floor         float64
+	maxFloor      float64
+	mana          float64
+	manaPerSecond float64
+	sigilCharge   float64
+	sigilRequired float64
+	era           float64
+
+	// Counters
+	casts         map[castKey]float64
+	crits         map[castKey]float64
+	floorsClimbed float64
+	synergySecs   float64
+	playSecs      float64
+	saves         float64
+	saveFailures  float64
+
+	// Save latency histogram
+	saveBucketCounts []float64
+	saveSeconds      float64
+
+	server *http.Server
+	addr   string
+}
+
+// NewExporter creates an exporter subscribed to e's events.
+func NewExporter(e *engine.GameEngine) *Exporter {
+	x := &Exporter{
+		casts:            map[castKey]float64{},
+		crits:            map[castKey]float64{},
+		saveBucketCounts: make([]float64, len(saveBuckets)),
+	}
+	e.Subscribe(x.handleEvent)
+	return x
+}
+
+// handleEvent counts casts, crits and climbs.
+func (x *Exporter) handleEvent(event game.EventData) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	switch event.Type {
+	case game.EventSpellCast:
+		spellID, _ := event.Data["spell_id"].(string)
+		element, _ := event.Data["element"].(string)
+		key := castKey{spell: spellID, element: element}
+		x.casts[key]++
+		if crit, _ := event.Data["crit"].(bool); crit {
+			x.crits[key]++
+		}
+	case game.EventFloorClimbed:
+		x.floorsClimbed++
+	}
+}
+
+// Sample records the game's gauges. elapsed is the time since the last
+// sample, counted towards play time and, while a synergy is active, synergy
+// uptime as of the game's time now. Call it from the game loop.
+func (x *Exporter) Sample(gs *models.GameState, manaPerSecond float64, elapsed time.Duration, now time.Time) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.floor = float64(gs.Tower.CurrentFloor)
+	x.maxFloor = float64(gs.Tower.MaxFloorReached)
+	x.mana = gs.Tower.CurrentMana
+	x.manaPerSecond = manaPerSecond
+	x.sigilCharge = gs.Tower.SigilCharge
+	x.sigilRequired = gs.Tower.SigilRequired
+	x.era = float64(gs.PrestigeData.CurrentEra)
+	x.playSecs += elapsed.Seconds()
+	if gs.HasActiveSynergyAt(now.UnixMilli()) {
+		x.synergySecs += elapsed.Seconds()
+	}
+}
+
+// ObserveSave records how long a save took and whether it failed.
+func (x *Exporter) ObserveSave(d time.Duration, err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.saves++
+	if err != nil {
+		x.saveFailures++
+	}
+	seconds := d.Seconds()
+	x.saveSeconds += seconds
+	for i, bound := range saveBuckets {
+		if seconds <= bound {
+			x.saveBucketCounts[i]++
+		}
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (x *Exporter) WriteTo(w io.Writer) (int64, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	var b strings.Builder
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatValue(value))
+	}
+	counter := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n%s %s\n", name, help, name, name, formatValue(value))
+	}
+	castCounter := func(name, help string, values map[castKey]float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		keys := make([]castKey, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].spell != keys[j].spell {
+				return keys[i].spell < keys[j].spell
+			}
+			return keys[i].element < keys[j].element
+		})
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s{spell=\"%s\",element=\"%s\"} %s\n", name, escapeLabel(key.spell), escapeLabel(key.element), formatValue(values[key]))
+		}
+	}
+
+	gauge("manatty_floor", "Current tower floor.", x.floor)
+	gauge("manatty_max_floor", "Highest floor reached.", x.maxFloor)
+	gauge("manatty_mana", "Current mana.", x.mana)
+	gauge("manatty_mana_per_second", "Current mana generation per second.", x.manaPerSecond)
+	gauge("manatty_sigil_charge", "Current ascension sigil charge.", x.sigilCharge)
+	gauge("manatty_sigil_required", "Sigil charge needed to climb the current floor.", x.sigilRequired)
+	gauge("manatty_era", "Current prestige era.", x.era)
+	castCounter("manatty_casts_total", "Spell casts by spell and element.", x.casts)
+	castCounter("manatty_crits_total", "Critical spell casts by spell and element.", x.crits)
+	counter("manatty_floors_climbed_total", "Floors climbed.", x.floorsClimbed)
+	counter("manatty_play_seconds_total", "Time the game has been running.", x.playSecs)
+	counter("manatty_synergy_active_seconds_total", "Time an element synergy was active (divide by play time for uptime).", x.synergySecs)
+	counter("manatty_saves_total", "Save attempts.", x.saves)
+	counter("manatty_save_failures_total", "Failed saves.", x.saveFailures)
+
+	name := "manatty_save_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Time taken to save the game.\n# TYPE %s histogram\n", name, name)
+	for i, bound := range saveBuckets {
+		fmt.Fprintf(&b, "%s_bucket{le=%q} %s\n", name, formatValue(bound), formatValue(x.saveBucketCounts[i]))
+	}
+	fmt.Fprintf(&b, "%s_bucket{le=\"+Inf\"} %s\n", name, formatValue(x.saves))
+	fmt.Fprintf(&b, "%s_sum %s\n%s_count %s\n", name, formatValue(x.saveSeconds), name, formatValue(x.saves))
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// formatValue formats a sample value.
+func formatValue(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+// labelEscaper escapes label values as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value.
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// Handler returns an HTTP handler serving the metrics.
+func (x *Exporter) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := x.WriteTo(w); err != nil {
+			utils.Log(utils.LogWarn, "metrics response failed", "err", err)
+		}
+	})
+}
+
+// Start serves the metrics at http://addr/metrics in the background.
+func (x *Exporter) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", x.Handler())
+	x.addr = listener.Addr().String()
+	x.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := x.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			utils.Log(utils.LogError, "metrics server stopped", "err", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the metrics are served on.
+func (x *Exporter) Addr() string {
+	return x.addr
+}
+
+// Shutdown stops serving the metrics.
+func (x *Exporter) Shutdown(ctx context.Context) error {
+	if x.server == nil {
+		return nil
+	}
+	return x.server.Shutdown(ctx)
+}
@@ -0,0 +1,136 @@
+package models
+
+import "time"
+
+// AchievementCategory groups achievements in the UI.
+type AchievementCategory string
+
+const (
+	AchievementFloors  AchievementCategory = "floors"
+	AchievementEras    AchievementCategory = "eras"
+	AchievementSpells  AchievementCategory = "spells"
+	AchievementRituals AchievementCategory = "rituals"
+	AchievementSpeed   AchievementCategory = "speed"
+)
+
+// AchievementCategories lists categories in display order.
+var AchievementCategories = []AchievementCategory{
+	AchievementFloors,
+	AchievementEras,
+	AchievementSpells,
+	AchievementRituals,
+	AchievementSpeed,
+}
+
+// AchievementCategoryNames provides display names for categories.
+var AchievementCategoryNames = map[AchievementCategory]string{
+	AchievementFloors:  "Floors",
+	AchievementEras:    "Eras",
+	AchievementSpells:  "Spells",
+	AchievementRituals: "Rituals",
+	AchievementSpeed:   "Speed",
+}
+
+// AchievementStat names a lifetime statistic that achievements track.
+type AchievementStat string
+
+const (
+	StatHighestFloor      AchievementStat = "highest_floor"
+	StatAscensions        AchievementStat = "ascensions"
+	StatCasts             AchievementStat = "casts"
+	StatCrits             AchievementStat = "crits"
+	StatElementSynergies  AchievementStat = "element_synergies"
+	StatRitualSynergies   AchievementStat = "max_ritual_synergies" // Most ritual synergies active at once
+	StatFastestFloor100Ms AchievementStat = "fastest_floor_100_ms"
+	StatHighestCast       AchievementStat = "highest_cast" // Highest single-cast damage
+)
+
+// SignatureRitualStat returns the stat recording that a signature ritual was formed.
+func SignatureRitualStat(signature string) AchievementStat {
+	return AchievementStat("ritual:" + signature)
+}
+
+// AchievementDefinition describes an achievement and how progress is measured.
+type AchievementDefinition struct {
+	ID            string
+	Name          string
+	Description   string
+	Category      AchievementCategory
+	Stat          AchievementStat
+	Target        float64
+	LowerIsBetter bool    // Stat must be at or below Target (e.g. a best time)
+	RewardManaGen float64 // Permanent mana generation bonus (0.01 = +1%)
+}
+
+// Progress returns completion in [0, 1] for a stat value.
+func (d *AchievementDefinition) Progress(value float64) float64 {
+	if d.LowerIsBetter {
+		if value <= 0 {
+			return 0
+		}
+		if value <= d.Target {
+			return 1
+		}
+		return d.Target / value
+	}
+	if d.Target <= 0 || value >= d.Target {
+		return 1
+	}
+	if value < 0 {
+		return 0
+	}
+	return value / d.Target
+}
+
+// AchievementState is a player's achievement progress, kept across saves and prestige.
+type AchievementState struct {
+	Unlocked map[string]time.Time        `bson:"unlocked" json:"unlocked"` // Achievement ID -> unlock time
+	Stats    map[AchievementStat]float64 `bson:"stats" json:"stats"`
+}
+
+// NewAchievementState creates empty achievement progress.
+func NewAchievementState() *AchievementState {
+	return &AchievementState{
+		Unlocked: map[string]time.Time{},
+		Stats:    map[AchievementStat]float64{},
+	}
+}
+
+// IsUnlocked returns true if the achievement has been earned.
+func (a *AchievementState) IsUnlocked(id string) bool {
+	_, ok := a.Unlocked[id]
+	return ok
+}
+
+// AddStat increments a counter stat.
+func (a *AchievementState) AddStat(stat AchievementStat, amount float64) {
+	a.Stats[stat] += amount
+}
+
+// RaiseStat records value if it beats the current best.
+func (a *AchievementState) RaiseStat(stat AchievementStat, value float64) {
+	if value > a.Stats[stat] {
+		a.Stats[stat] = value
+	}
+}
+
+// LowerStat records value if it beats the current best (lower is better, 0 means unset).
+func (a *AchievementState) LowerStat(stat AchievementStat, value float64) {
+	if current := a.Stats[stat]; current == 0 || value < current {
+		a.Stats[stat] = value
+	}
+}
+
+// EnsureAchievements initializes achievement progress for profiles that predate it.
+func (p *Player) EnsureAchievements() *AchievementState {
+	if p.Achievements == nil {
+		p.Achievements = NewAchievementState()
+	}
+	if p.Achievements.Unlocked == nil {
+		p.Achievements.Unlocked = map[string]time.Time{}
+	}
+	if p.Achievements.Stats == nil {
+		p.Achievements.Stats = map[AchievementStat]float64{}
+	}
+	return p.Achievements
+}
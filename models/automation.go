@@ -0,0 +1,260 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Automation rules let the engine act on the player's behalf (prestige,
+// upgrades, floor events, loadouts), both while playing and offline.
+
+const (
+	// FloorRateWindowMs is the rolling window used to measure floors per hour.
+	FloorRateWindowMs = int64(15 * 60 * 1000)
+	// FloorRateMinSampleMs is how long climbs must be tracked before the rate is trusted.
+	FloorRateMinSampleMs = int64(5 * 60 * 1000)
+
+	// MaxLoadoutPresets limits how many loadouts can be saved.
+	MaxLoadoutPresets = 9
+)
+
+// AutoPrestigeRule prestiges once progress has stalled past a floor.
+type AutoPrestigeRule struct {
+	Enabled          bool    `bson:"enabled" json:"enabled"`
+	MinFloor         int     `bson:"min_floor" json:"min_floor"`                     // Only prestige at or above this floor
+	MaxFloorsPerHour float64 `bson:"max_floors_per_hour" json:"max_floors_per_hour"` // ...and when climbing slower than this
+}
+
+// AutoUpgradeRule buys the cheapest spell upgrade when mana piles up.
+type AutoUpgradeRule struct {
+	Enabled   bool    `bson:"enabled" json:"enabled"`
+	ManaAbove float64 `bson:"mana_above" json:"mana_above"` // Multiple of the current floor cost (2.0 = 200%)
+}
+
+// AutoFloorEventRule answers floor events with a fixed choice.
+type AutoFloorEventRule struct {
+	Enabled bool             `bson:"enabled" json:"enabled"`
+	Choice  FloorEventChoice `bson:"choice" json:"choice"`
+}
+
+// ReapplyPresetRule restores a saved loadout after every prestige.
+type ReapplyPresetRule struct {
+	Enabled bool   `bson:"enabled" json:"enabled"`
+	Preset  string `bson:"preset" json:"preset"` // Preset name
+}
+
+// AutomationState holds the player's automation rules and the climb history
+// used to measure floors per hour.
+type AutomationState struct {
+	Prestige      AutoPrestigeRule   `bson:"prestige" json:"prestige"`
+	Upgrade       AutoUpgradeRule    `bson:"upgrade" json:"upgrade"`
+	FloorEvent    AutoFloorEventRule `bson:"floor_event" json:"floor_event"`
+	ReapplyPreset ReapplyPresetRule  `bson:"reapply_preset" json:"reapply_preset"`
+
+	// Floor rate tracking
+	RecentClimbsMs  []int64 `bson:"recent_climbs_ms" json:"recent_climbs_ms"`   // Climb times within the rate window
+	TrackingSinceMs int64   `bson:"tracking_since_ms" json:"tracking_since_ms"` // Start of tracking (reset on prestige)
+}
+
+// FloorEventChoiceCycle is the order choices are cycled in the UI.
+var FloorEventChoiceCycle = []FloorEventChoice{
+	FloorEventChoiceManaGen,
+	FloorEventChoiceSigilChargeRate,
+	FloorEventChoiceCooldownReduction,
+}
+
+// NewAutomationState creates automation with every rule disabled.
+func NewAutomationState(nowMs int64) *AutomationState {
+	return &AutomationState{
+		Prestige:        AutoPrestigeRule{MinFloor: PrestigeMilestone, MaxFloorsPerHour: 60},
+		Upgrade:         AutoUpgradeRule{ManaAbove: 2.0},
+		FloorEvent:      AutoFloorEventRule{Choice: FloorEventChoiceManaGen},
+		RecentClimbsMs:  []int64{},
+		TrackingSinceMs: nowMs,
+	}
+}
+
+// AnyEnabled returns true if at least one rule is switched on.
+func (a *AutomationState) AnyEnabled() bool {
+	return a.Prestige.Enabled || a.Upgrade.Enabled || a.FloorEvent.Enabled || a.ReapplyPreset.Enabled
+}
+
+// DisableAll switches every rule off, keeping their settings.
+func (a *AutomationState) DisableAll() {
+	a.Prestige.Enabled = false
+	a.Upgrade.Enabled = false
+	a.FloorEvent.Enabled = false
+	a.ReapplyPreset.Enabled = false
+}
+
+// RecordClimb notes a floor climb and drops climbs outside the rate window.
+func (a *AutomationState) RecordClimb(nowMs int64) {
+	cutoff := nowMs - FloorRateWindowMs
+	kept := a.RecentClimbsMs[:0]
+	for _, at := range a.RecentClimbsMs {
+		if at >= cutoff {
+			kept = append(kept, at)
+		}
+	}
+	a.RecentClimbsMs = append(kept, nowMs)
+}
+
+// ResetFloorRate restarts tracking, e.g. at the start of a new era.
+func (a *AutomationState) ResetFloorRate(nowMs int64) {
+	a.RecentClimbsMs = []int64{}
+	a.TrackingSinceMs = nowMs
+}
+
+// FloorsPerHour returns the recent climb rate. ok is false until climbs have
+// been tracked for at least FloorRateMinSampleMs.
+func (a *AutomationState) FloorsPerHour(nowMs int64) (rate float64, ok bool) {
+	if nowMs-a.TrackingSinceMs < FloorRateMinSampleMs {
+		return 0, false
+	}
+	start := nowMs - FloorRateWindowMs
+	if a.TrackingSinceMs > start {
+		start = a.TrackingSinceMs
+	}
+	climbs := 0
+	for _, at := range a.RecentClimbsMs {
+		if at >= start {
+			climbs++
+		}
+	}
+	hours := float64(nowMs-start) / float64(time.Hour.Milliseconds())
+	return float64(climbs) / hours, true
+}
+
+// EnsureAutomation initializes automation for saves that predate it.
+func (gs *GameState) EnsureAutomation(nowMs int64) *AutomationState {
+	if gs.Automation == nil {
+		gs.Automation = NewAutomationState(nowMs)
+	}
+	return gs.Automation
+}
+
+// LoadoutPreset is a saved auto-cast and rotation setup.
+type LoadoutPreset struct {
+	Name            string               `bson:"name" json:"name"`
+	AutoCastEnabled bool                 `bson:"auto_cast_enabled" json:"auto_cast_enabled"`
+	AutoCastConfigs []AutoCastSlotConfig `bson:"auto_cast_configs" json:"auto_cast_configs"`
+	Rotation        *SpellRotation       `bson:"rotation,omitempty" json:"rotation,omitempty"`
+	SavedAt         time.Time            `bson:"saved_at" json:"saved_at"`
+}
+
+// GetLoadoutPreset returns the preset with the given name (case-insensitive).
+func (gs *GameState) GetLoadoutPreset(name string) *LoadoutPreset {
+	for _, preset := range gs.Presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset
+		}
+	}
+	return nil
+}
+
+// SaveLoadoutPreset stores the current loadout under name, replacing any
+// preset with the same name. Returns nil if the preset limit is reached.
+func (gs *GameState) SaveLoadoutPreset(name string) *LoadoutPreset {
+	preset := &LoadoutPreset{
+		Name:            name,
+		AutoCastEnabled: gs.Session.AutoCastEnabled,
+		AutoCastConfigs: append([]AutoCastSlotConfig{}, gs.Session.AutoCastConfigs...),
+		SavedAt:         time.Now(),
+	}
+	if gs.Session.Rotation != nil {
+		rotation := *gs.Session.Rotation
+		rotation.Spells = append([]RotationSpellConfig{}, gs.Session.Rotation.Spells...)
+		preset.Rotation = &rotation
+	}
+
+	for i, existing := range gs.Presets {
+		if strings.EqualFold(existing.Name, name) {
+			gs.Presets[i] = preset
+			return preset
+		}
+	}
+	if len(gs.Presets) >= MaxLoadoutPresets {
+		return nil
+	}
+	gs.Presets = append(gs.Presets, preset)
+	return preset
+}
+
+// ApplyLoadoutPreset replaces the current loadout with a saved preset.
+// Spells the player hasn't unlocked yet are kept and take effect once unlocked.
+func (gs *GameState) ApplyLoadoutPreset(name string) bool {
+	preset := gs.GetLoadoutPreset(name)
+	if preset == nil {
+		return false
+	}
+	gs.Session.AutoCastEnabled = preset.AutoCastEnabled
+	gs.Session.AutoCastConfigs = append([]AutoCastSlotConfig{}, preset.AutoCastConfigs...)
+	gs.Session.AutoCastSlots = make([]string, len(preset.AutoCastConfigs))
+	for i, config := range preset.AutoCastConfigs {
+		gs.Session.AutoCastSlots[i] = config.SpellID
+	}
+	if preset.Rotation != nil {
+		rotation := *preset.Rotation
+		rotation.Spells = append([]RotationSpellConfig{}, preset.Rotation.Spells...)
+		gs.Session.Rotation = &rotation
+	}
+	return true
+}
+
+// DeleteLoadoutPreset removes a preset by name.
+func (gs *GameState) DeleteLoadoutPreset(name string) bool {
+	for i, preset := range gs.Presets {
+		if strings.EqualFold(preset.Name, name) {
+			gs.Presets = append(gs.Presets[:i], gs.Presets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// NextPresetName returns an unused default preset name.
+func (gs *GameState) NextPresetName() string {
+	for n := len(gs.Presets) + 1; ; n++ {
+		name := "Loadout " + strconv.Itoa(n)
+		if gs.GetLoadoutPreset(name) == nil {
+			return name
+		}
+	}
+}
+
+// AdjustPrestigeFloor changes the auto-prestige floor, never below the prestige milestone.
+func (a *AutomationState) AdjustPrestigeFloor(delta int) int {
+	a.Prestige.MinFloor += delta
+	if a.Prestige.MinFloor < PrestigeMilestone {
+		a.Prestige.MinFloor = PrestigeMilestone
+	}
+	return a.Prestige.MinFloor
+}
+
+// AdjustPrestigeRate changes the floors-per-hour threshold for auto-prestige.
+func (a *AutomationState) AdjustPrestigeRate(delta float64) float64 {
+	a.Prestige.MaxFloorsPerHour = math.Max(0, a.Prestige.MaxFloorsPerHour+delta)
+	return a.Prestige.MaxFloorsPerHour
+}
+
+// AdjustUpgradeThreshold changes the auto-upgrade mana threshold (multiple of floor cost).
+func (a *AutomationState) AdjustUpgradeThreshold(delta float64) float64 {
+	a.Upgrade.ManaAbove = math.Max(1.0, math.Min(10.0, a.Upgrade.ManaAbove+delta))
+	return a.Upgrade.ManaAbove
+}
+
+// CycleFloorEventChoice moves the auto-picked floor event choice by direction.
+func (a *AutomationState) CycleFloorEventChoice(direction int) FloorEventChoice {
+	idx := 0
+	for i, choice := range FloorEventChoiceCycle {
+		if choice == a.FloorEvent.Choice {
+			idx = i
+			break
+		}
+	}
+	n := len(FloorEventChoiceCycle)
+	a.FloorEvent.Choice = FloorEventChoiceCycle[((idx+direction)%n+n)%n]
+	return a.FloorEvent.Choice
+}
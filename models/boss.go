@@ -0,0 +1,93 @@
+package models
+
+// Boss is a boss definition. A boss guards every tenth floor: once the
+// floor's sigil is charged it has to be beaten within the time limit.
+type Boss struct {
+	ID          string
+	Name        string
+	Description string
+
+	HPMultiplier float64             // Scales the floor's base boss HP
+	Resistances  map[Element]float64 // Damage reduction by element; negative is a weakness
+
+	// Only ShieldElement spells damage the shield, and nothing reaches the
+	// boss's HP until it breaks
+	ShieldElement  Element
+	ShieldFraction float64 // Shield HP as a fraction of max HP
+
+	// After EnrageAfterMs the boss heals EnrageRegenPerSec of its max HP
+	// every second; 0 never enrages
+	EnrageAfterMs     int64
+	EnrageRegenPerSec float64
+
+	ArtifactID string // Granted the first time the boss is beaten
+}
+
+// Resistance returns the boss's damage reduction against element.
+func (b *Boss) Resistance(element Element) float64 {
+	return b.Resistances[element]
+}
+
+// Artifact is a permanent bonus dropped by a boss. Artifacts are kept
+// across prestige.
+type Artifact struct {
+	ID          string
+	Name        string
+	Description string
+
+	ManaGenBonus       float64 // +% mana/sec
+	SigilChargeBonus   float64 // +% sigil charge
+	BossDamageBonus    float64 // +% damage to bosses
+	Element            Element
+	ElementDamageBonus float64 // +% damage for Element spells
+}
+
+// BossEncounter is a boss fight in progress.
+type BossEncounter struct {
+	BossID        string             `bson:"boss_id" json:"boss_id"`
+	Floor         int                `bson:"floor" json:"floor"`
+	MaxHP         float64            `bson:"max_hp" json:"max_hp"`
+	HP            float64            `bson:"hp" json:"hp"`
+	MaxShield     float64            `bson:"max_shield" json:"max_shield"`
+	Shield        float64            `bson:"shield" json:"shield"`
+	ShieldElement Element            `bson:"shield_element" json:"shield_element"` // "" when the boss has no shield this fight
+	TimeLimitMs   int64              `bson:"time_limit_ms" json:"time_limit_ms"`
+	ElapsedMs     int64              `bson:"elapsed_ms" json:"elapsed_ms"`
+	Enraged       bool               `bson:"enraged" json:"enraged"`
+	Damage        map[string]float64 `bson:"damage" json:"damage"` // Damage dealt by spell ID, for spell XP
+}
+
+// RemainingMs returns the time left to beat the boss.
+func (b *BossEncounter) RemainingMs() int64 {
+	remaining := b.TimeLimitMs - b.ElapsedMs
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// HPProgress returns the boss's HP left (0.0 to 1.0).
+func (b *BossEncounter) HPProgress() float64 {
+	if b.MaxHP <= 0 {
+		return 0
+	}
+	return b.HP / b.MaxHP
+}
+
+// ShieldProgress returns the boss's shield left (0.0 to 1.0).
+func (b *BossEncounter) ShieldProgress() float64 {
+	if b.MaxShield <= 0 {
+		return 0
+	}
+	return b.Shield / b.MaxShield
+}
+
+// Shielded returns true while the boss's shield is up.
+func (b *BossEncounter) Shielded() bool {
+	return b.Shield > 0
+}
+
+// Defeated returns true once the boss has no HP left.
+func (b *BossEncounter) Defeated() bool {
+	return b.HP <= 0
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// ChallengeSlot is the save slot challenge runs are kept in, away from the
+// player's own saves.
+const ChallengeSlot = 100
+
+// ChallengeModifier is a rule that changes how a challenge plays. Zero
+// values leave the game unchanged.
+type ChallengeModifier struct {
+	ID          string
+	Name        string
+	Description string
+	Group       string // A challenge has at most one modifier per group
+
+	DisabledElement       Element // Spells of this element can't be cast
+	ManaGenMultiplier     float64
+	SigilChargeMultiplier float64
+	NoManualCasting       bool
+	FloorEventInterval    int // Floors between floor events
+}
+
+// Challenge is a seeded set of modifiers and a goal, the same for everyone
+// who plays it.
+type Challenge struct {
+	ID          string   `bson:"id" json:"id"` // e.g. "2026-W42"
+	Seed        int64    `bson:"seed" json:"seed"`
+	Modifiers   []string `bson:"modifiers" json:"modifiers"` // ChallengeModifier IDs
+	TargetFloor int      `bson:"target_floor" json:"target_floor"`
+}
+
+// ChallengeRun is a game's attempt at a challenge. Only time spent playing
+// counts; offline time doesn't.
+type ChallengeRun struct {
+	Challenge   Challenge  `bson:"challenge" json:"challenge"`
+	StartedAt   time.Time  `bson:"started_at" json:"started_at"` // Names the run's replay
+	ElapsedMs   int64      `bson:"elapsed_ms" json:"elapsed_ms"`
+	Ticks       int64      `bson:"ticks" json:"ticks"`
+	RNGDraws    int64      `bson:"rng_draws" json:"rng_draws"` // Values drawn from the seeded RNG so far
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ReplayHash  string     `bson:"replay_hash,omitempty" json:"replay_hash,omitempty"`
+}
+
+// NewChallengeRun starts a run of c.
+func NewChallengeRun(c Challenge) *ChallengeRun {
+	return &ChallengeRun{Challenge: c, StartedAt: time.Now().UTC().Truncate(time.Second)}
+}
+
+// Complete reports whether the run reached its target floor.
+func (r *ChallengeRun) Complete() bool {
+	return r.CompletedAt != nil
+}
+
+// Result returns the outcome of a completed run.
+func (r *ChallengeRun) Result() ChallengeResult {
+	result := ChallengeResult{
+		ChallengeID: r.Challenge.ID,
+		Seed:        r.Challenge.Seed,
+		TargetFloor: r.Challenge.TargetFloor,
+		ElapsedMs:   r.ElapsedMs,
+		ReplayHash:  r.ReplayHash,
+	}
+	if r.CompletedAt != nil {
+		result.CompletedAt = *r.CompletedAt
+	}
+	return result
+}
+
+// ChallengeResult is a completed challenge run, kept on the player's
+// profile.
+type ChallengeResult struct {
+	ChallengeID string    `bson:"challenge_id" json:"challenge_id"`
+	Seed        int64     `bson:"seed" json:"seed"`
+	TargetFloor int       `bson:"target_floor" json:"target_floor"`
+	ElapsedMs   int64     `bson:"elapsed_ms" json:"elapsed_ms"`
+	ReplayHash  string    `bson:"replay_hash" json:"replay_hash"`
+	CompletedAt time.Time `bson:"completed_at" json:"completed_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,6 +21,11 @@ type GameState struct {
 	PassiveBonuses    *PassiveBonuses    `bson:"passive_bonuses" json:"passive_bonuses"`
 	PrestigeData      *PrestigeData      `bson:"prestige" json:"prestige"`
 	Session           *SessionData       `bson:"session" json:"session"`
+	Automation        *AutomationState   `bson:"automation,omitempty" json:"automation,omitempty"`
+	Presets           []*LoadoutPreset   `bson:"presets,omitempty" json:"presets,omitempty"`
+	Ledger            *StatsLedger       `bson:"ledger,omitempty" json:"ledger,omitempty"`
+	Challenge         *ChallengeRun      `bson:"challenge,omitempty" json:"challenge,omitempty"` // Nil outside challenge runs
+	Boss              *BossEncounter     `bson:"boss,omitempty" json:"boss,omitempty"`           // Nil outside boss fights
 	SavedAt           time.Time          `bson:"saved_at" json:"saved_at"`
 	Version           int                `bson:"version" json:"version"`
 }
@@ -29,6 +36,7 @@ type PassiveBonuses struct {
 	FloorClimbSpeed        float64 `bson:"floor_climb_speed" json:"floor_climb_speed"`
 	SpellCooldownReduction float64 `bson:"spell_cooldown_reduction" json:"spell_cooldown_reduction"`
 	RitualCapacity         int     `bson:"ritual_capacity" json:"ritual_capacity"`
+	AchievementManaGen     float64 `bson:"achievement_mana_gen" json:"achievement_mana_gen"` // Mana gen bonus from achievements (0.05 = +5%)
 }
 
 // AutoCastCondition defines when an auto-cast slot should trigger.
@@ -89,6 +97,9 @@ type SessionData struct {
 	// v1.5.0: Advanced Spell Rotation
 	Rotation *SpellRotation `bson:"rotation,omitempty" json:"rotation,omitempty"`
 
+	// Time-series samples for graphs
+	Samples *SampleBuffer `bson:"samples,omitempty" json:"samples,omitempty"`
+
 	// Floor Events (Lightweight)
 	ActiveFloorEvent    *FloorEventState `bson:"active_floor_event,omitempty" json:"active_floor_event,omitempty"`
 	ActiveFloorBuff     *FloorEventBuff  `bson:"active_floor_buff,omitempty" json:"active_floor_buff,omitempty"`
@@ -146,6 +157,44 @@ func NewSessionData() *SessionData {
 	}
 }
 
+// ResumeSessionData starts a new session that keeps the player's configured
+// loadout (auto-cast slots, rotation, floor buff) from a previous session.
+// Timers and streaks are reset.
+func ResumeSessionData(prev *SessionData) *SessionData {
+	session := NewSessionData()
+	if prev == nil {
+		return session
+	}
+
+	session.AutoCastEnabled = prev.AutoCastEnabled
+	if prev.AutoCastSlots != nil {
+		session.AutoCastSlots = prev.AutoCastSlots
+	}
+	if prev.AutoCastConfigs != nil {
+		session.AutoCastConfigs = prev.AutoCastConfigs
+	}
+	if prev.Rotation != nil {
+		session.Rotation = prev.Rotation
+	}
+	session.ActiveFloorBuff = prev.ActiveFloorBuff
+	session.LastFloorEventFloor = prev.LastFloorEventFloor
+	return session
+}
+
+// Clone returns a deep copy of the game state, e.g. for simulations that
+// must not touch the live save. Transient fields are not copied.
+func (gs *GameState) Clone() *GameState {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		return nil
+	}
+	clone := &GameState{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil
+	}
+	return clone
+}
+
 // GetSpellByID returns a spell from the player's list by ID.
 func (gs *GameState) GetSpellByID(spellID string) *Spell {
 	for _, spell := range gs.Spells {
@@ -193,7 +242,11 @@ func (gs *GameState) CanAddRitual() bool {
 
 // UpdateSession updates session timing data.
 func (gs *GameState) UpdateSession() {
-	now := time.Now()
+	gs.UpdateSessionAt(time.Now())
+}
+
+// UpdateSessionAt updates session timing data as of now.
+func (gs *GameState) UpdateSessionAt(now time.Time) {
 	gs.Session.LastTickMs = now.UnixMilli()
 	gs.Session.SessionDuration = now.UnixMilli() - gs.Session.SessionStartMs
 }
@@ -440,23 +493,38 @@ func (gs *GameState) CheckElementSynergy() Element {
 
 // ActivateSynergy activates an element synergy buff.
 func (gs *GameState) ActivateSynergy(element Element, durationMs int64) {
+	gs.ActivateSynergyAt(element, durationMs, time.Now().UnixMilli())
+}
+
+// ActivateSynergyAt activates an element synergy buff relative to nowMs.
+func (gs *GameState) ActivateSynergyAt(element Element, durationMs int64, nowMs int64) {
 	gs.Session.ActiveSynergy = element
-	gs.Session.SynergyExpiresAtMs = time.Now().UnixMilli() + durationMs
+	gs.Session.SynergyExpiresAtMs = nowMs + durationMs
 	// Clear streak so it must be rebuilt
 	gs.Session.LastCastElements = []Element{}
 }
 
 // HasActiveSynergy returns true if a synergy buff is currently active.
 func (gs *GameState) HasActiveSynergy() bool {
+	return gs.HasActiveSynergyAt(time.Now().UnixMilli())
+}
+
+// HasActiveSynergyAt returns true if a synergy buff is active at nowMs.
+func (gs *GameState) HasActiveSynergyAt(nowMs int64) bool {
 	if gs.Session.ActiveSynergy == "" {
 		return false
 	}
-	return time.Now().UnixMilli() < gs.Session.SynergyExpiresAtMs
+	return nowMs < gs.Session.SynergyExpiresAtMs
 }
 
 // GetActiveSynergy returns the active synergy element, or empty if none.
 func (gs *GameState) GetActiveSynergy() Element {
-	if gs.HasActiveSynergy() {
+	return gs.GetActiveSynergyAt(time.Now().UnixMilli())
+}
+
+// GetActiveSynergyAt returns the synergy element active at nowMs, or empty if none.
+func (gs *GameState) GetActiveSynergyAt(nowMs int64) Element {
+	if gs.HasActiveSynergyAt(nowMs) {
 		return gs.Session.ActiveSynergy
 	}
 	return ""
@@ -464,14 +532,15 @@ func (gs *GameState) GetActiveSynergy() Element {
 
 // GetSynergyTimeRemaining returns milliseconds remaining on synergy buff.
 func (gs *GameState) GetSynergyTimeRemaining() int64 {
-	if !gs.HasActiveSynergy() {
-		return 0
-	}
-	remaining := gs.Session.SynergyExpiresAtMs - time.Now().UnixMilli()
-	if remaining < 0 {
+	return gs.GetSynergyTimeRemainingAt(time.Now().UnixMilli())
+}
+
+// GetSynergyTimeRemainingAt returns milliseconds remaining on synergy buff at nowMs.
+func (gs *GameState) GetSynergyTimeRemainingAt(nowMs int64) int64 {
+	if !gs.HasActiveSynergyAt(nowMs) {
 		return 0
 	}
-	return remaining
+	return gs.Session.SynergyExpiresAtMs - nowMs
 }
 
 // ResetForPrestige resets appropriate data for prestige.
@@ -498,7 +567,8 @@ func (gs *GameState) ResetForPrestige(baseSpells []*Spell) {
 	gs.PassiveBonuses.SpellCooldownReduction = gs.PrestigeData.SpellCooldownReduction
 	gs.PassiveBonuses.RitualCapacity = gs.PrestigeData.RitualCapacity
 
-	// Clear transient floor-event state (floors reset)
+	// Clear transient floor-event and boss state (floors reset)
+	gs.Boss = nil
 	if gs.Session != nil {
 		gs.Session.ActiveFloorEvent = nil
 		gs.Session.ActiveFloorBuff = nil
@@ -620,3 +690,79 @@ func (gs *GameState) GetRotationConfig(spellID string) *RotationSpellConfig {
 
 	return nil
 }
+
+// MoveRotationSpell moves a spell up (-1) or down (+1) in the rotation order.
+// Returns true if moved.
+func (gs *GameState) MoveRotationSpell(spellID string, direction int) bool {
+	gs.EnsureRotation()
+	spells := gs.Session.Rotation.Spells
+
+	for i := range spells {
+		if spells[i].SpellID != spellID {
+			continue
+		}
+		target := i + direction
+		if target < 0 || target >= len(spells) {
+			return false
+		}
+		spells[i], spells[target] = spells[target], spells[i]
+		return true
+	}
+	return false
+}
+
+// CycleRotationPriority cycles a rotation spell's priority High -> Medium -> Low.
+func (gs *GameState) CycleRotationPriority(spellID string) RotationPriority {
+	config := gs.GetRotationConfig(spellID)
+	if config == nil {
+		return PriorityMedium
+	}
+
+	switch config.Priority {
+	case PriorityHigh:
+		config.Priority = PriorityMedium
+	case PriorityMedium:
+		config.Priority = PriorityLow
+	default:
+		config.Priority = PriorityHigh
+	}
+	return config.Priority
+}
+
+// CycleRotationCondition cycles a rotation spell to the next built-in condition.
+// A custom expression is kept so it can be re-enabled from the editor.
+func (gs *GameState) CycleRotationCondition(spellID string) RotationCondition {
+	config := gs.GetRotationConfig(spellID)
+	if config == nil {
+		return RotationConditionAlways
+	}
+
+	next := RotationConditionAlways
+	for i, cond := range RotationConditionCycle {
+		if cond == config.Condition {
+			next = RotationConditionCycle[(i+1)%len(RotationConditionCycle)]
+			break
+		}
+	}
+	config.Condition = next
+	return next
+}
+
+// AdjustRotationManaThreshold changes the rotation's mana reserve by delta,
+// clamped to [0, MaxRotationManaThreshold]. Returns the new threshold.
+func (gs *GameState) AdjustRotationManaThreshold(delta float64) float64 {
+	gs.EnsureRotation()
+	rotation := gs.Session.Rotation
+
+	threshold := rotation.ManaThreshold + delta
+	// Round to whole percents so repeated steps don't drift
+	threshold = math.Round(threshold*100) / 100
+	if threshold < 0 {
+		threshold = 0
+	}
+	if threshold > MaxRotationManaThreshold {
+		threshold = MaxRotationManaThreshold
+	}
+	rotation.ManaThreshold = threshold
+	return threshold
+}
@@ -0,0 +1,63 @@
+package models
+
+import "sort"
+
+// Leaderboard ranks players by one of their lifetime stats, which live on
+// the profile so they outlast prestige and span every save slot.
+type Leaderboard struct {
+	ID            string
+	Name          string
+	Stat          AchievementStat
+	LowerIsBetter bool // Best times rank first
+}
+
+// Leaderboards lists the leaderboards in display order.
+var Leaderboards = []Leaderboard{
+	{ID: "highest_floor", Name: "Highest Floor", Stat: StatHighestFloor},
+	{ID: "most_eras", Name: "Most Eras", Stat: StatAscensions},
+	{ID: "fastest_floor_100", Name: "Fastest Floor 100", Stat: StatFastestFloor100Ms, LowerIsBetter: true},
+	{ID: "highest_cast", Name: "Highest Cast", Stat: StatHighestCast},
+}
+
+// LeaderboardEntry is one player's place on a leaderboard.
+type LeaderboardEntry struct {
+	Rank       int     `bson:"-" json:"rank"`
+	PlayerUUID string  `bson:"uuid" json:"player_uuid"`
+	Username   string  `bson:"username" json:"username"`
+	Value      float64 `bson:"value" json:"value"`
+}
+
+// Value returns the player's stat for the leaderboard; 0 means they have
+// no entry yet.
+func (b Leaderboard) Value(p *Player) float64 {
+	if p == nil || p.Achievements == nil {
+		return 0
+	}
+	return p.Achievements.Stats[b.Stat]
+}
+
+// Better reports whether value x ranks above value y.
+func (b Leaderboard) Better(x, y float64) bool {
+	if b.LowerIsBetter {
+		return x < y
+	}
+	return x > y
+}
+
+// Rank sorts entries best first, by name among equals, and numbers them;
+// tied values share a rank.
+func (b Leaderboard) Rank(entries []LeaderboardEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return b.Better(entries[i].Value, entries[j].Value)
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	for i := range entries {
+		if i > 0 && entries[i].Value == entries[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+}
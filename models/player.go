@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,6 +17,10 @@ type Player struct {
 	PlayDurationMs     int64              `bson:"play_duration_ms" json:"play_duration_ms"`
 	TotalPrestigeCount int                `bson:"total_prestige_count" json:"total_prestige_count"`
 	CurrentSaveSlot    int                `bson:"current_save_slot" json:"current_save_slot"`
+	Achievements       *AchievementState  `bson:"achievements,omitempty" json:"achievements,omitempty"`
+	Tutorial           *TutorialState     `bson:"tutorial,omitempty" json:"tutorial,omitempty"`
+	Theme              string             `bson:"theme,omitempty" json:"theme,omitempty"`                         // UI theme name (empty = default)
+	ChallengeResults   []ChallengeResult  `bson:"challenge_results,omitempty" json:"challenge_results,omitempty"` // Best run per challenge
 	Version            int                `bson:"version" json:"version"`
 }
 
@@ -34,6 +39,20 @@ func NewPlayer(uuid, username string) *Player {
 	}
 }
 
+// Clone returns a deep copy of the player, e.g. to write it out while the
+// game goes on changing the original.
+func (p *Player) Clone() *Player {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil
+	}
+	clone := &Player{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil
+	}
+	return clone
+}
+
 // UpdateLastPlayed updates the last played timestamp.
 func (p *Player) UpdateLastPlayed() {
 	p.LastPlayed = time.Now()
@@ -43,3 +62,28 @@ func (p *Player) UpdateLastPlayed() {
 func (p *Player) AddPlayDuration(durationMs int64) {
 	p.PlayDurationMs += durationMs
 }
+
+// ChallengeResult returns the player's best run of a challenge, or nil.
+func (p *Player) ChallengeResult(challengeID string) *ChallengeResult {
+	for i := range p.ChallengeResults {
+		if p.ChallengeResults[i].ChallengeID == challengeID {
+			return &p.ChallengeResults[i]
+		}
+	}
+	return nil
+}
+
+// RecordChallengeResult keeps result if it's the player's first or fastest
+// run of its challenge, and reports whether it did.
+func (p *Player) RecordChallengeResult(result ChallengeResult) bool {
+	best := p.ChallengeResult(result.ChallengeID)
+	if best == nil {
+		p.ChallengeResults = append(p.ChallengeResults, result)
+		return true
+	}
+	if result.ElapsedMs >= best.ElapsedMs {
+		return false
+	}
+	*best = result
+	return true
+}
@@ -16,6 +16,8 @@ type PrestigeData struct {
 	AutoCastSlotBonus          int         `bson:"auto_cast_slot_bonus" json:"auto_cast_slot_bonus"` // Extra auto-cast slots from prestige
 	UnlockedPrestigeSpells     []string    `bson:"unlocked_prestige_spells" json:"unlocked_prestige_spells"`
 	PrestigeEvents             []time.Time `bson:"prestige_events" json:"prestige_events"`
+	Artifacts                  []string    `bson:"artifacts,omitempty" json:"artifacts,omitempty"` // Dropped by bosses, kept forever
+	BossesDefeated             int         `bson:"bosses_defeated" json:"bosses_defeated"`
 }
 
 // PrestigeMilestone is the floor required to prestige.
@@ -101,6 +103,20 @@ func (p *PrestigeData) GetTotalMultiplier() float64 {
 	return p.EraMultiplier * p.PermanentManaGenMultiplier
 }
 
+// HasArtifact returns true if a boss has dropped the artifact.
+func (p *PrestigeData) HasArtifact(id string) bool {
+	return contains(p.Artifacts, id)
+}
+
+// AddArtifact keeps an artifact. Returns false if it was already kept.
+func (p *PrestigeData) AddArtifact(id string) bool {
+	if p.HasArtifact(id) {
+		return false
+	}
+	p.Artifacts = append(p.Artifacts, id)
+	return true
+}
+
 // contains checks if a string is in a slice.
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
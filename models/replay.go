@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// ReplayVersion is the format of replays written by this version.
+const ReplayVersion = 1
+
+// ReplayActionType is what a player did in a recorded action.
+type ReplayActionType string
+
+// Replay action types
+const (
+	ReplayCast              ReplayActionType = "cast"
+	ReplayUpgrade           ReplayActionType = "upgrade"
+	ReplaySpecialize        ReplayActionType = "specialize"
+	ReplayCreateRitual      ReplayActionType = "create_ritual"
+	ReplayToggleRitual      ReplayActionType = "toggle_ritual"
+	ReplayRemoveRitual      ReplayActionType = "remove_ritual"
+	ReplayResetRituals      ReplayActionType = "reset_rituals"
+	ReplayFloorEvent        ReplayActionType = "floor_event"
+	ReplayDismissFloorEvent ReplayActionType = "dismiss_floor_event"
+	ReplayPrestige          ReplayActionType = "prestige"
+	ReplaySettingsEdit      ReplayActionType = "settings" // Loadout, rotation, automation or preset edits
+)
+
+// Replay is a recorded stretch of play: the state it started from, the RNG
+// seed and every player action, by tick. Running the actions through the
+// engine from the same state must end at FinalHash.
+type Replay struct {
+	Version   int            `json:"version"`
+	Seed      int64          `json:"seed"`
+	StartedAt time.Time      `json:"started_at"` // The engine's clock at tick 0
+	TickMs    int64          `json:"tick_ms"`
+	Initial   *GameState     `json:"initial"`
+	Actions   []ReplayAction `json:"actions"`
+	Ticks     int64          `json:"ticks"`      // Ticks recorded so far
+	FinalHash string         `json:"final_hash"` // State hash after Ticks ticks and every action
+}
+
+// ReplayAction is one player action, taken after Tick ticks.
+type ReplayAction struct {
+	Tick     int64               `json:"tick"`
+	Type     ReplayActionType    `json:"type"`
+	SpellID  string              `json:"spell_id,omitempty"`
+	SpellIDs []string            `json:"spell_ids,omitempty"`
+	RitualID string              `json:"ritual_id,omitempty"`
+	Spec     SpellSpecialization `json:"spec,omitempty"`
+	Choice   FloorEventChoice    `json:"choice,omitempty"`
+	Manual   bool                `json:"manual,omitempty"`
+	Settings *ReplaySettings     `json:"settings,omitempty"`
+}
+
+// ReplaySettings is the configuration a player edits without any immediate
+// effect on the game: the auto-cast loadout, rotation, automation rules and
+// presets. Edits are recorded as the settings they leave behind.
+type ReplaySettings struct {
+	AutoCastEnabled    bool                 `json:"auto_cast_enabled"`
+	AutoCastSlots      []string             `json:"auto_cast_slots"`
+	AutoCastConfigs    []AutoCastSlotConfig `json:"auto_cast_configs"`
+	Rotation           *SpellRotation       `json:"rotation,omitempty"`
+	Automation         *AutomationState     `json:"automation,omitempty"`
+	Presets            []*LoadoutPreset     `json:"presets,omitempty"`
+	AchievementManaGen float64              `json:"achievement_mana_gen"` // Granted by achievements, which live outside the game
+}
@@ -17,8 +17,26 @@ const (
 	RotationConditionSigilAlmostFull RotationCondition = "sigil_almost_full" // Only when sigil > 80%
 	RotationConditionHighPriority    RotationCondition = "high_priority"     // Always cast ASAP (ignore mana efficiency)
 	RotationConditionFillerOnly      RotationCondition = "filler_only"       // Only when nothing else can cast
+
+	// Custom condition: evaluate RotationSpellConfig.Expression
+	RotationConditionExpression RotationCondition = "expression"
 )
 
+// RotationConditionCycle is the order conditions are cycled through in the editor.
+// Custom expressions are entered separately.
+var RotationConditionCycle = []RotationCondition{
+	RotationConditionAlways,
+	RotationConditionManaAbove50,
+	RotationConditionManaAbove75,
+	RotationConditionSigilNotFull,
+	RotationConditionSynergyActive,
+	RotationConditionManaEfficient,
+	RotationConditionDuringSynergy,
+	RotationConditionSigilAlmostFull,
+	RotationConditionHighPriority,
+	RotationConditionFillerOnly,
+}
+
 // RotationPriority defines spell priority tiers.
 type RotationPriority int
 
@@ -34,6 +52,10 @@ type RotationSpellConfig struct {
 	Priority  RotationPriority  `bson:"priority" json:"priority"`
 	Condition RotationCondition `bson:"condition" json:"condition"`
 	Enabled   bool              `bson:"enabled" json:"enabled"` // Can disable without removing from rotation
+
+	// Expression is evaluated when Condition is RotationConditionExpression,
+	// e.g. `mana_pct > 0.6 && !synergy(fire) && cd("spell_inferno") < 2s`.
+	Expression string `bson:"expression,omitempty" json:"expression,omitempty"`
 }
 
 // SpellRotation holds the complete rotation configuration.
@@ -68,6 +90,8 @@ func GetConditionDescription(cond RotationCondition) string {
 		return "High priority - cast ASAP"
 	case RotationConditionFillerOnly:
 		return "Filler - only when nothing else ready"
+	case RotationConditionExpression:
+		return "Custom expression"
 	default:
 		return "Unknown condition"
 	}
@@ -87,6 +111,9 @@ func GetPriorityLabel(priority RotationPriority) string {
 	}
 }
 
+// MaxRotationManaThreshold caps the mana reserve so the rotation can always cast.
+const MaxRotationManaThreshold = 0.9
+
 // DefaultRotation creates a default rotation configuration from auto-cast slots.
 func DefaultRotation() *SpellRotation {
 	return &SpellRotation{
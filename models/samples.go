@@ -0,0 +1,86 @@
+package models
+
+// Sample is one point of the time series shown in the Graphs view.
+type Sample struct {
+	AtMs       int64   `bson:"at_ms" json:"at_ms"`
+	ManaPerSec float64 `bson:"mana_per_sec" json:"mana_per_sec"`
+	SigilDPS   float64 `bson:"sigil_dps" json:"sigil_dps"` // Sigil charge per second since the previous sample
+	Floor      int     `bson:"floor" json:"floor"`
+}
+
+// SampleBuffer is a fixed-size ring buffer of samples. Once full, each new
+// sample overwrites the oldest one.
+type SampleBuffer struct {
+	Samples []Sample `bson:"samples" json:"samples"`
+	Next    int      `bson:"next" json:"next"` // Index the next sample is written to once full
+	Size    int      `bson:"size" json:"size"`
+
+	// Accumulators for the sample in progress
+	LastSampleMs     int64   `bson:"last_sample_ms" json:"last_sample_ms"`
+	SigilSinceSample float64 `bson:"sigil_since_sample" json:"sigil_since_sample"`
+}
+
+// NewSampleBuffer creates an empty buffer holding up to size samples.
+func NewSampleBuffer(size int, nowMs int64) *SampleBuffer {
+	return &SampleBuffer{
+		Samples:      make([]Sample, 0, size),
+		Size:         size,
+		LastSampleMs: nowMs,
+	}
+}
+
+// Add appends a sample, overwriting the oldest once the buffer is full.
+func (b *SampleBuffer) Add(sample Sample) {
+	if b.Size <= 0 {
+		return
+	}
+	if len(b.Samples) < b.Size {
+		b.Samples = append(b.Samples, sample)
+		return
+	}
+	b.Samples[b.Next] = sample
+	b.Next = (b.Next + 1) % b.Size
+}
+
+// Len returns the number of samples recorded.
+func (b *SampleBuffer) Len() int {
+	return len(b.Samples)
+}
+
+// Ordered returns the samples oldest first.
+func (b *SampleBuffer) Ordered() []Sample {
+	ordered := make([]Sample, 0, len(b.Samples))
+	if len(b.Samples) < b.Size {
+		return append(ordered, b.Samples...)
+	}
+	ordered = append(ordered, b.Samples[b.Next:]...)
+	return append(ordered, b.Samples[:b.Next]...)
+}
+
+// Recent returns up to n of the newest samples, oldest first.
+func (b *SampleBuffer) Recent(n int) []Sample {
+	ordered := b.Ordered()
+	if n < len(ordered) {
+		return ordered[len(ordered)-n:]
+	}
+	return ordered
+}
+
+// EnsureSamples initializes the sample buffer for sessions that predate it,
+// or resizes it (keeping the newest samples) when size changes.
+func (gs *GameState) EnsureSamples(size int, nowMs int64) *SampleBuffer {
+	buf := gs.Session.Samples
+	if buf == nil {
+		gs.Session.Samples = NewSampleBuffer(size, nowMs)
+		return gs.Session.Samples
+	}
+	if buf.Size != size {
+		resized := NewSampleBuffer(size, buf.LastSampleMs)
+		resized.SigilSinceSample = buf.SigilSinceSample
+		for _, sample := range buf.Recent(size) {
+			resized.Add(sample)
+		}
+		gs.Session.Samples = resized
+	}
+	return gs.Session.Samples
+}
@@ -79,6 +79,7 @@ type Spell struct {
 	CooldownRemainingMs int64     `bson:"cooldown_remaining_ms" json:"cooldown_remaining_ms"`
 	LastCastTime        time.Time `bson:"last_cast_time" json:"last_cast_time"`
 	CastCount           int       `bson:"cast_count" json:"cast_count"`
+	XP                  float64   `bson:"xp" json:"xp"` // Earned beating bosses; levels the spell up for free
 
 	// Specializations chosen at milestone levels
 	Tier1Spec SpellSpecialization `bson:"tier1_spec" json:"tier1_spec"` // Chosen at level 5
@@ -0,0 +1,163 @@
+package models
+
+import "time"
+
+// MaxLedgerEras limits how many finished eras the stats ledger keeps.
+const MaxLedgerEras = 100
+
+// EraStats are the statistics collected during one era (or, summed, a lifetime).
+type EraStats struct {
+	Era       int       `bson:"era" json:"era"`
+	StartedAt time.Time `bson:"started_at" json:"started_at"`
+	EndedAt   time.Time `bson:"ended_at,omitempty" json:"ended_at,omitempty"` // Zero while the era is in progress
+
+	Casts           int                 `bson:"casts" json:"casts"`
+	Crits           int                 `bson:"crits" json:"crits"`
+	DamageByElement map[Element]float64 `bson:"damage_by_element" json:"damage_by_element"`
+	HighestCast     float64             `bson:"highest_cast" json:"highest_cast"` // Highest single-cast damage
+
+	ManaOnCasts    float64 `bson:"mana_on_casts" json:"mana_on_casts"`
+	ManaOnUpgrades float64 `bson:"mana_on_upgrades" json:"mana_on_upgrades"`
+	ManaOnFloors   float64 `bson:"mana_on_floors" json:"mana_on_floors"`
+
+	FloorsClimbed int `bson:"floors_climbed" json:"floors_climbed"`
+	HighestFloor  int `bson:"highest_floor" json:"highest_floor"`
+
+	// Time spent waiting on each floor requirement (online play only)
+	SigilGatedMs int64 `bson:"sigil_gated_ms" json:"sigil_gated_ms"`
+	ManaGatedMs  int64 `bson:"mana_gated_ms" json:"mana_gated_ms"`
+}
+
+// NewEraStats starts statistics for an era.
+func NewEraStats(era int, startedAt time.Time) *EraStats {
+	return &EraStats{
+		Era:             era,
+		StartedAt:       startedAt,
+		DamageByElement: map[Element]float64{},
+	}
+}
+
+// TotalDamage returns damage dealt across all elements.
+func (s *EraStats) TotalDamage() float64 {
+	total := 0.0
+	for _, damage := range s.DamageByElement {
+		total += damage
+	}
+	return total
+}
+
+// TotalManaSpent returns mana spent on casts, upgrades and floors.
+func (s *EraStats) TotalManaSpent() float64 {
+	return s.ManaOnCasts + s.ManaOnUpgrades + s.ManaOnFloors
+}
+
+// CritRate returns the fraction of casts that were critical hits.
+func (s *EraStats) CritRate() float64 {
+	if s.Casts == 0 {
+		return 0
+	}
+	return float64(s.Crits) / float64(s.Casts)
+}
+
+// Duration returns how long the era lasted, or has lasted so far.
+func (s *EraStats) Duration(now time.Time) time.Duration {
+	end := s.EndedAt
+	if end.IsZero() {
+		end = now
+	}
+	if end.Before(s.StartedAt) {
+		return 0
+	}
+	return end.Sub(s.StartedAt)
+}
+
+// FloorsPerHour returns the average climb rate over the era.
+func (s *EraStats) FloorsPerHour(now time.Time) float64 {
+	hours := s.Duration(now).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return float64(s.FloorsClimbed) / hours
+}
+
+// add sums other into s (used for lifetime totals).
+func (s *EraStats) add(other *EraStats) {
+	s.Casts += other.Casts
+	s.Crits += other.Crits
+	for element, damage := range other.DamageByElement {
+		s.DamageByElement[element] += damage
+	}
+	if other.HighestCast > s.HighestCast {
+		s.HighestCast = other.HighestCast
+	}
+	s.ManaOnCasts += other.ManaOnCasts
+	s.ManaOnUpgrades += other.ManaOnUpgrades
+	s.ManaOnFloors += other.ManaOnFloors
+	s.FloorsClimbed += other.FloorsClimbed
+	if other.HighestFloor > s.HighestFloor {
+		s.HighestFloor = other.HighestFloor
+	}
+	s.SigilGatedMs += other.SigilGatedMs
+	s.ManaGatedMs += other.ManaGatedMs
+}
+
+// StatsLedger collects statistics per era; it is kept across prestige.
+type StatsLedger struct {
+	Current      *EraStats   `bson:"current" json:"current"`
+	PreviousEras []*EraStats `bson:"previous_eras" json:"previous_eras"` // Oldest first
+}
+
+// EnsureLedger initializes the stats ledger for saves that predate it.
+func (gs *GameState) EnsureLedger(now time.Time) *StatsLedger {
+	if gs.Ledger == nil {
+		gs.Ledger = &StatsLedger{PreviousEras: []*EraStats{}}
+	}
+	if gs.Ledger.Current == nil {
+		gs.Ledger.Current = NewEraStats(gs.PrestigeData.CurrentEra, now)
+	}
+	if gs.Ledger.Current.DamageByElement == nil {
+		gs.Ledger.Current.DamageByElement = map[Element]float64{}
+	}
+	return gs.Ledger
+}
+
+// StartEra closes the current era and begins a new one.
+func (l *StatsLedger) StartEra(era int, now time.Time) {
+	if l.Current != nil {
+		l.Current.EndedAt = now
+		l.PreviousEras = append(l.PreviousEras, l.Current)
+		if len(l.PreviousEras) > MaxLedgerEras {
+			l.PreviousEras = l.PreviousEras[len(l.PreviousEras)-MaxLedgerEras:]
+		}
+	}
+	l.Current = NewEraStats(era, now)
+}
+
+// Lifetime returns totals across every recorded era.
+func (l *StatsLedger) Lifetime() *EraStats {
+	total := NewEraStats(-1, time.Time{})
+	for i, era := range l.PreviousEras {
+		if i == 0 {
+			total.StartedAt = era.StartedAt
+		}
+		total.add(era)
+	}
+	if l.Current != nil {
+		if total.StartedAt.IsZero() {
+			total.StartedAt = l.Current.StartedAt
+		}
+		total.add(l.Current)
+	}
+	return total
+}
+
+// FastestEra returns the shortest completed era, or nil if none has finished.
+func (l *StatsLedger) FastestEra() *EraStats {
+	var fastest *EraStats
+	for _, era := range l.PreviousEras {
+		if fastest == nil || era.Duration(era.EndedAt) < fastest.Duration(fastest.EndedAt) {
+			fastest = era
+		}
+	}
+	return fastest
+}
@@ -11,6 +11,9 @@ type TowerState struct {
 	// Ascension Sigil - damage requirement to climb floors
 	SigilCharge   float64 `bson:"sigil_charge" json:"sigil_charge"`     // Current damage accumulated
 	SigilRequired float64 `bson:"sigil_required" json:"sigil_required"` // Damage needed to unlock ascension
+
+	// Boss floors also need their boss beaten before climbing
+	BossDefeated bool `bson:"boss_defeated" json:"boss_defeated"`
 }
 
 // NewTowerState creates a new tower state at floor 1.
@@ -50,6 +53,7 @@ func (t *TowerState) ClimbFloor() {
 	}
 	// Reset sigil for next floor (will be set by engine)
 	t.SigilCharge = 0
+	t.BossDefeated = false
 }
 
 // AddSigilCharge adds damage to the sigil charge.
@@ -83,6 +87,7 @@ func (t *TowerState) Reset() {
 	t.CurrentFloor = 1
 	t.CurrentMana = 0
 	t.SigilCharge = 0
+	t.BossDefeated = false
 }
 
 // GetFloorProgress returns progress towards next floor (0.0 to 1.0).
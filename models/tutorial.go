@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// TutorialStep is a stage of the first-run tutorial.
+type TutorialStep string
+
+const (
+	TutorialCast       TutorialStep = "cast"
+	TutorialAutoCast   TutorialStep = "auto_cast"
+	TutorialUpgrade    TutorialStep = "upgrade"
+	TutorialRitual     TutorialStep = "ritual"
+	TutorialFloorEvent TutorialStep = "floor_event"
+	TutorialDone       TutorialStep = "done"
+)
+
+// TutorialSteps lists the tutorial stages in order.
+var TutorialSteps = []TutorialStep{
+	TutorialCast,
+	TutorialAutoCast,
+	TutorialUpgrade,
+	TutorialRitual,
+	TutorialFloorEvent,
+}
+
+// TutorialState is a player's progress through the tutorial.
+type TutorialState struct {
+	Step        TutorialStep `bson:"step" json:"step"`
+	Skipped     bool         `bson:"skipped" json:"skipped"`
+	CompletedAt time.Time    `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// NewTutorialState starts the tutorial at its first step.
+func NewTutorialState() *TutorialState {
+	return &TutorialState{Step: TutorialSteps[0]}
+}
+
+// Active returns true while the tutorial is being shown.
+func (t *TutorialState) Active() bool {
+	return t.Step != TutorialDone && !t.Skipped
+}
+
+// StepNumber returns the 1-based position of the current step (0 when done).
+func (t *TutorialState) StepNumber() int {
+	for i, step := range TutorialSteps {
+		if step == t.Step {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Complete advances past step if it is the current one. Returns true if the
+// tutorial moved on.
+func (t *TutorialState) Complete(step TutorialStep, now time.Time) bool {
+	if !t.Active() || t.Step != step {
+		return false
+	}
+	next := t.StepNumber()
+	if next < len(TutorialSteps) {
+		t.Step = TutorialSteps[next]
+		return true
+	}
+	t.Step = TutorialDone
+	t.CompletedAt = now
+	return true
+}
+
+// Skip hides the tutorial without completing it.
+func (t *TutorialState) Skip() {
+	t.Skipped = true
+}
+
+// Restart starts the tutorial over from the first step.
+func (t *TutorialState) Restart() {
+	*t = *NewTutorialState()
+}
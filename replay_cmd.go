@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/storage"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// runReplay runs "manatty replay <file>", which plays a recorded replay
+// through the engine again and checks that it ends in the recorded state.
+func runReplay(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: manatty replay <file>")
+		return 2
+	}
+	replay, err := storage.ReadReplay(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can't read replay: %v\n", err)
+		return 1
+	}
+
+	played := time.Duration(replay.Ticks*replay.TickMs) * time.Millisecond
+	fmt.Printf("Replay started %s: %d actions over %s of play (seed %d)\n",
+		replay.StartedAt.Local().Format("2006-01-02 15:04"), len(replay.Actions), utils.FormatDuration(played), replay.Seed)
+
+	// A challenge result only counts from a new run of the week's seed
+	if replay.Initial != nil && replay.Initial.Challenge != nil {
+		if err := engine.CheckChallengeStart(replay); err != nil {
+			fmt.Fprintf(os.Stderr, "not a challenge result: %v\n", err)
+			return 1
+		}
+	}
+
+	gs, err := engine.Replay(replay)
+	if gs != nil {
+		fmt.Printf("Ended on floor %d, era %d\n", gs.Tower.CurrentFloor, gs.PrestigeData.CurrentEra)
+	}
+	switch {
+	case errors.Is(err, engine.ErrReplayMismatch):
+		fmt.Printf("Mismatch: recorded %.12s, replayed %.12s\n", replay.FinalHash, engine.StateHash(gs))
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "replay failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("OK: state hash %.12s matches\n", replay.FinalHash)
+	if run := gs.Challenge; run != nil && run.Complete() {
+		fmt.Printf("Challenge %s completed in %s: replay hash %.12s\n", run.Challenge.ID,
+			utils.FormatDuration(time.Duration(run.ElapsedMs)*time.Millisecond), run.ReplayHash)
+	}
+	return 0
+}
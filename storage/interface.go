@@ -20,7 +20,8 @@ type SaveStore interface {
 	// Load retrieves a game save by player UUID and slot.
 	Load(ctx context.Context, playerUUID string, slot int) (*models.GameState, error)
 
-	// LoadLatest loads the most recently saved game for a player.
+	// LoadLatest loads the most recently saved game for a player, leaving
+	// out challenge runs.
 	LoadLatest(ctx context.Context, playerUUID string) (*models.GameState, error)
 
 	// ListSaves returns all saves for a player.
@@ -58,4 +59,8 @@ type PlayerStore interface {
 
 	// Delete removes a player.
 	Delete(ctx context.Context, uuid string) error
+
+	// Leaderboard returns the top limit players on board, best first.
+	// Players without a value for the board's stat are left out.
+	Leaderboard(ctx context.Context, board models.Leaderboard, limit int) ([]models.LeaderboardEntry, error)
 }
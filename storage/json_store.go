@@ -16,7 +16,7 @@ import (
 )
 
 // JSONSaveStore implements SaveStore using local JSON files.
-// Saves are stored in ~/.manatty/saves/<player_uuid>/<slot>.json
+// Saves are stored in <data dir>/saves/<player_uuid>/<slot>.json
 // Note: Context cancellation is not supported for file-based storage operations.
 type JSONSaveStore struct {
 	baseDir string
@@ -34,14 +34,10 @@ func validateUUID(id string) error {
 	return nil
 }
 
-// NewJSONSaveStore creates a new JSON-based save store.
-func NewJSONSaveStore() (*JSONSaveStore, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-
-	baseDir := filepath.Join(homeDir, ".manatty", "saves")
+// NewJSONSaveStore creates a new JSON-based save store in dataDir
+// (usually ~/.manatty).
+func NewJSONSaveStore(dataDir string) (*JSONSaveStore, error) {
+	baseDir := filepath.Join(dataDir, "saves")
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, err
 	}
@@ -133,12 +129,19 @@ func (s *JSONSaveStore) Load(ctx context.Context, playerUUID string, slot int) (
 	return &save, nil
 }
 
-// LoadLatest loads the most recently saved game for a player.
+// LoadLatest loads the most recently saved game for a player, leaving out
+// challenge runs.
 func (s *JSONSaveStore) LoadLatest(ctx context.Context, playerUUID string) (*models.GameState, error) {
-	saves, err := s.ListSaves(ctx, playerUUID)
+	all, err := s.ListSaves(ctx, playerUUID)
 	if err != nil {
 		return nil, err
 	}
+	var saves []*models.GameState
+	for _, save := range all {
+		if save.Challenge == nil {
+			saves = append(saves, save)
+		}
+	}
 	if len(saves) == 0 {
 		return nil, ErrSaveNotFound
 	}
@@ -281,20 +284,17 @@ func (s *JSONSaveStore) GetLastSavedTime(ctx context.Context, playerUUID string,
 }
 
 // JSONPlayerStore implements PlayerStore using local JSON files.
+// Players are stored in <data dir>/players/<player_uuid>.json
 // Note: Context cancellation is not supported for file-based storage operations.
 type JSONPlayerStore struct {
 	baseDir string
 	mu      sync.RWMutex
 }
 
-// NewJSONPlayerStore creates a new JSON-based player store.
-func NewJSONPlayerStore() (*JSONPlayerStore, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-
-	baseDir := filepath.Join(homeDir, ".manatty", "players")
+// NewJSONPlayerStore creates a new JSON-based player store in dataDir
+// (usually ~/.manatty).
+func NewJSONPlayerStore(dataDir string) (*JSONPlayerStore, error) {
+	baseDir := filepath.Join(dataDir, "players")
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, err
 	}
@@ -396,6 +396,49 @@ func (s *JSONPlayerStore) GetByUsername(ctx context.Context, username string) (*
 	return nil, ErrPlayerNotFound
 }
 
+// Leaderboard returns the top limit players on board, best first, ranking
+// every profile in the data directory.
+func (s *JSONPlayerStore) Leaderboard(ctx context.Context, board models.Leaderboard, limit int) ([]models.LeaderboardEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranked []models.LeaderboardEntry
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.baseDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var player models.Player
+		if err := json.Unmarshal(data, &player); err != nil {
+			continue
+		}
+
+		if value := board.Value(&player); value > 0 {
+			ranked = append(ranked, models.LeaderboardEntry{PlayerUUID: player.UUID, Username: player.Username, Value: value})
+		}
+	}
+
+	board.Rank(ranked)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
+
 // Update updates an existing player.
 func (s *JSONPlayerStore) Update(ctx context.Context, player *models.Player) error {
 	return s.Create(ctx, player) // Same as create (overwrite)
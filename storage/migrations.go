@@ -28,6 +28,14 @@ func (db *Database) EnsureIndexes(ctx context.Context) error {
 			Keys: bson.D{{Key: "last_played", Value: -1}},
 		},
 	}
+	for _, board := range models.Leaderboards {
+		playerIndexes = append(playerIndexes, mongo.IndexModel{
+			Keys: bson.D{
+				{Key: leaderboardField(board), Value: leaderboardOrder(board)},
+				{Key: "username", Value: 1},
+			},
+		})
+	}
 
 	if _, err := db.Players.Indexes().CreateMany(ctx, playerIndexes); err != nil {
 		return err
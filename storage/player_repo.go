@@ -131,6 +131,43 @@ func (r *PlayerRepository) List(ctx context.Context, limit, offset int64) ([]*mo
 	return players, nil
 }
 
+// Leaderboard returns the top limit players on board, best first. The
+// board's sort index (see EnsureIndexes) serves the match and sort.
+func (r *PlayerRepository) Leaderboard(ctx context.Context, board models.Leaderboard, limit int) ([]models.LeaderboardEntry, error) {
+	field := leaderboardField(board)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{field: bson.M{"$gt": 0}}}},
+		{{Key: "$sort", Value: bson.D{{Key: field, Value: leaderboardOrder(board)}, {Key: "username", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "uuid": 1, "username": 1, "value": "$" + field}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.LeaderboardEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	board.Rank(entries)
+	return entries, nil
+}
+
+// leaderboardField is the player document field a leaderboard ranks by.
+func leaderboardField(board models.Leaderboard) string {
+	return "achievements.stats." + string(board.Stat)
+}
+
+// leaderboardOrder is the sort direction of a leaderboard, best first.
+func leaderboardOrder(board models.Leaderboard) int {
+	if board.LowerIsBetter {
+		return 1
+	}
+	return -1
+}
+
 // Exists checks if a player exists by UUID.
 func (r *PlayerRepository) Exists(ctx context.Context, uuid string) (bool, error) {
 	count, err := r.collection.CountDocuments(ctx, bson.M{"uuid": uuid})
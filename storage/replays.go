@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/models"
+)
+
+// ReplayPath returns where a recording of gs started at start is
+// written: <data dir>/replays/<player_uuid>/slot_<slot>_<start>.json
+func ReplayPath(dataDir string, gs *models.GameState, start time.Time) (string, error) {
+	if err := validateUUID(gs.PlayerUUID); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("slot_%d_%s.json", gs.Slot, start.Format("20060102-150405"))
+	return filepath.Join(dataDir, "replays", gs.PlayerUUID, name), nil
+}
+
+// WriteReplay writes replay to path as JSON, replacing what's there.
+func WriteReplay(path string, replay *models.Replay) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReadReplay reads a replay written by WriteReplay.
+func ReadReplay(path string) (*models.Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var replay models.Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
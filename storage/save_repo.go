@@ -57,12 +57,13 @@ func (r *SaveRepository) Load(ctx context.Context, playerUUID string, slot int)
 	return &save, nil
 }
 
-// LoadLatest loads the most recently saved game for a player.
+// LoadLatest loads the most recently saved game for a player, leaving out
+// challenge runs.
 func (r *SaveRepository) LoadLatest(ctx context.Context, playerUUID string) (*models.GameState, error) {
 	var save models.GameState
 
 	opts := options.FindOne().SetSort(bson.D{{Key: "saved_at", Value: -1}})
-	filter := bson.M{"player_uuid": playerUUID}
+	filter := bson.M{"player_uuid": playerUUID, "challenge": bson.M{"$exists": false}}
 
 	err := r.collection.FindOne(ctx, filter, opts).Decode(&save)
 	if err == mongo.ErrNoDocuments {
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/engine"
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/ui/components"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// bossToastKey identifies the boss fight prompt.
+const bossToastKey = "boss"
+
+// viewBoss renders the boss encounter: the boss's HP, shield, timer and
+// resistances, and the spells to fight it with.
+func (m Model) viewBoss() string {
+	gs := m.gameState
+	if gs == nil || gs.Boss == nil {
+		return "No boss fight"
+	}
+	fight := gs.Boss
+	boss := game.GetBoss(fight.BossID)
+	if boss == nil {
+		return "Unknown boss: " + fight.BossID
+	}
+
+	sym := m.styles.Symbols()
+	var lines []string
+	header := m.styles.Header.Width(70).Render(
+		m.styles.Title.Render(fmt.Sprintf("%s BOSS - FLOOR %d", sym.Boss, fight.Floor)),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+	lines = append(lines, m.styles.Subtitle.Render(boss.Name))
+	lines = append(lines, m.styles.Dim.Render(boss.Description))
+	lines = append(lines, "")
+
+	// HP, shield and timer
+	lines = append(lines, fmt.Sprintf("HP     [%s] %s / %s",
+		m.styles.Palette().ProgressBar(40, fight.HPProgress()),
+		utils.FormatNumber(fight.HP), utils.FormatNumber(fight.MaxHP)))
+	if fight.MaxShield > 0 {
+		element := string(fight.ShieldElement)
+		shieldStr := fmt.Sprintf("Ward   [%s] %s / %s  %s %s only",
+			m.styles.Palette().ProgressBarColored(40, fight.ShieldProgress(), m.styles.Palette().GetElementColor(element)),
+			utils.FormatNumber(fight.Shield), utils.FormatNumber(fight.MaxShield),
+			m.styles.GetElementIcon(element), m.styles.GetElementLabel(element))
+		if !fight.Shielded() {
+			shieldStr = m.styles.Dim.Render("Ward   broken")
+		}
+		lines = append(lines, shieldStr)
+	}
+
+	remaining := time.Duration(fight.RemainingMs()) * time.Millisecond
+	timer := fmt.Sprintf("Time left: %dm%02ds (failing drains the sigil)", int(remaining.Minutes()), int(remaining.Seconds())%60)
+	if remaining < 30*time.Second {
+		lines = append(lines, m.styles.Warning.Render(timer))
+	} else {
+		lines = append(lines, m.styles.Text.Render(timer))
+	}
+	switch {
+	case fight.Enraged:
+		lines = append(lines, m.styles.Error.Render(fmt.Sprintf("ENRAGED: heals %.1f%% of its HP every second", boss.EnrageRegenPerSec*100)))
+	case boss.EnrageAfterMs > 0:
+		enrageIn := (boss.EnrageAfterMs - fight.ElapsedMs) / 1000
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("Enrages in %ds", enrageIn)))
+	}
+	lines = append(lines, "")
+
+	// Spells, with how much of their damage reaches the boss
+	lines = append(lines, m.styles.Subtitle.Render("Spells"))
+	for i, spell := range gs.Spells {
+		prefix := "  "
+		if i == m.selectedIndex {
+			prefix = "> "
+		}
+
+		status := m.styles.Success.Render("Ready")
+		if _, needs := spell.NeedsSpecialization(); needs {
+			status = m.styles.Highlight.Render("Spec ready")
+		} else if !spell.IsReady() {
+			status = m.styles.Warning.Render(utils.FormatCooldown(spell.CooldownRemainingMs))
+		}
+
+		effect := ""
+		if m.engine != nil {
+			multiplier := m.engine.BossDamageMultiplier(gs, spell.Element)
+			effect = fmt.Sprintf("x%.2f", multiplier)
+			switch {
+			case fight.Shielded() && spell.Element == fight.ShieldElement:
+				effect = "breaks ward"
+			case fight.Shielded():
+				effect = "blocked by ward"
+			case boss.Resistance(spell.Element) < 0:
+				effect += " weak"
+			case boss.Resistance(spell.Element) > 0:
+				effect += " resisted"
+			}
+		}
+
+		line := fmt.Sprintf("%s%s %s (Lv%d) - %s  %s", prefix, m.styles.GetElementIcon(string(spell.Element)), spell.Name, spell.Level, status, effect)
+		if i == m.selectedIndex {
+			lines = append(lines, m.styles.Selected.Render(line))
+		} else {
+			lines = append(lines, m.styles.Text.Render(line))
+		}
+	}
+
+	if len(gs.PrestigeData.Artifacts) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Dim.Render("Artifacts: "+strings.Join(artifactNames(gs), ", ")))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewBoss)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// handleBossKeys handles keys in the boss view.
+func (m Model) handleBossKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.gameState == nil || m.gameState.Boss == nil {
+		m.Navigate(ViewTower)
+		return m, nil
+	}
+
+	spells := m.gameState.Spells
+	switch m.keys.Action(ViewBoss, msg.String()) {
+	case ActionUp:
+		if m.selectedIndex > 0 {
+			m.selectedIndex--
+		}
+	case ActionDown:
+		if m.selectedIndex < len(spells)-1 {
+			m.selectedIndex++
+		}
+	case ActionCast:
+		if m.engine == nil || m.selectedIndex >= len(spells) {
+			break
+		}
+		spell := spells[m.selectedIndex]
+		if err := m.engine.CastSpell(m.gameState, spell, true); err == engine.ErrNeedsSpecialization {
+			m.openSpecialize(spell)
+		} else if err != nil {
+			m.Notify(err.Error(), components.NotifyWarning)
+		}
+	case ActionSpecialize:
+		if m.selectedIndex < len(spells) {
+			m.openSpecialize(spells[m.selectedIndex])
+		}
+	case ActionBack:
+		m.Navigate(ViewTower)
+	}
+	return m, nil
+}
+
+// openSpecialize opens the specialization menu for spell if it's waiting
+// on a choice.
+func (m *Model) openSpecialize(spell *models.Spell) {
+	tier, needs := spell.NeedsSpecialization()
+	if !needs {
+		if spell.Level >= 5 {
+			m.ShowNotification("Already specialized")
+		} else {
+			m.ShowNotification("Reach level 5 to specialize")
+		}
+		return
+	}
+	m.specSpellID = spell.ID
+	m.specTier = tier
+	m.specChoiceIdx = 0
+	m.Navigate(ViewSpecialize)
+}
+
+// renderBossStatus describes the floor's boss in the tower view: the fight
+// in progress, or the boss waiting for a charged sigil.
+func (m Model) renderBossStatus() string {
+	gs := m.gameState
+	if fight := gs.Boss; fight != nil {
+		return m.styles.Warning.Render(fmt.Sprintf("%s %s: %.0f%% HP, %ds left  %s", m.styles.Symbols().Boss, bossName(fight.BossID),
+			fight.HPProgress()*100, fight.RemainingMs()/1000, m.keys.Label(ViewTower, ActionOpenBoss)))
+	}
+	if gs.Tower.BossDefeated {
+		return m.styles.Success.Render(m.styles.Symbols().Boss + " Boss defeated")
+	}
+	if boss := game.BossForFloor(gs.Tower.CurrentFloor); boss != nil {
+		return m.styles.Dim.Render(fmt.Sprintf("%s %s awaits a charged sigil: %s", m.styles.Symbols().Boss, boss.Name, boss.Description))
+	}
+	return ""
+}
+
+// bossName returns a boss's display name, falling back to its ID.
+func bossName(bossID string) string {
+	if boss := game.GetBoss(bossID); boss != nil {
+		return boss.Name
+	}
+	return bossID
+}
+
+// artifactNames returns the names of the artifacts gs has collected.
+func artifactNames(gs *models.GameState) []string {
+	var names []string
+	for _, artifact := range game.GetArtifacts(gs.PrestigeData.Artifacts) {
+		names = append(names, artifact.Name+" ("+artifact.Description+")")
+	}
+	return names
+}
+
+// announceBoss opens the boss view when a fight starts (unless the player
+// is busy elsewhere) and reports how the fight ended.
+func (m *Model) announceBoss() {
+	gs := m.gameState
+	if fight := gs.Boss; fight != nil {
+		if m.bossFloor != 0 {
+			return
+		}
+		m.bossFloor = fight.Floor
+		m.bossWins = gs.PrestigeData.BossesDefeated
+		m.bossArtifacts = len(gs.PrestigeData.Artifacts)
+		m.PushNotification(components.NewNotification(
+			fmt.Sprintf("%s blocks floor %d: beat it in %ds!", bossName(fight.BossID), fight.Floor, fight.TimeLimitMs/1000), components.NotifyWarning).
+			WithPriority(components.PriorityHigh).
+			WithKey(bossToastKey))
+		if !m.confirming && m.currentView != ViewFloorEvent && m.currentView != ViewSpecialize {
+			m.Navigate(ViewBoss)
+		}
+		return
+	}
+
+	if m.bossFloor == 0 {
+		return
+	}
+	floor := m.bossFloor
+	m.bossFloor = 0
+	m.DismissNotification(bossToastKey)
+	if m.currentView == ViewBoss {
+		m.Navigate(ViewTower)
+	}
+
+	if gs.PrestigeData.BossesDefeated <= m.bossWins {
+		m.Notify(fmt.Sprintf("The boss of floor %d held out: the sigil drained", floor), components.NotifyWarning)
+		return
+	}
+	text := fmt.Sprintf("Boss of floor %d defeated! Spells gained XP", floor)
+	if len(gs.PrestigeData.Artifacts) > m.bossArtifacts {
+		id := gs.PrestigeData.Artifacts[len(gs.PrestigeData.Artifacts)-1]
+		if artifact := game.GetArtifact(id); artifact != nil {
+			text += fmt.Sprintf(". Artifact: %s (%s)", artifact.Name, artifact.Description)
+		}
+	}
+	m.PushNotification(components.NewNotification(text, components.NotifySuccess).WithPriority(components.PriorityHigh))
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/ui/components"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// renderChallengeStatus describes the challenge run shown in the tower
+// view: goal, play time and modifiers.
+func (m Model) renderChallengeStatus() []string {
+	run := m.gameState.Challenge
+	elapsed := utils.FormatDuration(time.Duration(run.ElapsedMs) * time.Millisecond)
+	status := fmt.Sprintf("%s Challenge %s: reach floor %d", m.styles.Symbols().Star, run.Challenge.ID, run.Challenge.TargetFloor)
+
+	var lines []string
+	if run.Complete() {
+		lines = append(lines, m.styles.Success.Render(fmt.Sprintf("%s  %s Complete in %s", status, m.styles.Symbols().Check, elapsed)))
+	} else {
+		lines = append(lines, m.styles.Highlight.Render(fmt.Sprintf("%s  (%s)", status, elapsed)))
+	}
+
+	var rules []string
+	for _, mod := range game.GetChallengeModifiers(run.Challenge.Modifiers) {
+		rules = append(rules, mod.Name+": "+mod.Description)
+	}
+	lines = append(lines, m.styles.Dim.Render("  "+strings.Join(rules, " · ")))
+	return lines
+}
+
+// announceChallenge notifies the player once when the challenge run
+// reaches its target floor.
+func (m *Model) announceChallenge() {
+	run := m.gameState.Challenge
+	if run == nil || !run.Complete() || m.challengeAnnounced {
+		return
+	}
+	m.challengeAnnounced = true
+
+	text := fmt.Sprintf("Challenge %s complete: floor %d in %s", run.Challenge.ID, run.Challenge.TargetFloor,
+		utils.FormatDuration(time.Duration(run.ElapsedMs)*time.Millisecond))
+	if m.player != nil {
+		if best := m.player.ChallengeResult(run.Challenge.ID); best != nil && best.CompletedAt.Equal(*run.CompletedAt) {
+			text += " (personal best!)"
+		}
+	}
+	m.PushNotification(components.NewNotification(text, components.NotifySuccess).WithPriority(components.PriorityHigh))
+}
@@ -0,0 +1,132 @@
+package components
+
+import (
+	"math"
+	"strings"
+)
+
+// valueRange returns the min and max of values, widened when flat so that
+// a constant series draws as a line along the bottom.
+func valueRange(values []float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// resample squeezes or stretches values to exactly n points by picking the
+// nearest source value for each point.
+func resample(values []float64, n int) []float64 {
+	if len(values) == 0 || n <= 0 {
+		return nil
+	}
+	if len(values) <= n {
+		return values
+	}
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = values[i*len(values)/n]
+	}
+	out[n-1] = values[len(values)-1]
+	return out
+}
+
+// Sparkline renders values as a single line of block characters, at most
+// width characters wide (newest values are kept when there are too many).
+func (p *Palette) Sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := valueRange(values)
+	top := len(p.SparkLevels) - 1
+	var b strings.Builder
+	for _, v := range values {
+		level := int(math.Round((v - lo) / (hi - lo) * float64(top)))
+		b.WriteRune(p.SparkLevels[level])
+	}
+	return b.String()
+}
+
+// LineChart renders values as a line chart width characters wide and height
+// rows tall, returned top row first. Values are scaled between their min
+// and max and resampled to fit the width.
+func (p *Palette) LineChart(values []float64, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	// Each braille cell holds a 2x4 grid of dots
+	dotsX, dotsY := width, height
+	if p.UseBraille {
+		dotsX, dotsY = width*2, height*4
+	}
+
+	grid := make([][]bool, dotsY)
+	for y := range grid {
+		grid[y] = make([]bool, dotsX)
+	}
+
+	points := resample(values, dotsX)
+	if len(points) > 0 {
+		lo, hi := valueRange(points)
+		prevY := -1
+		for x, v := range points {
+			y := dotsY - 1 - int(math.Round((v-lo)/(hi-lo)*float64(dotsY-1)))
+			grid[y][x] = true
+			// Join steep segments so the line stays continuous
+			if prevY >= 0 {
+				for fill := min(prevY, y) + 1; fill < max(prevY, y); fill++ {
+					grid[fill][x] = true
+				}
+			}
+			prevY = y
+		}
+	}
+
+	rows := make([]string, height)
+	for row := range rows {
+		var b strings.Builder
+		for col := 0; col < width; col++ {
+			if p.UseBraille {
+				b.WriteRune(brailleCell(grid, col*2, row*4))
+			} else if grid[row][col] {
+				b.WriteRune('*')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		rows[row] = b.String()
+	}
+	return rows
+}
+
+// brailleDots maps a dot's (x, y) position within a cell to its bit in the
+// Unicode braille block.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleCell returns the braille character for the 2x4 dots at (x, y).
+func brailleCell(grid [][]bool, x, y int) rune {
+	cell := rune(0x2800)
+	for dy := 0; dy < 4; dy++ {
+		for dx := 0; dx < 2; dx++ {
+			if grid[y+dy][x+dx] {
+				cell |= brailleDots[dy][dx]
+			}
+		}
+	}
+	return cell
+}
@@ -1,14 +1,7 @@
 package components
 
-import (
-	"github.com/charmbracelet/lipgloss"
-)
-
-// Loader styles
-var (
-	spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	loaderStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED"))
-)
+// spinnerFrames are the loader's animation frames.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
 // Loader represents a loading spinner.
 type Loader struct {
@@ -29,14 +22,14 @@ func (l *Loader) Tick() {
 	l.frame = (l.frame + 1) % len(spinnerFrames)
 }
 
-// Render renders the loader.
-func (l *Loader) Render() string {
-	spinner := loaderStyle.Render(spinnerFrames[l.frame])
+// Render renders the loader with p.
+func (l *Loader) Render(p *Palette) string {
+	spinner := p.loaderStyle.Render(spinnerFrames[l.frame])
 	return spinner + " " + l.text
 }
 
 // RenderLoader renders a static loading message.
-func RenderLoader(text string) string {
-	spinner := loaderStyle.Render(spinnerFrames[0])
+func (p *Palette) RenderLoader(text string) string {
+	spinner := p.loaderStyle.Render(spinnerFrames[0])
 	return spinner + " " + text
 }
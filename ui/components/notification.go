@@ -1,6 +1,8 @@
 package components
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -16,32 +18,41 @@ const (
 	NotifyError   NotificationType = "error"
 )
 
+// NotificationPriority orders toasts; higher priorities are shown first and
+// are never crowded out by lower ones.
+type NotificationPriority int
+
+const (
+	PriorityLow NotificationPriority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// NotificationDurations is how long each type of toast stays on screen.
+var NotificationDurations = map[NotificationType]time.Duration{
+	NotifyInfo:    3 * time.Second,
+	NotifySuccess: 3 * time.Second,
+	NotifyWarning: 5 * time.Second,
+	NotifyError:   8 * time.Second,
+}
+
 // Notification represents a toast notification.
 type Notification struct {
 	Text      string
 	Type      NotificationType
 	CreatedAt time.Time
-	Duration  time.Duration
-}
-
-// Notification styles
-var (
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#3B82F6")).
-			Bold(true)
-
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#22C55E")).
-			Bold(true)
+	Duration  time.Duration // Zero keeps the toast until it is dismissed
+	Priority  NotificationPriority
 
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EAB308")).
-			Bold(true)
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
-			Bold(true)
-)
+	// Key identifies toasts that should be merged rather than stacked; it
+	// defaults to Text, so identical messages are deduplicated.
+	Key string
+	// Count is how many notifications were merged into this one.
+	Count int
+	// Format, if set, rebuilds Text from Count when notifications are merged
+	// (e.g. "Auto-cast skipped N times").
+	Format func(count int) string
+}
 
 // NewNotification creates a new notification.
 func NewNotification(text string, notifyType NotificationType) *Notification {
@@ -49,40 +60,180 @@ func NewNotification(text string, notifyType NotificationType) *Notification {
 		Text:      text,
 		Type:      notifyType,
 		CreatedAt: time.Now(),
-		Duration:  3 * time.Second,
+		Duration:  NotificationDurations[notifyType],
+		Priority:  PriorityNormal,
+		Count:     1,
 	}
 }
 
+// WithPriority sets the notification's priority (builder pattern).
+func (n *Notification) WithPriority(priority NotificationPriority) *Notification {
+	n.Priority = priority
+	return n
+}
+
+// WithKey sets the key used to merge repeats of this notification.
+func (n *Notification) WithKey(key string) *Notification {
+	n.Key = key
+	return n
+}
+
+// WithCount sets the count and a formatter used to rebuild the text as
+// merged notifications add up.
+func (n *Notification) WithCount(count int, format func(count int) string) *Notification {
+	n.Count = count
+	n.Format = format
+	n.Text = format(count)
+	return n
+}
+
+// Sticky keeps the notification until it is dismissed.
+func (n *Notification) Sticky() *Notification {
+	n.Duration = 0
+	return n
+}
+
 // IsExpired returns true if the notification has expired.
 func (n *Notification) IsExpired() bool {
-	return time.Since(n.CreatedAt) > n.Duration
+	return n.Duration > 0 && time.Since(n.CreatedAt) > n.Duration
+}
+
+// mergeKey returns the key repeats of this notification are merged under.
+func (n *Notification) mergeKey() string {
+	if n.Key != "" {
+		return n.Key
+	}
+	return n.Text
 }
 
-// Render renders the notification.
-func (n *Notification) Render() string {
+// Render renders the notification with p.
+func (n *Notification) Render(p *Palette) string {
 	var style lipgloss.Style
 	var icon string
 
 	switch n.Type {
 	case NotifySuccess:
-		style = successStyle
+		style = p.successStyle
 		icon = "✓"
 	case NotifyWarning:
-		style = warningStyle
+		style = p.warningStyle
 		icon = "⚠"
 	case NotifyError:
-		style = errorStyle
+		style = p.errorStyle
 		icon = "✗"
 	default:
-		style = infoStyle
+		style = p.infoStyle
 		icon = "ℹ"
 	}
 
-	return style.Render(icon + " " + n.Text)
+	text := icon + " " + n.Text
+	if n.Count > 1 && n.Format == nil {
+		text += fmt.Sprintf(" ×%d", n.Count)
+	}
+	return style.Render(text)
 }
 
 // RenderNotification renders a notification string with type.
-func RenderNotification(text string, notifyType NotificationType) string {
+func (p *Palette) RenderNotification(text string, notifyType NotificationType) string {
 	n := NewNotification(text, notifyType)
-	return n.Render()
+	return n.Render(p)
+}
+
+// NotificationQueue holds the toasts currently on screen. Repeats are merged,
+// expired toasts are pruned, and only the highest-priority ones are shown.
+type NotificationQueue struct {
+	items      []*Notification
+	maxVisible int
+}
+
+// NewNotificationQueue creates a queue that shows up to maxVisible toasts.
+func NewNotificationQueue(maxVisible int) *NotificationQueue {
+	return &NotificationQueue{maxVisible: maxVisible}
+}
+
+// Push adds a notification, merging it into a live one with the same key.
+func (q *NotificationQueue) Push(n *Notification) {
+	key := n.mergeKey()
+	for _, existing := range q.items {
+		if existing.mergeKey() != key || existing.IsExpired() {
+			continue
+		}
+		existing.Count += n.Count
+		existing.Text = n.Text
+		if existing.Format != nil {
+			existing.Text = existing.Format(existing.Count)
+		}
+		existing.Type = n.Type
+		existing.CreatedAt = n.CreatedAt
+		existing.Duration = n.Duration
+		if n.Priority > existing.Priority {
+			existing.Priority = n.Priority
+		}
+		return
+	}
+	q.items = append(q.items, n)
+}
+
+// Dismiss removes the notification with the given key.
+func (q *NotificationQueue) Dismiss(key string) {
+	kept := q.items[:0]
+	for _, n := range q.items {
+		if n.mergeKey() != key {
+			kept = append(kept, n)
+		}
+	}
+	q.items = kept
+}
+
+// Prune drops expired notifications.
+func (q *NotificationQueue) Prune() {
+	kept := q.items[:0]
+	for _, n := range q.items {
+		if !n.IsExpired() {
+			kept = append(kept, n)
+		}
+	}
+	q.items = kept
+}
+
+// Len returns the number of live notifications, shown or not.
+func (q *NotificationQueue) Len() int {
+	live := 0
+	for _, n := range q.items {
+		if !n.IsExpired() {
+			live++
+		}
+	}
+	return live
+}
+
+// Latest returns the most recently pushed or refreshed notification, or nil.
+func (q *NotificationQueue) Latest() *Notification {
+	var latest *Notification
+	for _, n := range q.items {
+		if latest == nil || !n.CreatedAt.Before(latest.CreatedAt) {
+			latest = n
+		}
+	}
+	return latest
+}
+
+// Visible returns the toasts to display: highest priority first, then newest.
+func (q *NotificationQueue) Visible() []*Notification {
+	visible := make([]*Notification, 0, len(q.items))
+	for _, n := range q.items {
+		if !n.IsExpired() {
+			visible = append(visible, n)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		if visible[i].Priority != visible[j].Priority {
+			return visible[i].Priority > visible[j].Priority
+		}
+		return visible[i].CreatedAt.After(visible[j].CreatedAt)
+	})
+	if q.maxVisible > 0 && len(visible) > q.maxVisible {
+		visible = visible[:q.maxVisible]
+	}
+	return visible
 }
@@ -0,0 +1,66 @@
+package components
+
+import "github.com/charmbracelet/lipgloss"
+
+// Palette is the set of colors and glyphs components render with, and the
+// renderer they are drawn for. The ui package builds one for each TUI from
+// its theme and symbol set (see NewPalette).
+type Palette struct {
+	Primary  lipgloss.Color
+	Text     lipgloss.Color
+	TextDim  lipgloss.Color
+	Selected lipgloss.Color // Background of the selected list item
+	Empty    lipgloss.Color // Unfilled part of progress bars
+	Success  lipgloss.Color
+	Warning  lipgloss.Color
+	Error    lipgloss.Color
+	Info     lipgloss.Color
+	Elements map[string]lipgloss.Color
+
+	// Icons maps element names to icons, plus "default" for the rest
+	Icons map[string]string
+	// SparkLevels are the characters used for sparkline heights, lowest
+	// first
+	SparkLevels []rune
+	// UseBraille selects braille dots for line charts; when false, charts
+	// are drawn with '*' at one point per cell
+	UseBraille bool
+
+	renderer *lipgloss.Renderer
+
+	loaderStyle lipgloss.Style
+
+	infoStyle    lipgloss.Style
+	successStyle lipgloss.Style
+	warningStyle lipgloss.Style
+	errorStyle   lipgloss.Style
+
+	selectedStyle lipgloss.Style
+	normalStyle   lipgloss.Style
+	dimStyle      lipgloss.Style
+	readyStyle    lipgloss.Style
+	cooldownStyle lipgloss.Style
+}
+
+// NewPalette returns p with its styles built for r, lipgloss's default
+// renderer if nil.
+func NewPalette(r *lipgloss.Renderer, p Palette) *Palette {
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+	p.renderer = r
+
+	p.loaderStyle = r.NewStyle().Foreground(p.Primary)
+
+	p.infoStyle = r.NewStyle().Foreground(p.Info).Bold(true)
+	p.successStyle = r.NewStyle().Foreground(p.Success).Bold(true)
+	p.warningStyle = r.NewStyle().Foreground(p.Warning).Bold(true)
+	p.errorStyle = r.NewStyle().Foreground(p.Error).Bold(true)
+
+	p.selectedStyle = r.NewStyle().Bold(true).Foreground(p.Text).Background(p.Selected)
+	p.normalStyle = r.NewStyle().Foreground(p.Text)
+	p.dimStyle = r.NewStyle().Foreground(p.TextDim)
+	p.readyStyle = r.NewStyle().Foreground(p.Success).Bold(true)
+	p.cooldownStyle = r.NewStyle().Foreground(p.Warning)
+	return &p
+}
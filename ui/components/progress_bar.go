@@ -6,14 +6,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Colors for progress bar
-var (
-	filledColor = lipgloss.Color("#22C55E")
-	emptyColor  = lipgloss.Color("#374151")
-)
-
 // ProgressBar creates a text-based progress bar.
-func ProgressBar(width int, progress float64) string {
+func (p *Palette) ProgressBar(width int, progress float64) string {
 	if progress < 0 {
 		progress = 0
 	}
@@ -24,8 +18,8 @@ func ProgressBar(width int, progress float64) string {
 	filled := int(progress * float64(width))
 	empty := width - filled
 
-	filledStyle := lipgloss.NewStyle().Foreground(filledColor)
-	emptyStyle := lipgloss.NewStyle().Foreground(emptyColor)
+	filledStyle := p.renderer.NewStyle().Foreground(p.Success)
+	emptyStyle := p.renderer.NewStyle().Foreground(p.Empty)
 
 	bar := filledStyle.Render(strings.Repeat("█", filled)) +
 		emptyStyle.Render(strings.Repeat("░", empty))
@@ -34,13 +28,13 @@ func ProgressBar(width int, progress float64) string {
 }
 
 // ProgressBarWithLabel creates a progress bar with a label.
-func ProgressBarWithLabel(width int, progress float64, label string) string {
-	bar := ProgressBar(width, progress)
+func (p *Palette) ProgressBarWithLabel(width int, progress float64, label string) string {
+	bar := p.ProgressBar(width, progress)
 	return lipgloss.JoinHorizontal(lipgloss.Center, "[", bar, "] ", label)
 }
 
 // ProgressBarColored creates a colored progress bar.
-func ProgressBarColored(width int, progress float64, color lipgloss.Color) string {
+func (p *Palette) ProgressBarColored(width int, progress float64, color lipgloss.Color) string {
 	if progress < 0 {
 		progress = 0
 	}
@@ -51,8 +45,8 @@ func ProgressBarColored(width int, progress float64, color lipgloss.Color) strin
 	filled := int(progress * float64(width))
 	empty := width - filled
 
-	filledStyle := lipgloss.NewStyle().Foreground(color)
-	emptyStyle := lipgloss.NewStyle().Foreground(emptyColor)
+	filledStyle := p.renderer.NewStyle().Foreground(color)
+	emptyStyle := p.renderer.NewStyle().Foreground(p.Empty)
 
 	bar := filledStyle.Render(strings.Repeat("█", filled)) +
 		emptyStyle.Render(strings.Repeat("░", empty))
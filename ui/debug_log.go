@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// Debug log view sizing
+const (
+	debugLogTail     = 500 // Lines read from the end of the log file
+	debugLogPageSize = 20
+	debugLogRefresh  = time.Second
+)
+
+// debugLogLevels lists the minimum levels the debug log view can filter by.
+var debugLogLevels = []utils.LogLevel{utils.LogDebug, utils.LogInfo, utils.LogWarn, utils.LogError}
+
+// refreshLog re-reads the tail of the log file.
+func (m *Model) refreshLog() {
+	m.logReadAt = time.Now()
+	if m.logPath == "" {
+		return
+	}
+	m.logLines, m.logReadErr = utils.TailFile(m.logPath, debugLogTail)
+}
+
+// visibleLogLines returns the log lines at or above the selected level,
+// newest first.
+func (m Model) visibleLogLines() []string {
+	var lines []string
+	for i := len(m.logLines) - 1; i >= 0; i-- {
+		if logLineLevel(m.logLines[i]) >= m.logLevel {
+			lines = append(lines, m.logLines[i])
+		}
+	}
+	return lines
+}
+
+// logLineLevel reads the level=... field of a log file line.
+func logLineLevel(line string) utils.LogLevel {
+	for _, field := range strings.Fields(line) {
+		if level, ok := strings.CutPrefix(field, "level="); ok {
+			return utils.ParseLogLevel(level)
+		}
+	}
+	return utils.LogInfo
+}
+
+// handleDebugLogKeys handles keys in the debug log view.
+func (m Model) handleDebugLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	levels := len(debugLogLevels)
+	switch m.keys.Action(ViewDebugLog, msg.String()) {
+	case ActionLeft:
+		m.logLevel = debugLogLevels[(int(m.logLevel)+levels-1)%levels]
+		m.logScroll = 0
+	case ActionRight:
+		m.logLevel = debugLogLevels[(int(m.logLevel)+1)%levels]
+		m.logScroll = 0
+	case ActionUp:
+		if m.logScroll > 0 {
+			m.logScroll--
+		}
+	case ActionDown:
+		m.logScroll++
+	case ActionPageUp:
+		m.logScroll = max(m.logScroll-debugLogPageSize, 0)
+	case ActionPageDown:
+		m.logScroll += debugLogPageSize
+	case ActionTop:
+		m.logScroll = 0
+	case ActionBack:
+		m.GoBack()
+	}
+	m.logScroll = min(m.logScroll, max(len(m.visibleLogLines())-1, 0))
+	return m, nil
+}
+
+// viewDebugLog renders the end of the log file, newest first.
+func (m Model) viewDebugLog() string {
+	var lines []string
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(m.styles.Symbols().Stats + " DEBUG LOG"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	var tabs []string
+	for _, level := range debugLogLevels {
+		label := fmt.Sprintf(" %s+ ", level)
+		if level == m.logLevel {
+			tabs = append(tabs, m.styles.Selected.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	lines = append(lines, "")
+
+	width := 100
+	if m.width > 0 {
+		width = max(m.width-8, 20)
+	}
+	visible := m.visibleLogLines()
+	switch {
+	case m.logPath == "":
+		lines = append(lines, m.styles.Dim.Render("  File logging is off"))
+	case m.logReadErr != nil:
+		lines = append(lines, m.styles.Error.Render("  Can't read "+m.logPath+": "+m.logReadErr.Error()))
+	case len(visible) == 0:
+		lines = append(lines, m.styles.Dim.Render("  No log entries yet"))
+	default:
+		end := min(m.logScroll+debugLogPageSize, len(visible))
+		for _, line := range visible[m.logScroll:end] {
+			lines = append(lines, "  "+m.logLineStyle(line).Render(truncateLine(line, width)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  %d-%d of %d lines (newest first) from %s",
+			m.logScroll+1, end, len(visible), m.logPath)))
+	}
+
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewDebugLog)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// logLineStyle colors a log line by its level.
+func (m Model) logLineStyle(line string) lipgloss.Style {
+	switch logLineLevel(line) {
+	case utils.LogDebug:
+		return m.styles.Dim
+	case utils.LogWarn:
+		return m.styles.Warning
+	case utils.LogError:
+		return m.styles.Error
+	default:
+		return m.styles.Text
+	}
+}
+
+// truncateLine shortens line to width characters, marking the cut.
+func truncateLine(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
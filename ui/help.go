@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/game"
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/ui/components"
+)
+
+// viewGuide is the help overlay text for a view.
+type viewGuide struct {
+	Title string
+	Text  string
+}
+
+// viewGuides explains each view's mechanics in the help overlay.
+var viewGuides = map[ViewType]viewGuide{
+	ViewTower: {"Tower", fmt.Sprintf(
+		"Mana fills passively based on your floor. You climb only when BOTH the mana bar and the ascension sigil are full; "+
+			"casting spells is what charges the sigil. Every %d floors a floor event offers a temporary bonus.",
+		game.FloorEventIntervalFloors)},
+	ViewSpells: {"Spells", fmt.Sprintf(
+		"Every cast costs mana (manual casts +%.0f%%) and charges the sigil. Spells in auto-cast slots cast on their own when "+
+			"their condition holds; slot order is priority. Upgrades lower cooldown and cost and raise damage. "+
+			"Levels 5 and 10 unlock a specialization. Three casts of one element in a row trigger an element synergy.",
+		game.ManualCastPenalty*100)},
+	ViewRituals: {"Rituals",
+		"Combine 3 spells into a ritual for +15% mana generation per active ritual. Element mixes add combo effects, " +
+			"and some combinations form named signature rituals. Prestige raises how many rituals can be active."},
+	ViewRotation: {"Rotation",
+		"When enabled, the rotation replaces auto-cast slots. Each tick the highest-priority spell whose condition holds " +
+			"is cast. Conditions range from simple mana thresholds to custom expressions (e.g. mana_pct > 0.6 && !synergy_active). " +
+			"Conditions see mana above the reserve as available mana, weaving favours short cooldowns, and the optimizer searches for a better order."},
+	ViewStats: {"Stats",
+		"A ledger of the current era, every previous era and lifetime totals. It is saved with your game and carried across prestige."},
+	ViewPrestige: {"Prestige",
+		"From floor 100 you can ascend: the tower resets, but you keep a permanent mana multiplier, more ritual and " +
+			"auto-cast slots, and prestige-only spells."},
+	ViewMenu: {"Menu", "Save your game or quit. The game also saves automatically every 30 seconds."},
+	ViewSpecialize: {"Specialization",
+		"Pick one path for this spell: crit chance, mana efficiency, burst damage or rapid cast. The choice is permanent for this era."},
+	ViewFloorEvent: {"Floor Event", fmt.Sprintf(
+		"Pick one bonus for the next %d floors. If you don't choose within %d minutes the event vanishes with no bonus.",
+		game.FloorEventBuffDurationFloors, game.FloorEventTimeoutMs/60000)},
+	ViewBoss: {"Boss Fight", fmt.Sprintf(
+		"Every %d floors a boss guards the climb. Once the sigil is charged it attacks, and spell damage goes to it "+
+			"instead of the sigil. Beat it within %d minutes or the sigil drains. Resistances and weaknesses favour some "+
+			"elements, a ward must be broken by its element first, and some bosses enrage and heal. Victory shares spell "+
+			"XP by damage dealt and the first win drops a permanent artifact.",
+		game.BossFloorInterval, game.BossTimeLimitMs/60000)},
+	ViewAutomation: {"Automation",
+		"Rules that play for you (answer floor events, prestige when climbing stalls, buy upgrades) and loadout presets " +
+			"that save auto-cast slots and rotation for reuse."},
+	ViewAchievements: {"Achievements", "Milestones that grant permanent mana generation bonuses. They follow your profile across saves."},
+	ViewLeaderboard: {"Leaderboard",
+		"Lifetime records of every player sharing this storage: all profiles in the data directory, or everyone on a shared " +
+			"MongoDB. Your row uses your live records; others update when they save."},
+	ViewGraphs:   {"Graphs", "Mana/sec, sigil DPS and floor sampled every few seconds, to check whether a change actually helped."},
+	ViewEventLog: {"Event Log", "Recent engine events with timestamps. Filter by type or pin them beside the tower."},
+	ViewDebugLog: {"Debug Log",
+		"The end of the log file (~/.manatty/logs/manatty.log), newest first and refreshed every second. Filter by minimum " +
+			"level. Set DEBUG=true or debug = true in config.toml to trace every engine event."},
+	ViewSettings: {"Settings",
+		"Changes apply immediately and are saved to ~/.manatty/config.toml (the theme is also saved with your profile). " +
+			"Lower notification verbosity to hide routine toasts. Themes include high-contrast, colorblind-safe and monochrome palettes; elements always " +
+			"show an icon and name too, so they never depend on color alone."},
+}
+
+// tutorialHint returns the instruction for a tutorial step.
+func (m Model) tutorialHint(step models.TutorialStep) (title, hint string) {
+	switch step {
+	case models.TutorialCast:
+		return "Cast a spell", fmt.Sprintf("Open Spells %s, select a spell and press %s. Casting charges the sigil, which you need to climb.",
+			m.keys.Label(ViewTower, ActionOpenSpells), m.keys.Label(ViewSpells, ActionCast))
+	case models.TutorialAutoCast:
+		return "Fill an auto-cast slot", fmt.Sprintf("In Spells, press %s to put a spell in an auto-cast slot so it casts by itself.",
+			m.keys.Label(ViewSpells, ActionSlot))
+	case models.TutorialUpgrade:
+		return "Upgrade a spell", fmt.Sprintf("In Spells, press %s to spend mana on an upgrade: faster, cheaper, stronger casts.",
+			m.keys.Label(ViewSpells, ActionUpgrade))
+	case models.TutorialRitual:
+		return "Form a ritual", fmt.Sprintf("Open Rituals %s and combine 3 spells for a permanent mana generation bonus.",
+			m.keys.Label(ViewTower, ActionOpenRituals))
+	case models.TutorialFloorEvent:
+		return "Answer a floor event", fmt.Sprintf("Keep climbing: every %d floors a floor event appears. Pick a bonus before it expires.",
+			game.FloorEventIntervalFloors)
+	}
+	return "", ""
+}
+
+// viewTutorialHint renders the current tutorial step, or "" when the
+// tutorial is finished or skipped.
+func (m Model) viewTutorialHint() string {
+	if m.tutorial == nil || !m.tutorial.State().Active() {
+		return ""
+	}
+	state := m.tutorial.State()
+	title, hint := m.tutorialHint(state.Step)
+	text := lipgloss.JoinVertical(lipgloss.Left,
+		m.styles.Highlight.Render(fmt.Sprintf("Tutorial %d/%d: %s", state.StepNumber(), len(models.TutorialSteps), title)),
+		m.styles.Text.Width(helpWidth-4).Render(hint),
+		m.styles.Dim.Render("Press "+m.keys.Label(keyScopeGlobal, ActionHelp)+" for help or to skip the tutorial"),
+	)
+	return m.styles.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.styles.ColorPrimary).
+		Padding(0, 1).
+		Render(text)
+}
+
+// helpScope returns the key scope whose bindings the help overlay lists.
+func (m Model) helpScope() ViewType {
+	if m.currentView == ViewRotation && m.addingRotationSpell {
+		return keyScopeRotationPicker
+	}
+	return m.currentView
+}
+
+// viewHelp renders the help overlay for the current view.
+func (m Model) viewHelp() string {
+	guide, ok := viewGuides[m.currentView]
+	if !ok {
+		guide = viewGuides[ViewTower]
+	}
+
+	var lines []string
+	lines = append(lines, m.styles.Title.Render("? HELP: "+strings.ToUpper(guide.Title)))
+	lines = append(lines, "")
+	lines = append(lines, m.styles.Text.Width(helpWidth).Render(guide.Text))
+	lines = append(lines, "")
+
+	lines = append(lines, m.styles.Subtitle.Render("Keys"))
+	lines = append(lines, m.renderHelpEntries(m.helpScope())...)
+	lines = append(lines, "")
+	lines = append(lines, m.styles.Subtitle.Render("Everywhere"))
+	lines = append(lines, m.renderHelpEntries(keyScopeGlobal)...)
+	lines = append(lines, "")
+
+	if m.tutorial != nil {
+		state := m.tutorial.State()
+		switch {
+		case state.Active():
+			title, _ := m.tutorialHint(state.Step)
+			lines = append(lines, m.styles.Text.Render(fmt.Sprintf("Tutorial: step %d/%d (%s)", state.StepNumber(), len(models.TutorialSteps), title)))
+		case state.Skipped:
+			lines = append(lines, m.styles.Dim.Render("Tutorial: skipped"))
+		default:
+			lines = append(lines, m.styles.Success.Render("Tutorial: complete"))
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(keyScopeHelp)+"  "+m.keys.Label(keyScopeGlobal, ActionHelp)+" Close"))
+	return m.styles.Container.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderHelpEntries lists a scope's bindings one per line.
+func (m Model) renderHelpEntries(scope ViewType) []string {
+	var lines []string
+	for _, entry := range m.keys.HelpEntries(scope) {
+		lines = append(lines, fmt.Sprintf("  %s %s", m.styles.Highlight.Render(fmt.Sprintf("%-14s", "["+entry.Keys+"]")), entry.Label))
+	}
+	return lines
+}
+
+// handleHelpKeys handles keys while the help overlay is open.
+func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.keys.Action(keyScopeHelp, msg.String()) {
+	case ActionBack:
+		m.showHelp = false
+	case ActionTutorial:
+		if m.tutorial == nil {
+			break
+		}
+		if m.tutorial.State().Active() {
+			m.tutorial.Skip()
+			m.ShowNotification("Tutorial skipped")
+		} else {
+			m.tutorial.Restart()
+			m.ShowNotification("Tutorial restarted")
+		}
+	}
+	return m, nil
+}
+
+// announceTutorialStep notifies the player of a completed tutorial step.
+func (m *Model) announceTutorialStep(step models.TutorialStep) {
+	title, _ := m.tutorialHint(step)
+	m.Notify("Tutorial: "+title+" ✓", components.NotifySuccess)
+	if step == models.TutorialSteps[len(models.TutorialSteps)-1] {
+		m.PushNotification(components.NewNotification(
+			"Tutorial complete! Press "+m.keys.Label(keyScopeGlobal, ActionHelp)+" any time for help.", components.NotifySuccess).
+			WithPriority(components.PriorityHigh))
+	}
+}
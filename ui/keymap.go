@@ -0,0 +1,608 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Action names a command that can be bound to keys. Action names are used
+// as-is in the key bindings file.
+type Action string
+
+// Shared actions (meaning depends on the view)
+const (
+	ActionNone     Action = ""
+	ActionUp       Action = "up"
+	ActionDown     Action = "down"
+	ActionLeft     Action = "left"
+	ActionRight    Action = "right"
+	ActionSelect   Action = "select"
+	ActionBack     Action = "back"
+	ActionToggle   Action = "toggle"
+	ActionDelete   Action = "delete"
+	ActionMoveUp   Action = "move_up"
+	ActionMoveDown Action = "move_down"
+	ActionPageUp   Action = "page_up"
+	ActionPageDown Action = "page_down"
+	ActionTop      Action = "top"
+)
+
+// Global actions
+const (
+	ActionQuit Action = "quit"
+	ActionSave Action = "save"
+	ActionHelp Action = "help"
+)
+
+// Tower actions
+const (
+	ActionOpenSpells       Action = "spells"
+	ActionOpenRituals      Action = "rituals"
+	ActionOpenRotation     Action = "rotation"
+	ActionOpenStats        Action = "stats"
+	ActionOpenPrestige     Action = "prestige"
+	ActionOpenMenu         Action = "menu"
+	ActionOpenAutomation   Action = "automation"
+	ActionOpenAchievements Action = "achievements"
+	ActionOpenGraphs       Action = "graphs"
+	ActionOpenEventLog     Action = "event_log"
+	ActionOpenSettings     Action = "settings"
+	ActionOpenLeaderboard  Action = "leaderboard"
+	ActionOpenBoss         Action = "boss"
+	ActionAutoCast         Action = "auto_cast"
+)
+
+// View-specific actions
+const (
+	ActionCast            Action = "cast"
+	ActionSlot            Action = "slot"
+	ActionUpgrade         Action = "upgrade"
+	ActionCondition       Action = "condition"
+	ActionSpecialize      Action = "specialize"
+	ActionClear           Action = "clear"
+	ActionReset           Action = "reset"
+	ActionPanel           Action = "panel"
+	ActionAscend          Action = "ascend"
+	ActionAdd             Action = "add"
+	ActionPriority        Action = "priority"
+	ActionEditExpression  Action = "edit_expression"
+	ActionEnableRotation  Action = "enable_rotation"
+	ActionConvert         Action = "convert"
+	ActionWeaving         Action = "weaving"
+	ActionIdle            Action = "idle"
+	ActionReserveDown     Action = "reserve_down"
+	ActionReserveUp       Action = "reserve_up"
+	ActionOptimize        Action = "optimize"
+	ActionOptimizeFloors  Action = "optimize_floors"
+	ActionUndo            Action = "undo"
+	ActionNewPreset       Action = "new_preset"
+	ActionOverwritePreset Action = "overwrite_preset"
+	ActionReapplyPreset   Action = "reapply_preset"
+	ActionConfirm         Action = "confirm"
+	ActionCancel          Action = "cancel"
+	ActionTutorial        Action = "tutorial"
+	ActionDebugLog        Action = "debug_log"
+	ActionRefresh         Action = "refresh"
+)
+
+// Key scopes that are not views. Global bindings apply in every view
+// (except confirmations, which capture all keys).
+const (
+	keyScopeGlobal         ViewType = "global"
+	keyScopeConfirm        ViewType = "confirm"
+	keyScopeRotationPicker ViewType = "rotation_picker"
+	keyScopeHelp           ViewType = "help"
+)
+
+// KeyScheme selects the navigation keys.
+type KeyScheme string
+
+const (
+	SchemeDefault KeyScheme = "default" // Arrow keys and vim keys
+	SchemeVim     KeyScheme = "vim"     // h/j/k/l only
+	SchemeArrows  KeyScheme = "arrows"  // Arrow keys only (leaves h/j/k/l free)
+)
+
+// navKeys are the direction keys of a scheme.
+type navKeys struct {
+	up, down, left, right []string
+}
+
+var schemeNavKeys = map[KeyScheme]navKeys{
+	SchemeDefault: {[]string{"up", "k"}, []string{"down", "j"}, []string{"left", "h"}, []string{"right", "l"}},
+	SchemeVim:     {[]string{"k"}, []string{"j"}, []string{"h"}, []string{"l"}},
+	SchemeArrows:  {[]string{"up"}, []string{"down"}, []string{"left"}, []string{"right"}},
+}
+
+// Key map errors
+var (
+	ErrKeyConflict   = errors.New("conflicting key bindings")
+	ErrUnknownScheme = errors.New("unknown key scheme")
+	ErrUnknownAction = errors.New("unknown key binding")
+)
+
+// Binding maps an action to its keys. Help is the footer label; bindings
+// without one are left out of the generated help.
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+}
+
+// KeyMap holds the key bindings of every view.
+type KeyMap struct {
+	Scheme   KeyScheme
+	bindings map[ViewType][]Binding
+}
+
+// NewKeyMap creates the default bindings for a navigation scheme.
+func NewKeyMap(scheme KeyScheme) (*KeyMap, error) {
+	nav, ok := schemeNavKeys[scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (want default, vim or arrows)", ErrUnknownScheme, scheme)
+	}
+	return &KeyMap{Scheme: scheme, bindings: defaultBindings(nav)}, nil
+}
+
+// DefaultKeyMap returns the built-in key bindings.
+func DefaultKeyMap() *KeyMap {
+	km, _ := NewKeyMap(SchemeDefault)
+	return km
+}
+
+// keys joins key lists into a new slice.
+func keys(lists ...[]string) []string {
+	var joined []string
+	for _, list := range lists {
+		joined = append(joined, list...)
+	}
+	return joined
+}
+
+// k is shorthand for a literal key list.
+func k(keys ...string) []string {
+	return keys
+}
+
+// defaultBindings lists every view's bindings in help order.
+func defaultBindings(nav navKeys) map[ViewType][]Binding {
+	back := Binding{ActionBack, k("esc", "b"), "Back"}
+	navigate := []Binding{
+		{ActionUp, nav.up, "Navigate"},
+		{ActionDown, nav.down, "Navigate"},
+	}
+
+	return map[ViewType][]Binding{
+		keyScopeGlobal: {
+			{ActionHelp, k("?"), "Help"},
+			{ActionSave, k("ctrl+s"), "Save"},
+			{ActionQuit, k("q", "ctrl+c"), "Quit"},
+		},
+		keyScopeHelp: {
+			{ActionTutorial, k("t"), "Skip/restart tutorial"},
+			{ActionBack, k("esc", "b"), "Close"},
+		},
+		keyScopeConfirm: {
+			{ActionConfirm, k("y", "Y", "enter"), "Yes"},
+			{ActionCancel, k("n", "N", "esc"), "No"},
+		},
+		ViewTower: {
+			{ActionOpenSpells, k("s"), "Spells"},
+			{ActionOpenRituals, k("r"), "Rituals"},
+			{ActionOpenRotation, k("o"), "Rotation"},
+			{ActionOpenStats, k("t"), "Stats"},
+			{ActionOpenPrestige, k("p"), "Prestige"},
+			{ActionOpenAutomation, k("x"), "Automation"},
+			{ActionOpenAchievements, k("v"), "Achievements"},
+			{ActionOpenLeaderboard, k("w"), "Leaderboard"},
+			{ActionOpenBoss, k("f"), "Boss Fight"},
+			{ActionOpenGraphs, k("g"), "Graphs"},
+			{ActionOpenEventLog, k("l"), "Log"},
+			{ActionOpenSettings, k("c"), "Settings"},
+			{ActionOpenMenu, k("m"), "Menu"},
+			{ActionAutoCast, k("a"), "Auto-cast"},
+		},
+		ViewSpells: {
+			{ActionUp, nav.up, "Select"},
+			{ActionDown, nav.down, "Select"},
+			{ActionCast, k("enter"), "Cast"},
+			{ActionUpgrade, k("u", "U"), "Upgrade"},
+			{ActionSlot, k(" "), "Slot"},
+			{ActionMoveUp, k("<", ","), "Order"},
+			{ActionMoveDown, k(">", "."), "Order"},
+			{ActionCondition, k("c"), "Cond"},
+			{ActionSpecialize, k("x"), "Spec"},
+			{ActionAutoCast, k("a"), "Auto"},
+			back,
+		},
+		ViewRituals: append(navigate,
+			Binding{ActionSelect, k("enter", " "), "Select"},
+			Binding{ActionClear, k("c"), "Clear"},
+			Binding{ActionReset, k("x", "X"), "Reset Rituals"},
+			back,
+		),
+		ViewStats: {
+			{ActionLeft, keys(nav.left, k("shift+tab")), "Tab"},
+			{ActionRight, keys(nav.right, k("tab")), "Tab"},
+			{ActionUp, nav.up, "Scroll eras"},
+			{ActionDown, nav.down, "Scroll eras"},
+			back,
+		},
+		ViewAchievements: {
+			{ActionLeft, keys(nav.left, k("shift+tab")), "Category"},
+			{ActionRight, keys(nav.right, k("tab")), "Category"},
+			back,
+		},
+		ViewLeaderboard: {
+			{ActionLeft, keys(nav.left, k("shift+tab")), "Board"},
+			{ActionRight, keys(nav.right, k("tab")), "Board"},
+			{ActionRefresh, k("r"), "Refresh"},
+			back,
+		},
+		ViewEventLog: {
+			{ActionLeft, keys(nav.left, k("shift+tab")), "Filter"},
+			{ActionRight, keys(nav.right, k("tab")), "Filter"},
+			{ActionUp, nav.up, "Scroll"},
+			{ActionDown, nav.down, "Scroll"},
+			{ActionPageUp, k("pgup"), "Page"},
+			{ActionPageDown, k("pgdown"), "Page"},
+			{ActionTop, k("home"), ""},
+			{ActionPanel, k("p"), "Tower panel"},
+			{ActionDebugLog, k("d"), "Debug log"},
+			back,
+		},
+		ViewDebugLog: {
+			{ActionLeft, keys(nav.left, k("shift+tab")), "Level"},
+			{ActionRight, keys(nav.right, k("tab")), "Level"},
+			{ActionUp, nav.up, "Scroll"},
+			{ActionDown, nav.down, "Scroll"},
+			{ActionPageUp, k("pgup"), "Page"},
+			{ActionPageDown, k("pgdown"), "Page"},
+			{ActionTop, k("home"), ""},
+			back,
+		},
+		ViewGraphs: {
+			{ActionLeft, keys(nav.left, k("-")), "Time window"},
+			{ActionRight, keys(nav.right, k("+", "=")), "Time window"},
+			back,
+		},
+		ViewSettings: append(navigate,
+			Binding{ActionLeft, keys(nav.left, k("-")), "Change"},
+			Binding{ActionRight, keys(nav.right, k("+", "=", "enter", " ")), "Change"},
+			back,
+		),
+		ViewPrestige: {
+			{ActionAscend, k("enter"), "Ascend"},
+			back,
+		},
+		ViewMenu: {
+			{ActionSave, k("s"), "Save Game"},
+			back,
+		},
+		ViewSpecialize: {
+			{ActionUp, keys(nav.up, nav.left), "Select"},
+			{ActionDown, keys(nav.down, nav.right), "Select"},
+			{ActionSelect, k("enter", " "), "Confirm"},
+			{ActionBack, k("esc", "b"), "Cancel"},
+		},
+		ViewFloorEvent: {
+			{ActionUp, nav.up, "Choose"},
+			{ActionDown, nav.down, "Choose"},
+			{ActionSelect, k("enter", " "), "Select"},
+			{ActionBack, k("esc", "b"), "Ignore"},
+		},
+		ViewBoss: {
+			{ActionUp, nav.up, "Select"},
+			{ActionDown, nav.down, "Select"},
+			{ActionCast, k("enter", " "), "Cast"},
+			{ActionSpecialize, k("x"), "Spec"},
+			back,
+		},
+		ViewRotation: append(navigate,
+			Binding{ActionAdd, k("a"), "Add Spell"},
+			Binding{ActionDelete, k("d", "delete"), "Remove"},
+			Binding{ActionMoveUp, k("<", ","), "Reorder"},
+			Binding{ActionMoveDown, k(">", "."), "Reorder"},
+			Binding{ActionPriority, k("p"), "Cycle Priority"},
+			Binding{ActionCondition, k("c"), "Cycle Condition"},
+			Binding{ActionEditExpression, k("e"), "Edit Expression"},
+			Binding{ActionToggle, k(" "), "Enable/Disable"},
+			Binding{ActionEnableRotation, k("o"), "Rotation On/Off"},
+			Binding{ActionConvert, k("v"), "Convert Auto-Cast"},
+			Binding{ActionWeaving, k("w"), "Weaving"},
+			Binding{ActionIdle, k("i"), "Idle Mode"},
+			Binding{ActionReserveDown, k("-", "_"), "Mana Reserve"},
+			Binding{ActionReserveUp, k("+", "="), "Mana Reserve"},
+			Binding{ActionOptimize, k("z"), "Optimize (sigil/s)"},
+			Binding{ActionOptimizeFloors, k("Z"), "Optimize (floors/hr)"},
+			Binding{ActionUndo, k("u"), "Undo Optimize"},
+			back,
+		),
+		keyScopeRotationPicker: append(navigate,
+			Binding{ActionSelect, k("enter", "a"), "Add Spell"},
+			Binding{ActionBack, k("esc", "b"), "Cancel"},
+		),
+		ViewAutomation: {
+			{ActionUp, nav.up, "Select"},
+			{ActionDown, nav.down, "Select"},
+			{ActionToggle, k(" ", "enter"), "Toggle rule / Apply preset"},
+			{ActionLeft, keys(nav.left, k("-", "_")), "Adjust"},
+			{ActionRight, keys(nav.right, k("+", "=")), "Adjust"},
+			{ActionNewPreset, k("n"), "Save loadout"},
+			{ActionOverwritePreset, k("s"), "Overwrite preset"},
+			{ActionReapplyPreset, k("r"), "Use after prestige"},
+			{ActionDelete, k("d", "delete"), "Delete"},
+			back,
+		},
+	}
+}
+
+// Action returns the action bound to key in view, falling back to the
+// global bindings. Confirmations only use their own bindings.
+func (km *KeyMap) Action(view ViewType, key string) Action {
+	if action := findAction(km.bindings[view], key); action != ActionNone || view == keyScopeConfirm {
+		return action
+	}
+	return findAction(km.bindings[keyScopeGlobal], key)
+}
+
+// findAction returns the action bound to key, if any.
+func findAction(bindings []Binding, key string) Action {
+	for _, b := range bindings {
+		for _, bound := range b.Keys {
+			if bound == key {
+				return b.Action
+			}
+		}
+	}
+	return ActionNone
+}
+
+// Keys returns the keys bound to an action in view.
+func (km *KeyMap) Keys(view ViewType, action Action) []string {
+	for _, b := range km.bindings[view] {
+		if b.Action == action {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// Label returns the primary keys of actions formatted for display, e.g.
+// "[U]" or "[-/+]". Global bindings are used when view has none.
+func (km *KeyMap) Label(view ViewType, actions ...Action) string {
+	shown := make([]string, 0, len(actions))
+	for _, action := range actions {
+		bound := km.Keys(view, action)
+		if len(bound) == 0 {
+			bound = km.Keys(keyScopeGlobal, action)
+		}
+		if len(bound) == 0 {
+			shown = append(shown, "-")
+			continue
+		}
+		shown = append(shown, displayKey(bound[0]))
+	}
+	return "[" + strings.Join(shown, "/") + "]"
+}
+
+// HelpEntry is one "[keys] label" item of generated help.
+type HelpEntry struct {
+	Keys  string
+	Label string
+}
+
+// HelpEntries lists the documented bindings of the given scopes in order.
+// Consecutive bindings sharing a label are merged (e.g. "[↑/↓] Navigate").
+func (km *KeyMap) HelpEntries(scopes ...ViewType) []HelpEntry {
+	var entries []HelpEntry
+	for _, scope := range scopes {
+		for _, b := range km.bindings[scope] {
+			if b.Help == "" || len(b.Keys) == 0 {
+				continue
+			}
+			key := displayKey(b.Keys[0])
+			if n := len(entries); n > 0 && entries[n-1].Label == b.Help {
+				entries[n-1].Keys += "/" + key
+				continue
+			}
+			entries = append(entries, HelpEntry{Keys: key, Label: b.Help})
+		}
+	}
+	return entries
+}
+
+// helpWidth is where generated footers wrap.
+const helpWidth = 90
+
+// Help renders the bindings of the given scopes as footer text, wrapped to
+// fit the screen.
+func (km *KeyMap) Help(scopes ...ViewType) string {
+	var lines []string
+	line := ""
+	for _, entry := range km.HelpEntries(scopes...) {
+		item := fmt.Sprintf("[%s] %s", entry.Keys, entry.Label)
+		if line != "" && len([]rune(line))+2+len([]rune(item)) > helpWidth {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += "  "
+		}
+		line += item
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// keyNames are display names for non-character keys.
+var keyNames = map[string]string{
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	" ":         "Space",
+	"enter":     "Enter",
+	"esc":       "Esc",
+	"delete":    "Del",
+	"tab":       "Tab",
+	"shift+tab": "Shift+Tab",
+	"pgup":      "PgUp",
+	"pgdown":    "PgDn",
+	"home":      "Home",
+}
+
+// displayKey formats a key for help text: letters are shown upper-case (as
+// the game's help always has) and upper-case bindings as Shift+letter.
+func displayKey(key string) string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	if rest, ok := strings.CutPrefix(key, "ctrl+"); ok {
+		return "Ctrl+" + strings.ToUpper(rest)
+	}
+	runes := []rune(key)
+	if len(runes) == 1 && unicode.IsLetter(runes[0]) {
+		if unicode.IsUpper(runes[0]) {
+			return "Shift+" + key
+		}
+		return strings.ToUpper(key)
+	}
+	return key
+}
+
+// Bind replaces the keys of an action in view.
+func (km *KeyMap) Bind(view ViewType, action Action, bound []string) error {
+	for i, b := range km.bindings[view] {
+		if b.Action == action {
+			km.bindings[view][i].Keys = bound
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s.%s", ErrUnknownAction, view, action)
+}
+
+// Validate reports keys bound to more than one action within a view
+// (including global bindings, which apply everywhere).
+func (km *KeyMap) Validate() error {
+	var conflicts []string
+	for view, bindings := range km.bindings {
+		scopes := [][]Binding{bindings}
+		if view != keyScopeGlobal && view != keyScopeConfirm {
+			scopes = append(scopes, km.bindings[keyScopeGlobal])
+		}
+		owner := map[string]Action{}
+		for _, scope := range scopes {
+			for _, b := range scope {
+				for _, key := range b.Keys {
+					if existing, ok := owner[key]; ok && existing != b.Action {
+						conflicts = append(conflicts, fmt.Sprintf("%s: %q is bound to both %s and %s", view, key, existing, b.Action))
+						continue
+					}
+					owner[key] = b.Action
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("%w:\n  %s", ErrKeyConflict, strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
+
+// DefaultKeyMapPath returns the user's key bindings file (~/.manatty/keys.toml).
+func DefaultKeyMapPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".manatty", "keys.toml"), nil
+}
+
+// LoadKeyMap reads key bindings from a TOML file. A missing file yields the
+// default bindings. The file may set a navigation scheme and override the
+// keys of any action per view:
+//
+//	scheme = "vim"
+//
+//	[tower]
+//	spells = ["s", "1"]
+//
+//	[global]
+//	quit = ["ctrl+q"]
+func LoadKeyMap(path string) (*KeyMap, error) {
+	var raw map[string]interface{}
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultKeyMap(), nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	scheme := SchemeDefault
+	if value, ok := raw["scheme"]; ok {
+		name, _ := value.(string)
+		scheme = KeyScheme(name)
+	}
+	km, err := NewKeyMap(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	for section, value := range raw {
+		if section == "scheme" {
+			continue
+		}
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a table of actions", ErrUnknownAction, section)
+		}
+		view := ViewType(section)
+		if _, ok := km.bindings[view]; !ok {
+			return nil, fmt.Errorf("%w: unknown view %q", ErrUnknownAction, section)
+		}
+		for action, keyList := range table {
+			bound, err := parseKeyList(keyList)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", section, action, err)
+			}
+			if err := km.Bind(view, Action(action), bound); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := km.Validate(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// parseKeyList accepts either a single key or a list of keys.
+func parseKeyList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		bound := make([]string, 0, len(v))
+		for _, item := range v {
+			key, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("keys must be strings, got %v", item)
+			}
+			bound = append(bound, key)
+		}
+		return bound, nil
+	}
+	return nil, fmt.Errorf("keys must be a string or list of strings, got %v", value)
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// leaderboardSize is how many players a leaderboard shows.
+const leaderboardSize = 15
+
+// LeaderboardLoadedMsg carries a leaderboard read from storage.
+type LeaderboardLoadedMsg struct {
+	Board   string // Leaderboard ID
+	Entries []models.LeaderboardEntry
+	Error   error
+}
+
+// leaderboardCmd returns a command that reads the selected leaderboard.
+func (m Model) leaderboardCmd() tea.Cmd {
+	if m.playerStore == nil {
+		return nil
+	}
+	board, store := models.Leaderboards[m.leaderboardTab], m.playerStore
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		entries, err := store.Leaderboard(ctx, board, leaderboardSize)
+		return LeaderboardLoadedMsg{Board: board.ID, Entries: entries, Error: err}
+	}
+}
+
+// handleLeaderboardLoaded shows a leaderboard unless another was selected
+// while it loaded.
+func (m Model) handleLeaderboardLoaded(msg LeaderboardLoadedMsg) (tea.Model, tea.Cmd) {
+	board := models.Leaderboards[m.leaderboardTab]
+	if msg.Board != board.ID {
+		return m, nil
+	}
+	m.leaderboardLoading = false
+	m.leaderboardErr = msg.Error
+	if msg.Error != nil {
+		utils.Log(utils.LogWarn, "leaderboard failed", "board", board.ID, "err", msg.Error)
+		return m, nil
+	}
+	m.leaderboard = m.withOwnEntry(board, msg.Entries)
+	return m, nil
+}
+
+// withOwnEntry puts the player's live value on the board, since storage
+// only has it as of the last save.
+func (m Model) withOwnEntry(board models.Leaderboard, entries []models.LeaderboardEntry) []models.LeaderboardEntry {
+	value := board.Value(m.player)
+	if value <= 0 {
+		return entries
+	}
+	merged := []models.LeaderboardEntry{{PlayerUUID: m.player.UUID, Username: m.player.Username, Value: value}}
+	for _, entry := range entries {
+		if entry.PlayerUUID != m.player.UUID {
+			merged = append(merged, entry)
+		}
+	}
+	board.Rank(merged)
+	if len(merged) > leaderboardSize {
+		merged = merged[:leaderboardSize]
+	}
+	return merged
+}
+
+// handleLeaderboardKeys handles keys in the leaderboard view.
+func (m Model) handleLeaderboardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tabs := len(models.Leaderboards)
+	tab := m.leaderboardTab
+	if idx, ok := digitKey(msg, tabs); ok {
+		tab = idx
+	}
+
+	switch m.keys.Action(ViewLeaderboard, msg.String()) {
+	case ActionLeft:
+		tab = (tab + tabs - 1) % tabs
+	case ActionRight:
+		tab = (tab + 1) % tabs
+	case ActionRefresh:
+		return m.openLeaderboard(tab)
+	case ActionBack:
+		m.Navigate(ViewTower)
+		return m, nil
+	}
+	if tab != m.leaderboardTab {
+		return m.openLeaderboard(tab)
+	}
+	return m, nil
+}
+
+// openLeaderboard selects a leaderboard tab and starts reading it.
+func (m Model) openLeaderboard(tab int) (tea.Model, tea.Cmd) {
+	m.leaderboardTab = tab
+	m.leaderboard = nil
+	m.leaderboardErr = nil
+	m.leaderboardLoading = m.playerStore != nil
+	return m, m.leaderboardCmd()
+}
+
+// viewLeaderboard renders the selected leaderboard.
+func (m Model) viewLeaderboard() string {
+	sym := m.styles.Symbols()
+	var lines []string
+
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(sym.Prestige + " LEADERBOARD"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	var tabs []string
+	for i, board := range models.Leaderboards {
+		label := fmt.Sprintf(" %d %s ", i+1, board.Name)
+		if i == m.leaderboardTab {
+			tabs = append(tabs, m.styles.Selected.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	lines = append(lines, "")
+
+	board := models.Leaderboards[m.leaderboardTab]
+	switch {
+	case m.playerStore == nil:
+		lines = append(lines, m.styles.Dim.Render("  Leaderboards are unavailable for this session."))
+	case m.leaderboardLoading:
+		lines = append(lines, m.styles.Dim.Render("  Loading..."))
+	case m.leaderboardErr != nil:
+		lines = append(lines, m.styles.Error.Render("  Can't read the leaderboard: "+m.leaderboardErr.Error()))
+	case len(m.leaderboard) == 0:
+		lines = append(lines, m.styles.Dim.Render("  Nobody has a record yet."))
+	default:
+		nameWidth := 0
+		for _, entry := range m.leaderboard {
+			nameWidth = max(nameWidth, lipgloss.Width(entry.Username))
+		}
+		for _, entry := range m.leaderboard {
+			row := fmt.Sprintf("  %3d. %-*s  %s", entry.Rank, nameWidth, entry.Username, formatLeaderboardValue(board, entry.Value))
+			if m.player != nil && entry.PlayerUUID == m.player.UUID {
+				lines = append(lines, m.styles.Highlight.Render(row+"  "+sym.Arrow+" you"))
+			} else {
+				lines = append(lines, m.styles.Text.Render(row))
+			}
+		}
+	}
+	lines = append(lines, "")
+
+	if value := board.Value(m.player); value > 0 {
+		lines = append(lines, m.styles.Subtitle.Render("Your best: "+formatLeaderboardValue(board, value)))
+	} else {
+		lines = append(lines, m.styles.Dim.Render("You have no record here yet."))
+	}
+
+	lines = append(lines, m.styles.Footer.Render(fmt.Sprintf("[1-%d] Jump  ", len(models.Leaderboards))+m.keys.Help(ViewLeaderboard)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// formatLeaderboardValue renders a leaderboard value in the board's unit.
+func formatLeaderboardValue(board models.Leaderboard, value float64) string {
+	switch board.Stat {
+	case models.StatHighestFloor:
+		return fmt.Sprintf("Floor %d", int(value))
+	case models.StatAscensions:
+		return fmt.Sprintf("%d eras", int(value))
+	case models.StatFastestFloor100Ms:
+		return utils.FormatDuration(time.Duration(value) * time.Millisecond)
+	default:
+		return utils.FormatNumber(value)
+	}
+}
@@ -3,25 +3,38 @@ package ui
 import (
 	"time"
 
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/daemon"
 	"github.com/Ltorre/ManaTTY/engine"
 	"github.com/Ltorre/ManaTTY/models"
 	"github.com/Ltorre/ManaTTY/storage"
+	"github.com/Ltorre/ManaTTY/ui/components"
+	"github.com/Ltorre/ManaTTY/utils"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // ViewType represents the current screen being displayed.
 type ViewType string
 
 const (
-	ViewTower      ViewType = "tower"
-	ViewSpells     ViewType = "spells"
-	ViewRituals    ViewType = "rituals"
-	ViewStats      ViewType = "stats"
-	ViewPrestige   ViewType = "prestige"
-	ViewMenu       ViewType = "menu"
-	ViewSpecialize ViewType = "specialize"
-	ViewFloorEvent ViewType = "floor_event"
-	ViewRotation   ViewType = "rotation" // v1.5.0
+	ViewTower        ViewType = "tower"
+	ViewSpells       ViewType = "spells"
+	ViewRituals      ViewType = "rituals"
+	ViewStats        ViewType = "stats"
+	ViewPrestige     ViewType = "prestige"
+	ViewMenu         ViewType = "menu"
+	ViewSpecialize   ViewType = "specialize"
+	ViewFloorEvent   ViewType = "floor_event"
+	ViewBoss         ViewType = "boss"
+	ViewRotation     ViewType = "rotation" // v1.5.0
+	ViewAutomation   ViewType = "automation"
+	ViewAchievements ViewType = "achievements"
+	ViewGraphs       ViewType = "graphs"
+	ViewEventLog     ViewType = "event_log"
+	ViewSettings     ViewType = "settings"
+	ViewDebugLog     ViewType = "debug_log"
+	ViewLeaderboard  ViewType = "leaderboard"
 )
 
 // Model is the main Bubble Tea model for the game.
@@ -32,8 +45,43 @@ type Model struct {
 	engine    *engine.GameEngine
 
 	// Storage (supports both MongoDB and local JSON)
-	db        *storage.Database // Keep for backward compatibility (may be nil)
-	saveStore storage.SaveStore
+	db          *storage.Database // Keep for backward compatibility (may be nil)
+	saveStore   storage.SaveStore
+	playerStore storage.PlayerStore
+
+	// Achievement progress for the current player (may be nil)
+	achievements   *engine.AchievementTracker
+	achievementTab int
+
+	// Stats view tab and previous-eras scroll offset
+	statsTab    int
+	statsScroll int
+
+	// Graphs view time window (index into graphWindows)
+	graphWindow int
+
+	// Leaderboard view: selected board (index into models.Leaderboards) and
+	// its last read
+	leaderboardTab     int
+	leaderboard        []models.LeaderboardEntry
+	leaderboardErr     error
+	leaderboardLoading bool
+
+	// Whether the challenge run's completion has been announced
+	challengeAnnounced bool
+
+	// Boss fight being followed (its floor, 0 for none), and the wins and
+	// artifacts before it, to tell how it ended
+	bossFloor     int
+	bossWins      int
+	bossArtifacts int
+
+	// Event log view and tower side panel (log may be nil)
+	eventLog    *engine.EventLog
+	eventCursor uint64 // Where this session's announcements read the log from
+	eventFilter engine.EventFilter
+	eventScroll int
+	eventPanel  bool
 
 	// UI state
 	currentView  ViewType
@@ -51,12 +99,36 @@ type Model struct {
 	confirmText   string
 	confirmAction string
 
-	// Notifications
-	notification     string
-	notificationTime time.Time
+	// Notifications (stacked toasts)
+	notifications *components.NotificationQueue
+
+	// Debug log view: tail of the log file, newest first (path may be empty)
+	logPath    string
+	logLines   []string
+	logLevel   utils.LogLevel
+	logScroll  int
+	logReadAt  time.Time
+	logReadErr error
+
+	// Game loop: ticks, autosaves and locks the game state (may be nil)
+	loop *daemon.Loop
+	// Set when cfg is this session's own, not the one the loop runs by
+	sessionConfig bool
+
+	// Key bindings
+	keys *KeyMap
+
+	// Theme, symbols and color profile, never nil
+	styles *Styles
+
+	// Settings (tick rate, autosave, notifications...), never nil
+	cfg *config.Config
+
+	// Help overlay and first-run tutorial (tutorial may be nil)
+	showHelp bool
+	tutorial *engine.Tutorial
 
 	// Timing
-	lastUpdate      time.Time
 	tickInterval    time.Duration
 	lastSkipCheckAt time.Time // For aggregated mana hints
 
@@ -70,6 +142,26 @@ type Model struct {
 	specSpellID   string // Spell being specialized
 	specTier      int    // Which tier (1 or 2)
 	specChoiceIdx int    // 0 or 1 selection
+
+	// Rotation dry-run preview (baseline is the plan before the last edit)
+	rotationPlan         *engine.RotationPlan
+	rotationPlanBaseline *engine.RotationPlan
+	rotationPlanAt       time.Time
+
+	// Rotation optimizer (previous rotation kept for undo)
+	optimizing             bool
+	optimizeResult         *engine.OptimizeResult
+	rotationBeforeOptimize *models.SpellRotation
+	pendingOptimize        *engine.OptimizeResult // Waiting for the player to confirm replacing an edited rotation
+
+	// Rotation editor: add-spell picker
+	addingRotationSpell bool
+	rotationPickIdx     int
+
+	// Rotation condition expression editor
+	editingExpr bool
+	exprInput   string
+	exprError   error
 }
 
 // NewModel creates a new UI model.
@@ -78,10 +170,13 @@ func NewModel() *Model {
 	return &Model{
 		currentView:     ViewTower,
 		previousView:    ViewTower,
-		lastUpdate:      now,
 		lastSkipCheckAt: now,
 		tickInterval:    100 * time.Millisecond, // 10 FPS
 		ritualSpells:    make([]string, 0, 3),
+		notifications:   components.NewNotificationQueue(maxToasts),
+		keys:            DefaultKeyMap(),
+		cfg:             config.DefaultConfig(),
+		styles:          NewStyles(nil),
 	}
 }
 
@@ -100,11 +195,92 @@ func (m *Model) SetEngine(e *engine.GameEngine) {
 	m.engine = e
 }
 
+// now returns the game's time, which runs on the engine's tick clock while
+// a replay is recorded.
+func (m Model) now() time.Time {
+	if m.engine == nil {
+		return time.Now()
+	}
+	return m.engine.Now()
+}
+
 // SetSaveStore sets the save store (interface supports both MongoDB and local JSON).
 func (m *Model) SetSaveStore(s storage.SaveStore) {
 	m.saveStore = s
 }
 
+// SetPlayerStore sets the player store used to persist the profile (achievements etc.).
+func (m *Model) SetPlayerStore(s storage.PlayerStore) {
+	m.playerStore = s
+}
+
+// SetAchievementTracker sets the tracker that records the player's achievements.
+func (m *Model) SetAchievementTracker(t *engine.AchievementTracker) {
+	m.achievements = t
+}
+
+// SetKeyMap sets the key bindings (see LoadKeyMap).
+func (m *Model) SetKeyMap(km *KeyMap) {
+	m.keys = km
+}
+
+// SetEventLog sets the engine event history shown in the event log view.
+// The session announces events recorded from now on.
+func (m *Model) SetEventLog(l *engine.EventLog) {
+	m.eventLog = l
+	m.eventCursor = l.Cursor()
+}
+
+// SetConfig applies the player-facing settings of cfg (tick rate, symbols,
+// notifications...). The Settings view edits cfg and saves it to cfg.Path.
+func (m *Model) SetConfig(cfg *config.Config) {
+	m.cfg = cfg
+	m.applyConfig()
+}
+
+// SetSessionConfig is SetConfig for a session sharing a game loop that runs
+// by a config of its own. The loop's settings (tick rate and autosave) are
+// then shown as set by the server.
+func (m *Model) SetSessionConfig(cfg *config.Config) {
+	m.SetConfig(cfg)
+	m.sessionConfig = true
+}
+
+// applyConfig applies the current settings to the running UI.
+func (m *Model) applyConfig() {
+	m.tickInterval = time.Second / time.Duration(m.cfg.GameTickRate)
+	m.styles.SetSymbolMode(m.cfg.Symbols)
+}
+
+// SetRenderer sets the renderer for the model's terminal, so its colors
+// match the terminal's color profile. It defaults to lipgloss's renderer
+// for the process's own terminal.
+func (m *Model) SetRenderer(r *lipgloss.Renderer) {
+	m.styles.SetRenderer(r)
+}
+
+// SetTheme switches the model to the named theme. An empty name selects
+// the default theme.
+func (m *Model) SetTheme(name string) error {
+	return m.styles.SetTheme(name)
+}
+
+// SetLogFile sets the log file shown in the debug log view.
+func (m *Model) SetLogFile(path string) {
+	m.logPath = path
+}
+
+// SetLoop sets the game loop. The TUI steps it on every tick unless it is
+// already running on its own (in the daemon).
+func (m *Model) SetLoop(l *daemon.Loop) {
+	m.loop = l
+}
+
+// SetTutorial sets the first-run tutorial shown to the player.
+func (m *Model) SetTutorial(t *engine.Tutorial) {
+	m.tutorial = t
+}
+
 // SetDatabase sets the database connection (for backward compatibility, may be nil).
 func (m *Model) SetDatabase(db *storage.Database) {
 	m.db = db
@@ -125,15 +301,50 @@ func (m Model) tickCmd() tea.Cmd {
 	})
 }
 
-// ShowNotification displays a notification message.
+// maxToasts is how many notifications are stacked on screen at once.
+const maxToasts = 4
+
+// ShowNotification displays an informational notification.
 func (m *Model) ShowNotification(msg string) {
-	m.notification = msg
-	m.notificationTime = time.Now()
+	m.Notify(msg, components.NotifyInfo)
 }
 
-// ClearNotification clears the current notification.
-func (m *Model) ClearNotification() {
-	m.notification = ""
+// Notify displays a notification of the given type at normal priority.
+func (m *Model) Notify(text string, notifyType components.NotificationType) {
+	m.PushNotification(components.NewNotification(text, notifyType))
+}
+
+// PushNotification queues a notification, merging it with a live one that
+// has the same key.
+func (m *Model) PushNotification(n *components.Notification) {
+	if !m.wantsNotification(n) {
+		return
+	}
+	if m.notifications == nil {
+		m.notifications = components.NewNotificationQueue(maxToasts)
+	}
+	m.notifications.Push(n)
+}
+
+// wantsNotification applies the notification verbosity setting.
+func (m *Model) wantsNotification(n *components.Notification) bool {
+	switch m.cfg.Notifications {
+	case config.NotificationsNormal:
+		return n.Priority != components.PriorityLow
+	case config.NotificationsQuiet:
+		if n.Priority == components.PriorityHigh {
+			return true
+		}
+		return n.Priority == components.PriorityNormal && (n.Type == components.NotifyWarning || n.Type == components.NotifyError)
+	}
+	return true
+}
+
+// DismissNotification removes a notification by key (e.g. a sticky prompt).
+func (m *Model) DismissNotification(key string) {
+	if m.notifications != nil {
+		m.notifications.Dismiss(key)
+	}
 }
 
 // Navigate changes the current view.
@@ -225,3 +436,10 @@ type NotificationMsg struct {
 type ErrorMsg struct {
 	Error error
 }
+
+// RotationOptimizedMsg carries the result of a background rotation optimization.
+type RotationOptimizedMsg struct {
+	Result *engine.OptimizeResult
+	Game   *models.GameState // Game the optimizer ran for
+	Base   string            // Rotation it started from (see rotationFingerprint)
+}
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/ui/components"
+	"github.com/Ltorre/ManaTTY/utils"
+)
+
+// setting is one adjustable row of the Settings view.
+type setting struct {
+	Label string
+	// Value returns the current value for display.
+	Value func(m *Model) string
+	// Detail returns an optional description of the current value.
+	Detail func(m *Model) string
+	// Adjust steps the value by delta (-1 or +1) and returns a command that
+	// persists it, if any.
+	Adjust func(m *Model, delta int) tea.Cmd
+	// Loop marks settings the game loop runs by, which a session with its
+	// own config can't change.
+	Loop bool
+}
+
+// locked reports whether s can't be changed in this session.
+func (s setting) locked(m *Model) bool {
+	return s.Loop && m.sessionConfig
+}
+
+// Setting choices
+var (
+	tickRateOptions     = []int{1, 2, 5, 10, 20, 30, 60}
+	autoSaveOptions     = []int{10, 15, 30, 60, 120, 300}
+	symbolModes         = []string{config.SymbolsAuto, config.SymbolsEmoji, config.SymbolsASCII}
+	notificationLevels  = []string{config.NotificationsAll, config.NotificationsNormal, config.NotificationsQuiet}
+	notificationDetails = map[string]string{
+		config.NotificationsAll:    "Every notification",
+		config.NotificationsNormal: "Hide routine ones (upgrades, automation, skipped casts)",
+		config.NotificationsQuiet:  "Only achievements, floor events, warnings and errors",
+	}
+)
+
+// settings lists the Settings view rows in display order.
+var settings = []setting{
+	{
+		Label: "Theme",
+		Value: func(m *Model) string { return m.styles.Theme().Name },
+		Detail: func(m *Model) string {
+			theme, err := ThemeByName(m.styles.Theme().Name)
+			if err != nil {
+				return ""
+			}
+			return theme.Description
+		},
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			names := make([]string, len(Themes))
+			for i, theme := range Themes {
+				names[i] = theme.Name
+			}
+			name := cycleString(names, m.styles.Theme().Name, delta)
+			_ = m.styles.SetTheme(name)
+			// The theme follows the profile; the config file keeps it as
+			// the default for profiles that haven't picked one
+			m.cfg.Theme = name
+			m.cfg.Changed(config.KeyTheme)
+			if m.player == nil {
+				return m.saveConfigCmd()
+			}
+			m.player.Theme = name
+			return tea.Batch(m.savePlayerCmd(), m.saveConfigCmd())
+		},
+	},
+	{
+		Label: "Tick rate",
+		Value: func(m *Model) string {
+			return fmt.Sprintf("%d/s", m.cfg.GameTickRate)
+		},
+		Detail: func(m *Model) string {
+			return fmt.Sprintf("Screen and game updates every %s", time.Second/time.Duration(m.cfg.GameTickRate))
+		},
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			m.cfg.GameTickRate = cycleInt(tickRateOptions, m.cfg.GameTickRate, delta)
+			m.cfg.Changed(config.KeyTickRate)
+			m.applyConfig()
+			return m.saveConfigCmd()
+		},
+		Loop: true,
+	},
+	{
+		Label: "Autosave",
+		Value: func(m *Model) string {
+			return utils.FormatDuration(time.Duration(m.cfg.AutoSaveInterval) * time.Second)
+		},
+		Detail: func(m *Model) string { return "Time between automatic saves" },
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			m.cfg.AutoSaveInterval = cycleInt(autoSaveOptions, m.cfg.AutoSaveInterval, delta)
+			m.cfg.Changed(config.KeyAutoSaveInterval)
+			return m.saveConfigCmd()
+		},
+		Loop: true,
+	},
+	{
+		Label: "Symbols",
+		Value: func(m *Model) string { return m.cfg.Symbols },
+		Detail: func(m *Model) string {
+			if m.cfg.Symbols != config.SymbolsAuto {
+				return ""
+			}
+			if SupportsEmoji() {
+				return "Detected: emoji"
+			}
+			return "Detected: ASCII"
+		},
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			m.cfg.Symbols = cycleString(symbolModes, m.cfg.Symbols, delta)
+			m.cfg.Changed(config.KeySymbols)
+			m.applyConfig()
+			return m.saveConfigCmd()
+		},
+	},
+	{
+		Label:  "Notifications",
+		Value:  func(m *Model) string { return m.cfg.Notifications },
+		Detail: func(m *Model) string { return notificationDetails[m.cfg.Notifications] },
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			m.cfg.Notifications = cycleString(notificationLevels, m.cfg.Notifications, delta)
+			m.cfg.Changed(config.KeyNotifications)
+			return m.saveConfigCmd()
+		},
+	},
+	{
+		Label: "Confirmations",
+		Value: func(m *Model) string {
+			if m.cfg.ConfirmPrompts {
+				return "on"
+			}
+			return "off"
+		},
+		Detail: func(m *Model) string { return "Ask before prestige and resetting rituals" },
+		Adjust: func(m *Model, delta int) tea.Cmd {
+			m.cfg.ConfirmPrompts = !m.cfg.ConfirmPrompts
+			m.cfg.Changed(config.KeyConfirmPrompts)
+			return m.saveConfigCmd()
+		},
+	},
+}
+
+// cycleInt steps from current to the next (delta > 0) or previous option,
+// wrapping around. Values between options snap to the nearest one first.
+func cycleInt(options []int, current, delta int) int {
+	idx := 0
+	for i, option := range options {
+		if abs(option-current) < abs(options[idx]-current) {
+			idx = i
+		}
+	}
+	if options[idx] == current {
+		idx += delta
+	}
+	return options[(idx%len(options)+len(options))%len(options)]
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// cycleString steps from current to the next (delta > 0) or previous option,
+// wrapping around.
+func cycleString(options []string, current string, delta int) string {
+	idx := 0
+	for i, option := range options {
+		if option == current {
+			idx = i + delta
+		}
+	}
+	return options[(idx%len(options)+len(options))%len(options)]
+}
+
+// saveConfigCmd returns a command that writes the settings to the config file.
+func (m Model) saveConfigCmd() tea.Cmd {
+	if m.cfg.Path == "" {
+		return nil
+	}
+	cfg := m.cfg.Clone()
+	return func() tea.Msg {
+		if err := cfg.SaveSettings(); err != nil {
+			return ErrorMsg{Error: err}
+		}
+		return nil
+	}
+}
+
+// handleSettingsKeys handles keys in the settings view.
+func (m Model) handleSettingsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.keys.Action(ViewSettings, msg.String()); action {
+	case ActionUp:
+		if m.selectedIndex > 0 {
+			m.selectedIndex--
+		}
+	case ActionDown:
+		if m.selectedIndex < len(settings)-1 {
+			m.selectedIndex++
+		}
+	case ActionLeft, ActionRight:
+		s := settings[m.selectedIndex]
+		if s.locked(&m) {
+			m.Notify(s.Label+" is set by the server", components.NotifyInfo)
+			return m, nil
+		}
+		delta := 1
+		if action == ActionLeft {
+			delta = -1
+		}
+		return m, s.Adjust(&m, delta)
+	case ActionBack:
+		m.Navigate(ViewTower)
+	}
+	return m, nil
+}
+
+// viewSettings renders the settings view.
+func (m Model) viewSettings() string {
+	var lines []string
+	lines = append(lines, m.styles.Title.Render(m.styles.Symbols().Settings+" SETTINGS"))
+
+	labelWidth, valueWidth := 0, 0
+	for _, s := range settings {
+		labelWidth = max(labelWidth, len(s.Label))
+		valueWidth = max(valueWidth, lipgloss.Width(s.Value(&m)))
+	}
+	for i, s := range settings {
+		value := s.Value(&m)
+		locked := s.locked(&m)
+		if locked {
+			value = "  " + value + "  " + strings.Repeat(" ", valueWidth-lipgloss.Width(value))
+		} else {
+			value = "◀ " + value + " ▶" + strings.Repeat(" ", valueWidth-lipgloss.Width(value))
+		}
+		row := fmt.Sprintf("  %-*s  %s", labelWidth, s.Label, value)
+		switch {
+		case i == m.selectedIndex:
+			row = m.styles.Selected.Render("> " + row[2:])
+		case locked:
+			row = m.styles.Dim.Render(row)
+		default:
+			row = m.styles.Text.Render(row)
+		}
+		if locked {
+			row += "  " + m.styles.Dim.Render("Set by the server")
+		} else if s.Detail != nil {
+			if detail := s.Detail(&m); detail != "" {
+				row += "  " + m.styles.Dim.Render(detail)
+			}
+		}
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, m.styles.Subtitle.Render("Preview"))
+	lines = append(lines, "  "+m.renderThemePreview())
+
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewSettings)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderThemePreview shows the element and status colors of the current
+// theme, each with its icon and label.
+func (m Model) renderThemePreview() string {
+	var elements []string
+	for _, element := range []string{"fire", "ice", "thunder", "arcane"} {
+		elements = append(elements, m.styles.GetElementStyle(element).Render(m.styles.GetElementIcon(element)+" "+m.styles.GetElementLabel(element)))
+	}
+	sym := m.styles.Symbols()
+	status := []string{
+		m.styles.Success.Render(sym.Check + " Success"),
+		m.styles.Warning.Render("! Warning"),
+		m.styles.Error.Render(sym.Cross + " Error"),
+		m.styles.Highlight.Render(sym.Star + " Highlight"),
+	}
+	return strings.Join(elements, "  ") + "\n  " + strings.Join(status, "  ")
+}
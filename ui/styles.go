@@ -2,162 +2,263 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Ltorre/ManaTTY/config"
+	"github.com/Ltorre/ManaTTY/ui/components"
 )
 
-// Color palette for the game
-var (
+// Styles is how one TUI draws: a theme's colors and the styles built from
+// them, a symbol set, and the renderer for its terminal's color profile.
+// Every Model has its own, so TUIs sharing a process (daemon clients, lobby
+// sessions) never restyle each other.
+type Styles struct {
+	renderer *lipgloss.Renderer
+	theme    Theme
+	emoji    bool
+	symbols  Symbols
+	palette  *components.Palette
+
 	// Primary colors
-	ColorPrimary   = lipgloss.Color("#7C3AED") // Purple
-	ColorSecondary = lipgloss.Color("#10B981") // Green
-	ColorAccent    = lipgloss.Color("#F59E0B") // Amber
+	ColorPrimary   lipgloss.Color
+	ColorSecondary lipgloss.Color
+	ColorAccent    lipgloss.Color
 
 	// Status colors
-	ColorSuccess = lipgloss.Color("#22C55E") // Green
-	ColorWarning = lipgloss.Color("#EAB308") // Yellow
-	ColorError   = lipgloss.Color("#EF4444") // Red
-	ColorInfo    = lipgloss.Color("#3B82F6") // Blue
+	ColorSuccess lipgloss.Color
+	ColorWarning lipgloss.Color
+	ColorError   lipgloss.Color
+	ColorInfo    lipgloss.Color
 
 	// Element colors
-	ColorFire    = lipgloss.Color("#EF4444") // Red
-	ColorIce     = lipgloss.Color("#06B6D4") // Cyan
-	ColorThunder = lipgloss.Color("#FACC15") // Yellow
-	ColorArcane  = lipgloss.Color("#A855F7") // Purple
+	ColorFire    lipgloss.Color
+	ColorIce     lipgloss.Color
+	ColorThunder lipgloss.Color
+	ColorArcane  lipgloss.Color
 
 	// UI colors
-	ColorBorder    = lipgloss.Color("#6B7280") // Gray
-	ColorBorderDim = lipgloss.Color("#374151") // Darker gray
-	ColorText      = lipgloss.Color("#F9FAFB") // Almost white
-	ColorTextDim   = lipgloss.Color("#9CA3AF") // Gray text
-	ColorBg        = lipgloss.Color("#111827") // Dark background
-	ColorBgAlt     = lipgloss.Color("#1F2937") // Slightly lighter bg
-)
+	ColorBorder    lipgloss.Color
+	ColorBorderDim lipgloss.Color
+	ColorText      lipgloss.Color
+	ColorTextDim   lipgloss.Color
+	ColorBg        lipgloss.Color
+	ColorBgAlt     lipgloss.Color
 
-// Base styles
-var (
-	// Title style
-	TitleStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
-			Bold(true).
-			MarginBottom(1)
-
-	// Subtitle style
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Bold(true)
-
-	// Normal text
-	TextStyle = lipgloss.NewStyle().
-			Foreground(ColorText)
-
-	// Dimmed text
-	DimStyle = lipgloss.NewStyle().
-			Foreground(ColorTextDim)
-
-	// Highlight style
-	HighlightStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true)
-
-	// Error style
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorError)
-
-	// Success style
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess)
-
-	// Warning style
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(ColorWarning)
-
-	// Selected item style
-	SelectedStyle = lipgloss.NewStyle().
-			Foreground(ColorText).
-			Background(ColorBgAlt).
-			Bold(true)
-)
+	// Base styles
+	Title     lipgloss.Style // Title
+	Subtitle  lipgloss.Style // Subtitle
+	Text      lipgloss.Style // Normal text
+	Dim       lipgloss.Style // Dimmed text
+	Highlight lipgloss.Style // Highlight
+	Error     lipgloss.Style // Error
+	Success   lipgloss.Style // Success
+	Warning   lipgloss.Style // Warning
+	Selected  lipgloss.Style // Selected item
 
-// Box styles
-var (
-	// Main container
-	ContainerStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorBorder).
-			Padding(1, 2)
-
-	// Header box
-	HeaderStyle = lipgloss.NewStyle().
-			Border(lipgloss.DoubleBorder()).
-			BorderForeground(ColorPrimary).
-			Padding(0, 2).
-			Align(lipgloss.Center)
-
-	// Section style
-	SectionStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(ColorBorderDim).
-			Padding(0, 1)
-
-	// Footer/help style
-	FooterStyle = lipgloss.NewStyle().
-			Foreground(ColorTextDim).
-			MarginTop(1)
-)
+	// Box styles
+	Container lipgloss.Style // Main container
+	Header    lipgloss.Style // Header box
+	Section   lipgloss.Style // Section
+	Footer    lipgloss.Style // Footer/help
+
+	// Element-specific styles
+	Fire    lipgloss.Style
+	Ice     lipgloss.Style
+	Thunder lipgloss.Style
+	Arcane  lipgloss.Style
 
-// Element-specific styles
-var (
-	FireStyle = lipgloss.NewStyle().
-			Foreground(ColorFire)
+	// Progress bar styles
+	ProgressBarFilled lipgloss.Style
+	ProgressBarEmpty  lipgloss.Style
+}
 
-	IceStyle = lipgloss.NewStyle().
-			Foreground(ColorIce)
+// NewStyles creates styles for r (lipgloss's default renderer if nil) with
+// the default theme and the symbols the terminal supports.
+func NewStyles(r *lipgloss.Renderer) *Styles {
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+	s := &Styles{renderer: r, theme: Themes[0]}
+	s.SetSymbolMode(config.SymbolsAuto)
+	return s
+}
 
-	ThunderStyle = lipgloss.NewStyle().
-			Foreground(ColorThunder)
+// SetRenderer switches the renderer the styles are built for.
+func (s *Styles) SetRenderer(r *lipgloss.Renderer) {
+	s.renderer = r
+	s.build()
+}
 
-	ArcaneStyle = lipgloss.NewStyle().
-			Foreground(ColorArcane)
-)
+// NewStyle returns an empty style for the TUI's renderer.
+func (s *Styles) NewStyle() lipgloss.Style {
+	return s.renderer.NewStyle()
+}
+
+// Palette returns the colors and glyphs components render with.
+func (s *Styles) Palette() *components.Palette {
+	return s.palette
+}
+
+// build rebuilds every style from the theme and symbol set.
+func (s *Styles) build() {
+	t := s.theme
+	s.ColorPrimary = lipgloss.Color(t.Primary)
+	s.ColorSecondary = lipgloss.Color(t.Secondary)
+	s.ColorAccent = lipgloss.Color(t.Accent)
+	s.ColorSuccess = lipgloss.Color(t.Success)
+	s.ColorWarning = lipgloss.Color(t.Warning)
+	s.ColorError = lipgloss.Color(t.Error)
+	s.ColorInfo = lipgloss.Color(t.Info)
+	s.ColorFire = lipgloss.Color(t.Fire)
+	s.ColorIce = lipgloss.Color(t.Ice)
+	s.ColorThunder = lipgloss.Color(t.Thunder)
+	s.ColorArcane = lipgloss.Color(t.Arcane)
+	s.ColorBorder = lipgloss.Color(t.Border)
+	s.ColorBorderDim = lipgloss.Color(t.BorderDim)
+	s.ColorText = lipgloss.Color(t.Text)
+	s.ColorTextDim = lipgloss.Color(t.TextDim)
+	s.ColorBg = lipgloss.Color(t.Bg)
+	s.ColorBgAlt = lipgloss.Color(t.BgAlt)
+
+	r := s.renderer
+	s.Title = r.NewStyle().
+		Foreground(s.ColorPrimary).
+		Bold(true).
+		MarginBottom(1)
+
+	s.Subtitle = r.NewStyle().
+		Foreground(s.ColorSecondary).
+		Bold(true)
+
+	s.Text = r.NewStyle().
+		Foreground(s.ColorText)
+
+	s.Dim = r.NewStyle().
+		Foreground(s.ColorTextDim)
+
+	s.Highlight = r.NewStyle().
+		Foreground(s.ColorAccent).
+		Bold(true)
+
+	s.Error = r.NewStyle().
+		Foreground(s.ColorError)
+
+	s.Success = r.NewStyle().
+		Foreground(s.ColorSuccess)
+
+	s.Warning = r.NewStyle().
+		Foreground(s.ColorWarning)
+
+	s.Selected = r.NewStyle().
+		Foreground(s.ColorText).
+		Background(s.ColorBgAlt).
+		Bold(true)
+
+	s.Container = r.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.ColorBorder).
+		Padding(1, 2)
+
+	s.Header = r.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(s.ColorPrimary).
+		Padding(0, 2).
+		Align(lipgloss.Center)
+
+	s.Section = r.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(s.ColorBorderDim).
+		Padding(0, 1)
+
+	s.Footer = r.NewStyle().
+		Foreground(s.ColorTextDim).
+		MarginTop(1)
+
+	s.Fire = r.NewStyle().Foreground(s.ColorFire)
+	s.Ice = r.NewStyle().Foreground(s.ColorIce)
+	s.Thunder = r.NewStyle().Foreground(s.ColorThunder)
+	s.Arcane = r.NewStyle().Foreground(s.ColorArcane)
+
+	s.ProgressBarFilled = r.NewStyle().Foreground(s.ColorSuccess)
+	s.ProgressBarEmpty = r.NewStyle().Foreground(s.ColorBorderDim)
+
+	// Charts fall back to ASCII along with the symbols
+	sparkLevels, braille := []rune("▁▂▃▄▅▆▇█"), true
+	if !s.emoji {
+		sparkLevels, braille = []rune("_.-=+*#@"), false
+	}
+	s.palette = components.NewPalette(r, components.Palette{
+		Primary:  s.ColorPrimary,
+		Text:     s.ColorText,
+		TextDim:  s.ColorTextDim,
+		Selected: s.ColorBgAlt,
+		Empty:    s.ColorBorderDim,
+		Success:  s.ColorSuccess,
+		Warning:  s.ColorWarning,
+		Error:    s.ColorError,
+		Info:     s.ColorInfo,
+		Elements: map[string]lipgloss.Color{
+			"fire":    s.ColorFire,
+			"ice":     s.ColorIce,
+			"thunder": s.ColorThunder,
+			"arcane":  s.ColorArcane,
+		},
+		Icons: map[string]string{
+			"fire":    s.symbols.Fire,
+			"ice":     s.symbols.Ice,
+			"thunder": s.symbols.Thunder,
+			"arcane":  s.symbols.Arcane,
+			"default": s.symbols.Default,
+		},
+		SparkLevels: sparkLevels,
+		UseBraille:  braille,
+	})
+}
 
 // GetElementStyle returns the style for an element type.
-func GetElementStyle(element string) lipgloss.Style {
+func (s *Styles) GetElementStyle(element string) lipgloss.Style {
+	switch element {
+	case "fire":
+		return s.Fire
+	case "ice":
+		return s.Ice
+	case "thunder":
+		return s.Thunder
+	case "arcane":
+		return s.Arcane
+	default:
+		return s.Text
+	}
+}
+
+// GetElementLabel returns the display name of an element type.
+func (s *Styles) GetElementLabel(element string) string {
 	switch element {
 	case "fire":
-		return FireStyle
+		return s.symbols.FireLabel
 	case "ice":
-		return IceStyle
+		return s.symbols.IceLabel
 	case "thunder":
-		return ThunderStyle
+		return s.symbols.ThunderLabel
 	case "arcane":
-		return ArcaneStyle
+		return s.symbols.ArcaneLabel
 	default:
-		return TextStyle
+		return element
 	}
 }
 
 // GetElementIcon returns an icon for an element type.
 // Uses ASCII fallbacks on Windows for compatibility.
-func GetElementIcon(element string) string {
-	sym := GetSymbols()
+func (s *Styles) GetElementIcon(element string) string {
 	switch element {
 	case "fire":
-		return sym.Fire
+		return s.symbols.Fire
 	case "ice":
-		return sym.Ice
+		return s.symbols.Ice
 	case "thunder":
-		return sym.Thunder
+		return s.symbols.Thunder
 	case "arcane":
-		return sym.Arcane
+		return s.symbols.Arcane
 	default:
-		return sym.Default
+		return s.symbols.Default
 	}
 }
-
-// Progress bar styles
-var (
-	ProgressBarFilled = lipgloss.NewStyle().
-				Foreground(ColorSuccess)
-
-	ProgressBarEmpty = lipgloss.NewStyle().
-				Foreground(ColorBorderDim)
-)
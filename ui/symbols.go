@@ -3,6 +3,8 @@ package ui
 import (
 	"os"
 	"runtime"
+
+	"github.com/Ltorre/ManaTTY/config"
 )
 
 // Symbols holds all the icons/emojis used in the UI.
@@ -15,12 +17,19 @@ type Symbols struct {
 	Arcane  string
 	Default string
 
+	// Element labels, so elements never rely on color alone
+	FireLabel    string
+	IceLabel     string
+	ThunderLabel string
+	ArcaneLabel  string
+
 	// UI icons
 	Tower    string
 	Stats    string
 	Ritual   string
 	AutoCast string
 	Event    string
+	Boss     string
 	Mana     string
 	Floor    string
 	Damage   string
@@ -28,6 +37,7 @@ type Symbols struct {
 	Wave     string
 	Prestige string
 	Synergy  string
+	Settings string
 
 	// Status
 	Ready     string
@@ -43,9 +53,6 @@ type Symbols struct {
 	Cross  string
 }
 
-// symbols is the global symbol set, initialized based on terminal capabilities.
-var symbols Symbols
-
 // supportsEmoji detects if the current terminal supports emoji display.
 // Returns true for:
 // - All non-Windows systems (macOS, Linux, etc.)
@@ -88,10 +95,26 @@ func supportsEmoji() bool {
 	return false
 }
 
-func init() {
-	if supportsEmoji() {
+// SetSymbolMode switches between emoji and ASCII symbols. mode is
+// config.SymbolsEmoji, config.SymbolsASCII or config.SymbolsAuto (detect
+// terminal support).
+func (s *Styles) SetSymbolMode(mode string) {
+	switch mode {
+	case config.SymbolsEmoji:
+		s.applySymbols(true)
+	case config.SymbolsASCII:
+		s.applySymbols(false)
+	default:
+		s.applySymbols(supportsEmoji())
+	}
+}
+
+// applySymbols selects the emoji or ASCII symbol set.
+func (s *Styles) applySymbols(emoji bool) {
+	s.emoji = emoji
+	if emoji {
 		// Full emoji support
-		symbols = Symbols{
+		s.symbols = Symbols{
 			// Elements
 			Fire:    "🔥",
 			Ice:     "❄️",
@@ -99,12 +122,18 @@ func init() {
 			Arcane:  "✨",
 			Default: "•",
 
+			FireLabel:    "Fire",
+			IceLabel:     "Ice",
+			ThunderLabel: "Thunder",
+			ArcaneLabel:  "Arcane",
+
 			// UI icons
 			Tower:    "🏰",
 			Stats:    "📊",
 			Ritual:   "🔥",
 			AutoCast: "⚡",
 			Event:    "✨",
+			Boss:     "👹",
 			Mana:     "💎",
 			Floor:    "🏔️",
 			Damage:   "💥",
@@ -112,6 +141,7 @@ func init() {
 			Wave:     "🌊",
 			Prestige: "⭐",
 			Synergy:  "🔥",
+			Settings: "⚙",
 
 			// Status
 			Ready:     "✓",
@@ -128,7 +158,7 @@ func init() {
 		}
 	} else {
 		// ASCII fallbacks for legacy Windows CMD
-		symbols = Symbols{
+		s.symbols = Symbols{
 			// Elements - use colored text markers instead of emojis
 			Fire:    "[F]",
 			Ice:     "[I]",
@@ -136,12 +166,18 @@ func init() {
 			Arcane:  "[A]",
 			Default: "*",
 
+			FireLabel:    "Fire",
+			IceLabel:     "Ice",
+			ThunderLabel: "Thunder",
+			ArcaneLabel:  "Arcane",
+
 			// UI icons
 			Tower:    "#",
 			Stats:    "*",
 			Ritual:   "~",
 			AutoCast: ">",
 			Event:    "!",
+			Boss:     "B",
 			Mana:     "o",
 			Floor:    "^",
 			Damage:   "!",
@@ -149,6 +185,7 @@ func init() {
 			Wave:     "~",
 			Prestige: "+",
 			Synergy:  "*",
+			Settings: "%",
 
 			// Status
 			Ready:     "+",
@@ -164,11 +201,17 @@ func init() {
 			Cross:  "x",
 		}
 	}
+	s.build()
+}
+
+// Symbols returns the symbol set.
+func (s *Styles) Symbols() Symbols {
+	return s.symbols
 }
 
-// GetSymbols returns the current symbol set.
-func GetSymbols() Symbols {
-	return symbols
+// UsingEmoji returns true if the emoji symbol set is active.
+func (s *Styles) UsingEmoji() bool {
+	return s.emoji
 }
 
 // SupportsEmoji returns true if the terminal supports emoji display.
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownTheme is returned when a theme name doesn't match any theme.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Theme is a named color palette. Colors are hex strings.
+type Theme struct {
+	Name        string
+	Description string
+
+	Primary   string
+	Secondary string
+	Accent    string
+
+	Success string
+	Warning string
+	Error   string
+	Info    string
+
+	Border    string
+	BorderDim string
+	Text      string
+	TextDim   string
+	Bg        string
+	BgAlt     string
+
+	Fire    string
+	Ice     string
+	Thunder string
+	Arcane  string
+}
+
+// Themes lists the built-in themes; the first is the default.
+var Themes = []Theme{
+	{
+		Name: "default", Description: "Purple and amber on dark",
+		Primary: "#7C3AED", Secondary: "#10B981", Accent: "#F59E0B",
+		Success: "#22C55E", Warning: "#EAB308", Error: "#EF4444", Info: "#3B82F6",
+		Border: "#6B7280", BorderDim: "#374151", Text: "#F9FAFB", TextDim: "#9CA3AF", Bg: "#111827", BgAlt: "#1F2937",
+		Fire: "#EF4444", Ice: "#06B6D4", Thunder: "#FACC15", Arcane: "#A855F7",
+	},
+	{
+		Name: "high-contrast", Description: "Bright, saturated colors and white text",
+		Primary: "#FF00FF", Secondary: "#00FFFF", Accent: "#FFFF00",
+		Success: "#00FF00", Warning: "#FFFF00", Error: "#FF3030", Info: "#00BFFF",
+		Border: "#FFFFFF", BorderDim: "#C0C0C0", Text: "#FFFFFF", TextDim: "#E0E0E0", Bg: "#000000", BgAlt: "#0000C0",
+		Fire: "#FF3030", Ice: "#00FFFF", Thunder: "#FFFF00", Arcane: "#FF00FF",
+	},
+	{
+		// Okabe-Ito palette, distinguishable with the common forms of color blindness
+		Name: "colorblind", Description: "Colorblind-safe element and status colors",
+		Primary: "#0072B2", Secondary: "#009E73", Accent: "#E69F00",
+		Success: "#009E73", Warning: "#E69F00", Error: "#D55E00", Info: "#56B4E9",
+		Border: "#6B7280", BorderDim: "#374151", Text: "#F9FAFB", TextDim: "#9CA3AF", Bg: "#111827", BgAlt: "#1F2937",
+		Fire: "#D55E00", Ice: "#56B4E9", Thunder: "#F0E442", Arcane: "#CC79A7",
+	},
+	{
+		Name: "monochrome", Description: "Shades of gray only",
+		Primary: "#FFFFFF", Secondary: "#E0E0E0", Accent: "#FFFFFF",
+		Success: "#E0E0E0", Warning: "#E0E0E0", Error: "#FFFFFF", Info: "#C0C0C0",
+		Border: "#A0A0A0", BorderDim: "#606060", Text: "#E0E0E0", TextDim: "#909090", Bg: "#000000", BgAlt: "#404040",
+		Fire: "#E0E0E0", Ice: "#E0E0E0", Thunder: "#E0E0E0", Arcane: "#E0E0E0",
+	},
+}
+
+// ThemeByName returns the built-in theme called name.
+func ThemeByName(name string) (Theme, error) {
+	for _, theme := range Themes {
+		if theme.Name == name {
+			return theme, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+}
+
+// Theme returns the applied theme.
+func (s *Styles) Theme() Theme {
+	return s.theme
+}
+
+// SetTheme switches every color to the named theme. An empty name selects
+// the default theme.
+func (s *Styles) SetTheme(name string) error {
+	theme := Themes[0]
+	if name != "" {
+		var err error
+		if theme, err = ThemeByName(name); err != nil {
+			return err
+		}
+	}
+	s.theme = theme
+	s.build()
+	return nil
+}
@@ -2,16 +2,32 @@ package ui
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Ltorre/ManaTTY/engine"
 	"github.com/Ltorre/ManaTTY/game"
 	"github.com/Ltorre/ManaTTY/models"
+	"github.com/Ltorre/ManaTTY/ui/components"
+	"github.com/Ltorre/ManaTTY/utils"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Update handles all messages and updates the model.
+// Update handles all messages and updates the model. The game state is
+// locked throughout, as the game loop, the HTTP API and other attached
+// terminals share it.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.loop != nil {
+		m.loop.Lock()
+		defer m.loop.Unlock()
+	}
+	return m.update(msg)
+}
+
+// update handles a message with the game state locked.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	// Window size
@@ -23,7 +39,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Keyboard input
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		prevView := m.currentView
+		before := m.rotationFingerprint()
+		updated, cmd := m.handleKeyPress(msg)
+		if next, ok := updated.(Model); ok && next.currentView == ViewRotation {
+			if prevView != ViewRotation {
+				next.rotationPlanBaseline = nil
+				next.refreshRotationPlan()
+			} else if next.rotationFingerprint() != before {
+				// Keep the old plan so the view can show the effect of the edit
+				next.rotationPlanBaseline = next.rotationPlan
+				next.refreshRotationPlan()
+			}
+			return next, cmd
+		}
+		return updated, cmd
 
 	// Game tick
 	case TickMsg:
@@ -32,31 +62,55 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Save complete
 	case SaveCompleteMsg:
 		if msg.Error != nil {
-			m.ShowNotification("Save failed!")
+			utils.Log(utils.LogError, "save failed", "err", msg.Error)
+			m.PushNotification(components.NewNotification("Save failed!", components.NotifyError).WithPriority(components.PriorityHigh))
 		} else {
-			m.ShowNotification("Game saved!")
+			m.PushNotification(components.NewNotification("Game saved!", components.NotifySuccess).WithPriority(components.PriorityLow))
+			utils.Log(utils.LogDebug, "game saved")
 		}
 		return m, nil
 
 	// Load complete
 	case LoadCompleteMsg:
 		if msg.Error != nil {
-			m.ShowNotification("Load failed!")
+			utils.Log(utils.LogError, "load failed", "err", msg.Error)
+			m.Notify("Load failed!", components.NotifyError)
 		} else if msg.GameState != nil {
 			m.gameState = msg.GameState
-			m.ShowNotification("Game loaded!")
+			if m.loop != nil {
+				m.loop.SetGameState(m.gameState)
+			}
+			if m.achievements != nil {
+				m.achievements.SetGameState(m.gameState)
+			}
+			m.Notify("Game loaded!", components.NotifySuccess)
+			if m.eventLog != nil {
+				m.eventLog.Record(game.NewEvent(game.EventGameLoaded, "Game loaded"))
+			}
 		}
 		return m, nil
 
+	// Rotation optimizer finished
+	case RotationOptimizedMsg:
+		return m.handleRotationOptimized(msg)
+
+	case LeaderboardLoadedMsg:
+		return m.handleLeaderboardLoaded(msg)
+
 	// Notification
 	case NotificationMsg:
-		m.ShowNotification(msg.Text)
+		n := components.NewNotification(msg.Text, components.NotifyInfo)
+		if msg.Duration > 0 {
+			n.Duration = msg.Duration
+		}
+		m.PushNotification(n)
 		return m, nil
 
 	// Error
 	case ErrorMsg:
 		m.lastError = msg.Error
-		m.ShowNotification("Error: " + msg.Error.Error())
+		utils.Log(utils.LogError, msg.Error.Error())
+		m.Notify("Error: "+msg.Error.Error(), components.NotifyError)
 		return m, nil
 	}
 
@@ -70,15 +124,29 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleConfirmKey(msg)
 	}
 
+	// Text entry captures every key, including global shortcuts
+	if m.editingExpr {
+		return m.handleExprEditKey(msg)
+	}
+
 	// Global keys
-	switch msg.String() {
-	case "ctrl+c", "q":
+	switch m.keys.Action(keyScopeGlobal, msg.String()) {
+	case ActionQuit:
 		// Save and quit
 		return m, tea.Sequence(m.saveGameCmd(), tea.Quit)
 
-	case "ctrl+s":
+	case ActionSave:
 		// Manual save
 		return m, m.saveGameCmd()
+
+	case ActionHelp:
+		m.showHelp = !m.showHelp
+		return m, nil
+	}
+
+	// The help overlay sits on top of the current view
+	if m.showHelp {
+		return m.handleHelpKeys(msg)
 	}
 
 	// View-specific keys
@@ -99,8 +167,24 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSpecializeKeys(msg)
 	case ViewFloorEvent:
 		return m.handleFloorEventKeys(msg)
+	case ViewBoss:
+		return m.handleBossKeys(msg)
 	case ViewRotation:
 		return m.handleRotationKeys(msg)
+	case ViewAutomation:
+		return m.handleAutomationKeys(msg)
+	case ViewAchievements:
+		return m.handleAchievementsKeys(msg)
+	case ViewGraphs:
+		return m.handleGraphsKeys(msg)
+	case ViewLeaderboard:
+		return m.handleLeaderboardKeys(msg)
+	case ViewEventLog:
+		return m.handleEventLogKeys(msg)
+	case ViewDebugLog:
+		return m.handleDebugLogKeys(msg)
+	case ViewSettings:
+		return m.handleSettingsKeys(msg)
 	}
 
 	return m, nil
@@ -113,30 +197,30 @@ func (m Model) handleFloorEventKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch msg.String() {
-	case "up", "k":
+	// Number keys pick a choice directly
+	if idx, ok := digitKey(msg, 3); ok {
+		m.selectedIndex = idx
+		return m.handleFloorEventChoice()
+	}
+
+	switch m.keys.Action(ViewFloorEvent, msg.String()) {
+	case ActionUp:
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
+	case ActionDown:
 		if m.selectedIndex < 2 {
 			m.selectedIndex++
 		}
-	case "1":
-		m.selectedIndex = 0
-		return m.handleFloorEventChoice()
-	case "2":
-		m.selectedIndex = 1
-		return m.handleFloorEventChoice()
-	case "3":
-		m.selectedIndex = 2
-		return m.handleFloorEventChoice()
-	case "enter", " ":
+	case ActionSelect:
 		return m.handleFloorEventChoice()
-	case "esc", "b":
+	case ActionBack:
 		// Explicitly ignore the event (no bonus)
-		m.gameState.ClearFloorEvent()
+		if m.engine != nil {
+			m.engine.DismissFloorEvent(m.gameState)
+		}
 		m.GoBack()
+		m.DismissNotification(floorEventToastKey)
 		m.ShowNotification("Floor event ignored")
 	}
 
@@ -161,66 +245,114 @@ func (m Model) handleFloorEventChoice() (tea.Model, tea.Cmd) {
 		choice = models.FloorEventChoiceManaGen
 	}
 
-	currentFloor := 0
-	if m.gameState.Tower != nil {
-		currentFloor = m.gameState.Tower.CurrentFloor
-	}
-
-	m.gameState.ApplyFloorEventChoice(choice, currentFloor, game.FloorEventBuffDurationFloors)
+	m.engine.ChooseFloorEvent(m.gameState, choice)
 	m.GoBack()
-	m.ShowNotification("Floor event chosen: " + models.FloorEventChoiceDisplayNames[choice])
+	m.DismissNotification(floorEventToastKey)
+	m.Notify("Floor event chosen: "+models.FloorEventChoiceDisplayNames[choice], components.NotifySuccess)
 	return m, nil
 }
 
 // handleConfirmKey handles keys during confirmation.
 func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y", "enter":
+	switch m.keys.Action(keyScopeConfirm, msg.String()) {
+	case ActionConfirm:
 		action := m.confirmAction
 		m.CancelConfirm()
-		// Handle confirmed action based on action key
-		switch action {
-		case "prestige":
-			return m.handlePrestigeConfirmed()
-		case "reset_rituals":
-			if m.engine != nil && m.gameState != nil {
-				m.engine.ResetRituals(m.gameState)
-				m.ritualSpells = []string{}
-				m.ShowNotification("Rituals reset")
-			}
-			return m, nil
-		default:
-			return m, nil
-		}
+		return m.runConfirmedAction(action)
 
-	case "n", "N", "esc":
+	case ActionCancel:
+		if m.confirmAction == "apply_optimized" {
+			m.pendingOptimize = nil
+			m.ShowNotification("Kept your rotation")
+		}
 		m.CancelConfirm()
 		return m, nil
 	}
 	return m, nil
 }
 
+// confirmOrRun asks for confirmation before an action, or runs it straight
+// away when confirmation prompts are turned off.
+func (m Model) confirmOrRun(text string, action string) (tea.Model, tea.Cmd) {
+	if !m.cfg.ConfirmPrompts {
+		return m.runConfirmedAction(action)
+	}
+	m.StartConfirmAction(text, action)
+	return m, nil
+}
+
+// runConfirmedAction performs a confirmed action by its action key.
+func (m Model) runConfirmedAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case "prestige":
+		return m.handlePrestigeConfirmed()
+	case "reset_rituals":
+		if m.engine != nil && m.gameState != nil {
+			m.engine.ResetRituals(m.gameState)
+			m.ritualSpells = []string{}
+			m.ShowNotification("Rituals reset")
+		}
+	case "apply_optimized":
+		if m.pendingOptimize != nil && m.gameState != nil {
+			m.applyOptimizeResult(m.pendingOptimize)
+			m.pendingOptimize = nil
+		}
+	}
+	return m, nil
+}
+
+// digitKey returns the zero-based index of a number key 1-n. Number keys
+// jump straight to tabs and choices and are not remappable.
+func digitKey(msg tea.KeyMsg, n int) (int, bool) {
+	key := msg.String()
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return 0, false
+	}
+	idx := int(key[0] - '1')
+	return idx, idx < n
+}
+
 // handleTowerKeys handles keys in the tower view.
 func (m Model) handleTowerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "s":
+	switch m.keys.Action(ViewTower, msg.String()) {
+	case ActionOpenSpells:
 		m.Navigate(ViewSpells)
-	case "r":
+	case ActionOpenRituals:
 		m.Navigate(ViewRituals)
-	case "o":
+	case ActionOpenRotation:
 		// v1.5.0: Navigate to rotation view
 		m.Navigate(ViewRotation)
-	case "t":
+	case ActionOpenStats:
 		m.Navigate(ViewStats)
-	case "p":
+	case ActionOpenPrestige:
 		if m.engine != nil && m.engine.CanPrestige(m.gameState) {
 			m.Navigate(ViewPrestige)
 		} else {
-			m.ShowNotification("Reach floor 100 to prestige!")
+			m.Notify("Reach floor 100 to prestige!", components.NotifyWarning)
 		}
-	case "m":
+	case ActionOpenMenu:
 		m.Navigate(ViewMenu)
-	case "a":
+	case ActionOpenAutomation:
+		m.Navigate(ViewAutomation)
+	case ActionOpenAchievements:
+		m.Navigate(ViewAchievements)
+	case ActionOpenGraphs:
+		m.Navigate(ViewGraphs)
+	case ActionOpenLeaderboard:
+		m.Navigate(ViewLeaderboard)
+		return m.openLeaderboard(m.leaderboardTab)
+	case ActionOpenEventLog:
+		m.eventScroll = 0
+		m.Navigate(ViewEventLog)
+	case ActionOpenSettings:
+		m.Navigate(ViewSettings)
+	case ActionOpenBoss:
+		if m.gameState.Boss != nil {
+			m.Navigate(ViewBoss)
+		} else {
+			m.ShowNotification("No boss fight in progress")
+		}
+	case ActionAutoCast:
 		// Toggle auto-cast
 		if m.engine != nil {
 			enabled := m.engine.ToggleAutoCast(m.gameState)
@@ -236,54 +368,56 @@ func (m Model) handleTowerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleSpellsKeys handles keys in the spells view.
 func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
+	switch m.keys.Action(ViewSpells, msg.String()) {
+	case ActionUp:
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
+	case ActionDown:
 		if m.gameState != nil && m.selectedIndex < len(m.gameState.Spells)-1 {
 			m.selectedIndex++
 		}
-	case "enter":
+	case ActionCast:
 		// Cast selected spell manually
 		if m.gameState != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
 			if err := m.engine.CastSpell(m.gameState, spell, true); err != nil {
-				m.ShowNotification(err.Error())
+				m.Notify(err.Error(), components.NotifyWarning)
 			} else {
 				// Check for synergy activation and combine notification
-				if m.gameState.HasActiveSynergy() {
-					m.ShowNotification(fmt.Sprintf("%s cast! %s SYNERGY!", spell.Name, string(m.gameState.GetActiveSynergy())))
+				if nowMs := m.now().UnixMilli(); m.gameState.HasActiveSynergyAt(nowMs) {
+					m.Notify(fmt.Sprintf("%s cast! %s SYNERGY!", spell.Name, string(m.gameState.GetActiveSynergyAt(nowMs))), components.NotifySuccess)
 				} else {
-					m.ShowNotification(fmt.Sprintf("%s cast!", spell.Name))
+					m.PushNotification(components.NewNotification(fmt.Sprintf("%s cast!", spell.Name), components.NotifyInfo).WithPriority(components.PriorityLow))
 				}
 			}
 		}
-	case " ":
+	case ActionSlot:
 		// Toggle auto-cast slot for selected spell
 		if m.gameState != nil && m.engine != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
 			inSlot, err := m.engine.ToggleSpellAutoCast(m.gameState, spell.ID)
 			if err != nil {
-				m.ShowNotification(err.Error())
+				m.Notify(err.Error(), components.NotifyWarning)
 			} else if inSlot {
 				m.ShowNotification(spell.Name + " added to slot")
 			} else {
 				m.ShowNotification(spell.Name + " removed from slot")
 			}
 		}
-	case "u", "U":
+	case ActionUpgrade:
 		// Upgrade selected spell
 		if m.gameState != nil && m.engine != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
 			if err := m.engine.UpgradeSpell(m.gameState, spell); err != nil {
-				m.ShowNotification(err.Error())
+				m.Notify(err.Error(), components.NotifyWarning)
 			} else {
-				m.ShowNotification(fmt.Sprintf("%s upgraded to Lv%d!", spell.Name, spell.Level))
+				m.PushNotification(components.NewNotification(fmt.Sprintf("%s upgraded to Lv%d!", spell.Name, spell.Level), components.NotifySuccess).
+					WithPriority(components.PriorityLow).
+					WithKey("upgrade:" + spell.ID))
 			}
 		}
-	case "<", ",":
+	case ActionMoveUp:
 		// Move spell up in auto-cast priority
 		if m.gameState != nil && m.engine != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
@@ -295,7 +429,7 @@ func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.ShowNotification(fmt.Sprintf("%s already at top priority", spell.Name))
 			}
 		}
-	case ">", ".":
+	case ActionMoveDown:
 		// Move spell down in auto-cast priority
 		if m.gameState != nil && m.engine != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
@@ -307,9 +441,9 @@ func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.ShowNotification(fmt.Sprintf("%s already at lowest priority", spell.Name))
 			}
 		}
-	case "esc", "b":
+	case ActionBack:
 		m.Navigate(ViewTower)
-	case "a":
+	case ActionAutoCast:
 		// Toggle auto-cast on/off
 		if m.engine != nil {
 			enabled := m.engine.ToggleAutoCast(m.gameState)
@@ -319,7 +453,7 @@ func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.ShowNotification("Auto-cast disabled")
 			}
 		}
-	case "c":
+	case ActionCondition:
 		// Cycle auto-cast condition for selected spell
 		if m.gameState != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
@@ -330,21 +464,10 @@ func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.ShowNotification(spell.Name + " not in auto-cast slot")
 			}
 		}
-	case "x":
+	case ActionSpecialize:
 		// Open specialization menu if spell needs it
 		if m.gameState != nil && m.selectedIndex < len(m.gameState.Spells) {
-			spell := m.gameState.Spells[m.selectedIndex]
-			tier, needs := spell.NeedsSpecialization()
-			if needs {
-				m.specSpellID = spell.ID
-				m.specTier = tier
-				m.specChoiceIdx = 0
-				m.Navigate(ViewSpecialize)
-			} else if spell.Level >= 5 {
-				m.ShowNotification("Already specialized")
-			} else {
-				m.ShowNotification("Reach level 5 to specialize")
-			}
+			m.openSpecialize(m.gameState.Spells[m.selectedIndex])
 		}
 	}
 	return m, nil
@@ -352,14 +475,14 @@ func (m Model) handleSpellsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleRitualsKeys handles keys in the rituals view.
 func (m Model) handleRitualsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
+	switch m.keys.Action(ViewRituals, msg.String()) {
+	case ActionUp:
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
+	case ActionDown:
 		m.selectedIndex++
-	case "enter", " ":
+	case ActionSelect:
 		// Add spell to ritual builder or select ritual
 		if m.gameState != nil && m.selectedIndex < len(m.gameState.Spells) {
 			spell := m.gameState.Spells[m.selectedIndex]
@@ -378,27 +501,27 @@ func (m Model) handleRitualsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						// Create ritual
 						_, err := m.engine.CreateRitual(m.gameState, m.ritualSpells)
 						if err != nil {
-							m.ShowNotification(err.Error())
+							m.Notify(err.Error(), components.NotifyWarning)
 						} else {
-							m.ShowNotification("Ritual created!")
+							m.Notify("Ritual created!", components.NotifySuccess)
 						}
 						m.ritualSpells = []string{}
 					}
 				}
 			}
 		}
-	case "c":
+	case ActionClear:
 		// Clear ritual builder
 		m.ritualSpells = []string{}
 		m.ShowNotification("Selection cleared")
-	case "x", "X":
+	case ActionReset:
 		// Reset all rituals (free up ritual slots)
 		if m.gameState != nil && len(m.gameState.Rituals) > 0 {
-			m.StartConfirmAction("Reset ALL rituals for this save? (y/n)", "reset_rituals")
+			return m.confirmOrRun("Reset ALL rituals for this save? (y/n)", "reset_rituals")
 		} else {
 			m.ShowNotification("No rituals to reset")
 		}
-	case "esc", "b":
+	case ActionBack:
 		m.ritualSpells = []string{}
 		m.Navigate(ViewTower)
 	}
@@ -406,9 +529,96 @@ func (m Model) handleRitualsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // handleStatsKeys handles keys in the stats view.
+// handleEventLogKeys handles keys in the event log view.
+func (m Model) handleEventLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filters := len(engine.EventFilters)
+	if idx, ok := digitKey(msg, filters); ok {
+		m.eventFilter = engine.EventFilters[idx]
+		m.eventScroll = 0
+	}
+
+	switch m.keys.Action(ViewEventLog, msg.String()) {
+	case ActionLeft:
+		m.eventFilter = engine.EventFilters[(int(m.eventFilter)+filters-1)%filters]
+		m.eventScroll = 0
+	case ActionRight:
+		m.eventFilter = engine.EventFilters[(int(m.eventFilter)+1)%filters]
+		m.eventScroll = 0
+	case ActionUp:
+		if m.eventScroll > 0 {
+			m.eventScroll--
+		}
+	case ActionDown:
+		m.eventScroll++
+	case ActionPageUp:
+		m.eventScroll = max(m.eventScroll-eventLogPageSize, 0)
+	case ActionPageDown:
+		m.eventScroll += eventLogPageSize
+	case ActionTop:
+		m.eventScroll = 0
+	case ActionDebugLog:
+		m.logScroll = 0
+		m.refreshLog()
+		m.Navigate(ViewDebugLog)
+	case ActionPanel:
+		m.eventPanel = !m.eventPanel
+		if m.eventPanel {
+			m.ShowNotification("Event panel shown on tower view")
+		} else {
+			m.ShowNotification("Event panel hidden")
+		}
+	case ActionBack:
+		m.Navigate(ViewTower)
+	}
+
+	// Clamp scrolling to the filtered history
+	if m.eventLog != nil {
+		maxScroll := max(len(m.eventLog.Entries(m.eventFilter))-eventLogPageSize, 0)
+		m.eventScroll = min(m.eventScroll, maxScroll)
+	}
+	return m, nil
+}
+
+// handleGraphsKeys handles keys in the graphs view.
+func (m Model) handleGraphsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.keys.Action(ViewGraphs, msg.String()) {
+	case ActionLeft:
+		if m.graphWindow > 0 {
+			m.graphWindow--
+		}
+	case ActionRight:
+		if m.graphWindow < len(graphWindows)-1 {
+			m.graphWindow++
+		}
+	case ActionBack:
+		m.Navigate(ViewTower)
+	}
+	return m, nil
+}
+
 func (m Model) handleStatsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "b":
+	tabs := len(statsTabNames)
+	if idx, ok := digitKey(msg, tabs); ok {
+		m.statsTab = idx
+		m.statsScroll = 0
+	}
+
+	switch m.keys.Action(ViewStats, msg.String()) {
+	case ActionLeft:
+		m.statsTab = (m.statsTab + tabs - 1) % tabs
+		m.statsScroll = 0
+	case ActionRight:
+		m.statsTab = (m.statsTab + 1) % tabs
+		m.statsScroll = 0
+	case ActionUp:
+		if m.statsScroll > 0 {
+			m.statsScroll--
+		}
+	case ActionDown:
+		if m.gameState != nil && m.gameState.Ledger != nil && m.statsScroll < len(m.gameState.Ledger.PreviousEras)-1 {
+			m.statsScroll++
+		}
+	case ActionBack:
 		m.Navigate(ViewTower)
 	}
 	return m, nil
@@ -416,10 +626,10 @@ func (m Model) handleStatsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handlePrestigeKeys handles keys in the prestige view.
 func (m Model) handlePrestigeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "enter":
-		m.StartConfirmAction("Are you sure you want to prestige? (y/n)", "prestige")
-	case "esc", "b":
+	switch m.keys.Action(ViewPrestige, msg.String()) {
+	case ActionAscend:
+		return m.confirmOrRun("Are you sure you want to prestige? (y/n)", "prestige")
+	case ActionBack:
 		m.Navigate(ViewTower)
 	}
 	return m, nil
@@ -428,7 +638,8 @@ func (m Model) handlePrestigeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // handlePrestigeConfirmed handles confirmed prestige action.
 func (m Model) handlePrestigeConfirmed() (tea.Model, tea.Cmd) {
 	if m.engine != nil && m.engine.ProcessPrestige(m.gameState) {
-		m.ShowNotification("Ascended to Era " + string(rune('0'+m.gameState.PrestigeData.CurrentEra)) + "!")
+		m.PushNotification(components.NewNotification(fmt.Sprintf("Ascended to Era %d!", m.gameState.PrestigeData.CurrentEra), components.NotifySuccess).
+			WithPriority(components.PriorityHigh))
 		m.Navigate(ViewTower)
 	}
 	return m, nil
@@ -436,76 +647,97 @@ func (m Model) handlePrestigeConfirmed() (tea.Model, tea.Cmd) {
 
 // handleSpecializeKeys handles keys in the specialization view.
 func (m Model) handleSpecializeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k", "left", "h":
+	switch m.keys.Action(ViewSpecialize, msg.String()) {
+	case ActionUp:
 		if m.specChoiceIdx > 0 {
 			m.specChoiceIdx--
 		}
-	case "down", "j", "right", "l":
+	case ActionDown:
 		if m.specChoiceIdx < 1 {
 			m.specChoiceIdx++
 		}
-	case "enter", " ":
+	case ActionSelect:
 		// Apply specialization
-		if m.gameState != nil {
+		if m.gameState != nil && m.engine != nil {
 			spell := m.gameState.GetSpellByID(m.specSpellID)
 			if spell != nil {
-				var spec models.SpellSpecialization
-				if m.specTier == 1 {
-					if m.specChoiceIdx == 0 {
-						spec = models.SpecCritChance
-					} else {
-						spec = models.SpecManaEfficiency
-					}
-					spell.Tier1Spec = spec
+				spec := engine.SpecializationChoices(m.specTier)[m.specChoiceIdx]
+				if err := m.engine.Specialize(m.gameState, spell, spec); err != nil {
+					m.Notify(err.Error(), components.NotifyWarning)
 				} else {
-					if m.specChoiceIdx == 0 {
-						spec = models.SpecBurstDamage
-					} else {
-						spec = models.SpecRapidCast
-					}
-					spell.Tier2Spec = spec
+					m.ShowNotification(fmt.Sprintf("%s specialized: %s!", spell.Name, models.SpecializationDisplayNames[spec]))
 				}
-				m.ShowNotification(fmt.Sprintf("%s specialized: %s!", spell.Name, models.SpecializationDisplayNames[spec]))
-				m.Navigate(ViewSpells)
+				m.GoBack()
 			}
 		}
-	case "esc", "b":
-		m.Navigate(ViewSpells)
+	case ActionBack:
+		m.GoBack()
+	}
+	return m, nil
+}
+
+// handleAchievementsKeys handles keys in the achievements view.
+func (m Model) handleAchievementsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tabs := len(models.AchievementCategories)
+	if idx, ok := digitKey(msg, tabs); ok {
+		m.achievementTab = idx
+	}
+
+	switch m.keys.Action(ViewAchievements, msg.String()) {
+	case ActionLeft:
+		m.achievementTab = (m.achievementTab + tabs - 1) % tabs
+	case ActionRight:
+		m.achievementTab = (m.achievementTab + 1) % tabs
+	case ActionBack:
+		m.Navigate(ViewTower)
 	}
 	return m, nil
 }
 
 // handleMenuKeys handles keys in the menu view.
 func (m Model) handleMenuKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "b":
+	switch m.keys.Action(ViewMenu, msg.String()) {
+	case ActionBack:
 		m.Navigate(ViewTower)
-	case "s":
+	case ActionSave:
 		return m, m.saveGameCmd()
-	case "q":
-		return m, tea.Sequence(m.saveGameCmd(), tea.Quit)
 	}
 	return m, nil
 }
 
+// floorEventToastKey identifies the sticky floor event prompt.
+const floorEventToastKey = "floor_event"
+
 // handleTick processes a game tick.
 func (m Model) handleTick(msg TickMsg) (tea.Model, tea.Cmd) {
 	if m.gameState == nil || m.engine == nil {
 		return m, m.tickCmd()
 	}
 
-	// Calculate elapsed time
-	elapsed := msg.Timestamp.Sub(m.lastUpdate)
-	m.lastUpdate = msg.Timestamp
+	// Advance the game, unless the daemon's loop is already doing it
+	saveDue := false
+	if m.loop != nil && !m.loop.Running() {
+		saveDue = m.loop.Step(msg.Timestamp)
+	}
 
-	// Update game state
-	m.engine.Tick(m.gameState, elapsed)
+	// Follow the log file while the debug log is open
+	if m.currentView == ViewDebugLog && msg.Timestamp.Sub(m.logReadAt) >= debugLogRefresh {
+		m.refreshLog()
+	}
 
 	// Floor events: auto-open when available (but don't interrupt confirmations/specializations)
 	if m.gameState != nil && m.gameState.Session != nil {
 		if m.gameState.Session.ActiveFloorEvent != nil && m.currentView != ViewFloorEvent && !m.confirming && m.currentView != ViewSpecialize {
 			m.Navigate(ViewFloorEvent)
+			// Keep the prompt on top of routine toasts until it's answered
+			m.PushNotification(components.NewNotification(
+				fmt.Sprintf("Floor event on floor %d: choose a bonus!", m.gameState.Session.ActiveFloorEvent.Floor), components.NotifyWarning).
+				WithPriority(components.PriorityHigh).
+				WithKey(floorEventToastKey).
+				Sticky())
+		}
+		if m.gameState.Session.ActiveFloorEvent == nil {
+			m.DismissNotification(floorEventToastKey)
 		}
 		// If we're showing the event view and it expired/was cleared, return to the previous view.
 		if m.currentView == ViewFloorEvent && m.gameState.Session.ActiveFloorEvent == nil {
@@ -513,28 +745,69 @@ func (m Model) handleTick(msg TickMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Report automation, achievements and tutorial progress since last tick
+	m.announceEvents()
+	m.announceChallenge()
+	m.announceBoss()
+
+	// Keep the rotation dry-run current while it's on screen
+	if m.currentView == ViewRotation && time.Since(m.rotationPlanAt) > time.Second {
+		m.refreshRotationPlan()
+	}
+
 	// Check for aggregated skip notifications (every 2 seconds)
 	if time.Since(m.lastSkipCheckAt) > 2*time.Second {
 		skipCount := m.engine.GetAndResetSkipCount(m.gameState)
 		if skipCount > 0 {
-			m.ShowNotification(fmt.Sprintf("Auto-cast skipped %d times (low mana)", skipCount))
+			m.PushNotification(components.NewNotification("", components.NotifyWarning).
+				WithPriority(components.PriorityLow).
+				WithKey("autocast_skipped").
+				WithCount(skipCount, func(count int) string {
+					return fmt.Sprintf("Auto-cast skipped %d times (low mana)", count)
+				}))
 		}
 		m.lastSkipCheckAt = time.Now()
 	}
 
-	// Clear old notifications (after 3 seconds)
-	if m.notification != "" && time.Since(m.notificationTime) > 3*time.Second {
-		m.ClearNotification()
-	}
+	// Drop expired notifications
+	m.notifications.Prune()
 
-	// Auto-save every 30 seconds
-	if m.saveStore != nil && time.Since(m.gameState.Session.LastSavedAt) > 30*time.Second {
+	// Auto-save on the configured interval
+	if saveDue {
 		return m, tea.Batch(m.tickCmd(), m.saveGameCmd())
 	}
 
 	return m, m.tickCmd()
 }
 
+// announceEvents notifies the player of automation actions, achievements and
+// tutorial steps recorded since this session last looked. Sessions read the
+// shared event log with their own cursor, so each one announces them.
+func (m *Model) announceEvents() {
+	if m.eventLog == nil {
+		return
+	}
+	var entries []engine.EventLogEntry
+	entries, m.eventCursor = m.eventLog.Since(m.eventCursor)
+	for _, entry := range entries {
+		event := entry.Event
+		switch event.Type {
+		case game.EventAutomation:
+			m.PushNotification(components.NewNotification(event.Message, components.NotifyInfo).WithPriority(components.PriorityLow))
+		case game.EventAchievement:
+			text := event.Message
+			id, _ := event.Data["achievement_id"].(string)
+			if def := game.GetAchievementDefinition(id); def != nil && def.RewardManaGen > 0 {
+				text += fmt.Sprintf(" (+%.0f%% mana gen)", def.RewardManaGen*100)
+			}
+			m.PushNotification(components.NewNotification(text, components.NotifySuccess).WithPriority(components.PriorityHigh))
+		case game.EventTutorialStep:
+			step, _ := event.Data["step"].(string)
+			m.announceTutorialStep(models.TutorialStep(step))
+		}
+	}
+}
+
 // v1.5.0: Rotation View Key Handler
 func (m Model) handleRotationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.gameState == nil {
@@ -545,16 +818,103 @@ func (m Model) handleRotationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.gameState.EnsureRotation()
 	rotation := m.gameState.Session.Rotation
 
-	switch msg.String() {
-	case "up", "k":
+	if m.addingRotationSpell {
+		return m.handleRotationPickerKeys(msg)
+	}
+
+	// Selected rotation entry (empty if the list is empty)
+	selectedID := ""
+	if m.selectedIndex >= 0 && m.selectedIndex < len(rotation.Spells) {
+		selectedID = rotation.Spells[m.selectedIndex].SpellID
+	}
+
+	switch m.keys.Action(ViewRotation, msg.String()) {
+	case ActionUp:
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
+	case ActionDown:
 		if len(rotation.Spells) > 0 && m.selectedIndex < len(rotation.Spells)-1 {
 			m.selectedIndex++
 		}
-	case "o":
+	case ActionAdd:
+		// Add a spell to the rotation
+		if len(m.rotationCandidates()) == 0 {
+			m.ShowNotification("All unlocked spells are already in the rotation")
+		} else {
+			m.addingRotationSpell = true
+			m.rotationPickIdx = 0
+		}
+	case ActionDelete:
+		// Remove selected spell from the rotation
+		if selectedID != "" {
+			m.gameState.RemoveSpellFromRotation(selectedID)
+			if m.selectedIndex >= len(m.gameState.Session.Rotation.Spells) && m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+			if spell := m.gameState.GetSpellByID(selectedID); spell != nil {
+				m.ShowNotification(fmt.Sprintf("%s removed from rotation", spell.Name))
+			}
+		}
+	case ActionPriority:
+		// Cycle priority
+		if selectedID != "" {
+			priority := m.gameState.CycleRotationPriority(selectedID)
+			m.ShowNotification(fmt.Sprintf("Priority: %s", models.GetPriorityLabel(priority)))
+		}
+	case ActionCondition:
+		// Cycle built-in condition
+		if selectedID != "" {
+			cond := m.gameState.CycleRotationCondition(selectedID)
+			m.ShowNotification(fmt.Sprintf("Condition: %s", models.GetConditionDescription(cond)))
+		}
+	case ActionMoveUp:
+		// Move selected spell earlier in the rotation
+		if selectedID != "" && m.gameState.MoveRotationSpell(selectedID, -1) {
+			m.selectedIndex--
+		}
+	case ActionMoveDown:
+		// Move selected spell later in the rotation
+		if selectedID != "" && m.gameState.MoveRotationSpell(selectedID, 1) {
+			m.selectedIndex++
+		}
+	case ActionReserveDown:
+		threshold := m.gameState.AdjustRotationManaThreshold(-0.05)
+		m.ShowNotification(fmt.Sprintf("Mana reserve: %.0f%%", threshold*100))
+	case ActionReserveUp:
+		threshold := m.gameState.AdjustRotationManaThreshold(0.05)
+		m.ShowNotification(fmt.Sprintf("Mana reserve: %.0f%%", threshold*100))
+	case ActionOptimize, ActionOptimizeFloors:
+		// Optimize rotation in the background (z: sigil/s, Z: floors/hr)
+		if m.optimizing {
+			m.ShowNotification("Optimizer already running...")
+		} else if m.engine != nil {
+			objective := engine.ObjectiveSigilPerSecond
+			if m.keys.Action(ViewRotation, msg.String()) == ActionOptimizeFloors {
+				objective = engine.ObjectiveFloorsPerHour
+			}
+			m.optimizing = true
+			m.ShowNotification(fmt.Sprintf("Optimizing rotation for %s...", engine.OptimizeObjectiveLabels[objective]))
+			return m, m.optimizeRotationCmd(objective)
+		}
+	case ActionUndo:
+		// Undo the last optimization
+		if m.rotationBeforeOptimize != nil {
+			m.gameState.Session.Rotation = m.rotationBeforeOptimize
+			m.rotationBeforeOptimize = nil
+			m.optimizeResult = nil
+			m.selectedIndex = 0
+			m.ShowNotification("Restored rotation from before optimizing")
+		}
+	case ActionIdle:
+		// Toggle optimize for idle
+		rotation.OptimizeForIdle = !rotation.OptimizeForIdle
+		status := "disabled"
+		if rotation.OptimizeForIdle {
+			status = "enabled"
+		}
+		m.ShowNotification(fmt.Sprintf("Optimize for idle %s", status))
+	case ActionEnableRotation:
 		// Toggle rotation on/off
 		rotation.Enabled = !rotation.Enabled
 		status := "disabled"
@@ -562,12 +922,12 @@ func (m Model) handleRotationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			status = "enabled"
 		}
 		m.ShowNotification(fmt.Sprintf("Rotation system %s", status))
-	case "v":
+	case ActionConvert:
 		// Convert auto-cast to rotation
 		m.gameState.ConvertAutoCastToRotation()
 		m.selectedIndex = 0
 		m.ShowNotification("Converted auto-cast slots to rotation system")
-	case "w":
+	case ActionWeaving:
 		// Toggle cooldown weaving
 		rotation.CooldownWeaving = !rotation.CooldownWeaving
 		status := "disabled"
@@ -575,12 +935,11 @@ func (m Model) handleRotationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			status = "enabled"
 		}
 		m.ShowNotification(fmt.Sprintf("Cooldown weaving %s", status))
-	case " ":
+	case ActionToggle:
 		// Toggle selected spell enabled/disabled
-		if m.selectedIndex >= 0 && m.selectedIndex < len(rotation.Spells) {
-			spellID := rotation.Spells[m.selectedIndex].SpellID
-			m.gameState.ToggleRotationSpell(spellID)
-			
+		if selectedID != "" {
+			m.gameState.ToggleRotationSpell(selectedID)
+
 			// Re-read rotation after toggling to get updated state safely
 			rotation = m.gameState.Session.Rotation
 			if m.selectedIndex < len(rotation.Spells) {
@@ -591,25 +950,361 @@ func (m Model) handleRotationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.ShowNotification(fmt.Sprintf("Spell %s", status))
 			}
 		}
-	case "esc", "b":
+	case ActionEditExpression:
+		// Edit the selected spell's condition expression
+		if m.selectedIndex >= 0 && m.selectedIndex < len(rotation.Spells) {
+			m.editingExpr = true
+			m.exprInput = rotation.Spells[m.selectedIndex].Expression
+			m.exprError = nil
+			if m.exprInput != "" {
+				m.exprError = engine.ValidateConditionExpr(m.exprInput)
+			}
+		}
+	case ActionBack:
 		m.GoBack()
 	}
 
 	return m, nil
 }
 
-// saveGameCmd returns a command to save the game.
-func (m Model) saveGameCmd() tea.Cmd {
+// optimizeRotationCmd runs the rotation optimizer on a snapshot of the game
+// so the UI keeps ticking while it searches. The snapshot and the engine's
+// time are taken here, under the loop's lock; the search uses neither the
+// live game nor the live engine.
+func (m Model) optimizeRotationCmd(objective engine.OptimizeObjective) tea.Cmd {
+	snapshot, start := m.gameState.Clone(), m.engine.Now()
+	gs, base := m.gameState, m.rotationFingerprint()
 	return func() tea.Msg {
-		if m.saveStore == nil || m.gameState == nil {
-			return SaveCompleteMsg{Error: nil}
+		return RotationOptimizedMsg{Result: engine.OptimizeRotationAt(snapshot, start, objective), Game: gs, Base: base}
+	}
+}
+
+// handleRotationOptimized applies an optimizer result. If the rotation was
+// edited while the optimizer ran, the player confirms replacing it first.
+func (m Model) handleRotationOptimized(msg RotationOptimizedMsg) (tea.Model, tea.Cmd) {
+	m.optimizing = false
+	if m.gameState == nil || msg.Result == nil || msg.Result.Rotation == nil {
+		return m, nil
+	}
+	if m.gameState != msg.Game {
+		m.ShowNotification("Game changed while optimizing; result discarded")
+		return m, nil
+	}
+	if m.rotationFingerprint() != msg.Base {
+		if m.confirming {
+			m.ShowNotification("Rotation changed while optimizing; result discarded")
+			return m, nil
+		}
+		m.pendingOptimize = msg.Result
+		m.StartConfirmAction("Rotation changed while optimizing. Replace it with the optimized one? (y/n)", "apply_optimized")
+		return m, nil
+	}
+	m.applyOptimizeResult(msg.Result)
+	if m.currentView == ViewRotation {
+		m.rotationPlanBaseline = m.rotationPlan
+		m.refreshRotationPlan()
+	}
+	return m, nil
+}
+
+// applyOptimizeResult switches to an optimized rotation, keeping the
+// previous one for undo. The caller refreshes the rotation preview.
+func (m *Model) applyOptimizeResult(result *engine.OptimizeResult) {
+	m.gameState.EnsureRotation()
+	m.rotationBeforeOptimize = m.gameState.Session.Rotation
+	m.gameState.Session.Rotation = result.Rotation
+	m.optimizeResult = result
+	m.selectedIndex = 0
+	m.ShowNotification(fmt.Sprintf("Rotation optimized: %+.1f%% %s",
+		result.Improvement()*100, engine.OptimizeObjectiveLabels[result.Objective]))
+}
+
+// rotationFingerprint returns a snapshot of the rotation settings used to
+// detect edits (empty if there is no rotation).
+func (m Model) rotationFingerprint() string {
+	if m.gameState == nil || m.gameState.Session == nil || m.gameState.Session.Rotation == nil {
+		return ""
+	}
+	data, _ := json.Marshal(m.gameState.Session.Rotation)
+	return string(data)
+}
+
+// refreshRotationPlan re-runs the rotation dry-run for the preview.
+func (m *Model) refreshRotationPlan() {
+	if m.gameState == nil || m.engine == nil {
+		return
+	}
+	m.gameState.EnsureRotation()
+	m.rotationPlan = m.engine.PlanRotation(m.gameState, m.gameState.Session.Rotation, engine.DefaultPlanHorizon)
+	m.rotationPlanAt = time.Now()
+}
+
+// rotationCandidates returns unlocked spells that are not yet in the rotation.
+func (m Model) rotationCandidates() []*models.Spell {
+	candidates := []*models.Spell{}
+	for _, spell := range m.gameState.Spells {
+		if m.gameState.GetRotationConfig(spell.ID) == nil {
+			candidates = append(candidates, spell)
+		}
+	}
+	return candidates
+}
+
+// handleRotationPickerKeys handles the add-spell picker in the Rotation view.
+func (m Model) handleRotationPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	candidates := m.rotationCandidates()
+	if len(candidates) == 0 {
+		m.addingRotationSpell = false
+		return m, nil
+	}
+	if m.rotationPickIdx >= len(candidates) {
+		m.rotationPickIdx = len(candidates) - 1
+	}
+
+	switch m.keys.Action(keyScopeRotationPicker, msg.String()) {
+	case ActionUp:
+		if m.rotationPickIdx > 0 {
+			m.rotationPickIdx--
+		}
+	case ActionDown:
+		if m.rotationPickIdx < len(candidates)-1 {
+			m.rotationPickIdx++
+		}
+	case ActionSelect:
+		spell := candidates[m.rotationPickIdx]
+		m.gameState.AddSpellToRotation(spell.ID, models.PriorityMedium, models.RotationConditionAlways)
+		m.selectedIndex = len(m.gameState.Session.Rotation.Spells) - 1
+		m.addingRotationSpell = false
+		m.ShowNotification(fmt.Sprintf("%s added to rotation", spell.Name))
+	case ActionBack:
+		m.addingRotationSpell = false
+	}
+
+	return m, nil
+}
+
+// handleExprEditKey handles typing in the rotation condition expression editor.
+func (m Model) handleExprEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editingExpr = false
+		m.exprError = nil
+		return m, nil
+
+	case tea.KeyEnter:
+		rotation := m.gameState.Session.Rotation
+		if rotation == nil || m.selectedIndex < 0 || m.selectedIndex >= len(rotation.Spells) {
+			m.editingExpr = false
+			return m, nil
+		}
+		config := &rotation.Spells[m.selectedIndex]
+		expr := strings.TrimSpace(m.exprInput)
+		if expr == "" {
+			// Clearing the expression reverts to the default condition
+			config.Condition = models.RotationConditionAlways
+			config.Expression = ""
+			m.editingExpr = false
+			m.ShowNotification("Condition expression cleared")
+			return m, nil
+		}
+		if err := engine.ValidateConditionExpr(expr); err != nil {
+			m.exprError = err
+			return m, nil
+		}
+		config.Condition = models.RotationConditionExpression
+		config.Expression = expr
+		m.editingExpr = false
+		m.exprError = nil
+		m.ShowNotification("Condition expression saved")
+		return m, nil
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.exprInput); len(runes) > 0 {
+			m.exprInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		m.exprInput = ""
+	case tea.KeySpace:
+		m.exprInput += " "
+	case tea.KeyRunes:
+		m.exprInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	// Validate as the player types
+	m.exprError = nil
+	if strings.TrimSpace(m.exprInput) != "" {
+		m.exprError = engine.ValidateConditionExpr(m.exprInput)
+	}
+	return m, nil
+}
+
+// Automation view rows; saved presets are listed after these.
+const (
+	autoRowPrestige = iota
+	autoRowPrestigeRate
+	autoRowUpgrade
+	autoRowFloorEvent
+	autoRowPreset
+	autoRuleRows
+)
+
+// handleAutomationKeys handles keys in the automation view.
+func (m Model) handleAutomationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.gameState == nil {
+		m.GoBack()
+		return m, nil
+	}
+
+	auto := m.gameState.EnsureAutomation(m.now().UnixMilli())
+	presetIdx := m.selectedIndex - autoRuleRows
+	var selectedPreset *models.LoadoutPreset
+	if presetIdx >= 0 && presetIdx < len(m.gameState.Presets) {
+		selectedPreset = m.gameState.Presets[presetIdx]
+	}
+
+	switch m.keys.Action(ViewAutomation, msg.String()) {
+	case ActionUp:
+		if m.selectedIndex > 0 {
+			m.selectedIndex--
+		}
+	case ActionDown:
+		if m.selectedIndex < autoRuleRows+len(m.gameState.Presets)-1 {
+			m.selectedIndex++
+		}
+	case ActionLeft:
+		m.adjustAutomationRow(auto, -1)
+	case ActionRight:
+		m.adjustAutomationRow(auto, 1)
+	case ActionToggle:
+		if selectedPreset != nil {
+			m.gameState.ApplyLoadoutPreset(selectedPreset.Name)
+			m.ShowNotification("Loadout applied: " + selectedPreset.Name)
+			return m, nil
+		}
+		m.toggleAutomationRow(auto)
+	case ActionNewPreset:
+		// Save the current loadout as a new preset
+		name := m.gameState.NextPresetName()
+		if m.gameState.SaveLoadoutPreset(name) == nil {
+			m.ShowNotification(fmt.Sprintf("Preset limit reached (%d)", models.MaxLoadoutPresets))
+		} else {
+			m.selectedIndex = autoRuleRows + len(m.gameState.Presets) - 1
+			m.ShowNotification("Saved loadout as " + name)
+		}
+	case ActionOverwritePreset:
+		// Overwrite the selected preset with the current loadout
+		if selectedPreset != nil {
+			m.gameState.SaveLoadoutPreset(selectedPreset.Name)
+			m.ShowNotification("Updated " + selectedPreset.Name)
+		}
+	case ActionReapplyPreset:
+		// Re-apply the selected preset after prestige
+		if selectedPreset != nil {
+			auto.ReapplyPreset.Preset = selectedPreset.Name
+			auto.ReapplyPreset.Enabled = true
+			m.ShowNotification(selectedPreset.Name + " will be applied after prestige")
+		}
+	case ActionDelete:
+		if selectedPreset != nil {
+			m.gameState.DeleteLoadoutPreset(selectedPreset.Name)
+			if auto.ReapplyPreset.Preset == selectedPreset.Name {
+				auto.ReapplyPreset = models.ReapplyPresetRule{}
+			}
+			if m.selectedIndex >= autoRuleRows+len(m.gameState.Presets) {
+				m.selectedIndex--
+			}
+			m.ShowNotification("Deleted " + selectedPreset.Name)
 		}
+	case ActionBack:
+		m.Navigate(ViewTower)
+	}
+	return m, nil
+}
+
+// toggleAutomationRow switches the selected rule on or off.
+func (m *Model) toggleAutomationRow(auto *models.AutomationState) {
+	switch m.selectedIndex {
+	case autoRowPrestige, autoRowPrestigeRate:
+		auto.Prestige.Enabled = !auto.Prestige.Enabled
+		m.ShowNotification("Auto-prestige " + onOff(auto.Prestige.Enabled))
+	case autoRowUpgrade:
+		auto.Upgrade.Enabled = !auto.Upgrade.Enabled
+		m.ShowNotification("Auto-upgrade " + onOff(auto.Upgrade.Enabled))
+	case autoRowFloorEvent:
+		auto.FloorEvent.Enabled = !auto.FloorEvent.Enabled
+		m.ShowNotification("Auto floor event " + onOff(auto.FloorEvent.Enabled))
+	case autoRowPreset:
+		if m.gameState.GetLoadoutPreset(auto.ReapplyPreset.Preset) == nil {
+			if len(m.gameState.Presets) == 0 {
+				m.ShowNotification("Save a loadout preset first ([N])")
+				return
+			}
+			auto.ReapplyPreset.Preset = m.gameState.Presets[0].Name
+		}
+		auto.ReapplyPreset.Enabled = !auto.ReapplyPreset.Enabled
+		m.ShowNotification("Re-apply preset " + onOff(auto.ReapplyPreset.Enabled))
+	}
+}
+
+// adjustAutomationRow changes the selected rule's setting in direction.
+func (m *Model) adjustAutomationRow(auto *models.AutomationState, direction int) {
+	switch m.selectedIndex {
+	case autoRowPrestige:
+		auto.AdjustPrestigeFloor(10 * direction)
+	case autoRowPrestigeRate:
+		auto.AdjustPrestigeRate(10 * float64(direction))
+	case autoRowUpgrade:
+		auto.AdjustUpgradeThreshold(0.25 * float64(direction))
+	case autoRowFloorEvent:
+		auto.CycleFloorEventChoice(direction)
+	case autoRowPreset:
+		presets := m.gameState.Presets
+		if len(presets) == 0 {
+			return
+		}
+		idx := -1
+		for i, preset := range presets {
+			if preset.Name == auto.ReapplyPreset.Preset {
+				idx = i
+			}
+		}
+		idx = ((idx+direction)%len(presets) + len(presets)) % len(presets)
+		auto.ReapplyPreset.Preset = presets[idx].Name
+	}
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
 
+// savePlayerCmd returns a command that saves just the player profile (e.g.
+// after changing a setting kept on it).
+func (m Model) savePlayerCmd() tea.Cmd {
+	if m.playerStore == nil || m.player == nil {
+		return nil
+	}
+	player, store := m.player, m.playerStore
+	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		if err := store.Update(ctx, player); err != nil {
+			return ErrorMsg{Error: err}
+		}
+		return nil
+	}
+}
 
-		m.gameState.Session.LastSavedAt = time.Now()
-		err := m.saveStore.Save(ctx, m.gameState)
-		return SaveCompleteMsg{Error: err}
+// saveGameCmd returns a command to save the game.
+func (m Model) saveGameCmd() tea.Cmd {
+	return func() tea.Msg {
+		if m.loop == nil {
+			return SaveCompleteMsg{Error: nil}
+		}
+		return SaveCompleteMsg{Error: m.loop.Save(context.Background())}
 	}
 }
@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Ltorre/ManaTTY/engine"
 	"github.com/Ltorre/ManaTTY/game"
 	"github.com/Ltorre/ManaTTY/models"
 	"github.com/Ltorre/ManaTTY/utils"
@@ -23,8 +26,17 @@ func sortRitualEffects(effects []models.RitualEffect) []models.RitualEffect {
 	return sorted
 }
 
-// View renders the current view.
+// View renders the current view with the game state locked.
 func (m Model) View() string {
+	if m.loop != nil {
+		m.loop.Lock()
+		defer m.loop.Unlock()
+	}
+	return m.view()
+}
+
+// view renders the current view.
+func (m Model) view() string {
 	if !m.ready {
 		return "Initializing..."
 	}
@@ -34,6 +46,9 @@ func (m Model) View() string {
 	switch m.currentView {
 	case ViewTower:
 		content = m.viewTower()
+		if m.eventPanel && m.eventLog != nil {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, content, "  ", m.viewEventPanel())
+		}
 	case ViewSpells:
 		content = m.viewSpells()
 	case ViewRituals:
@@ -48,26 +63,54 @@ func (m Model) View() string {
 		content = m.viewSpecialize()
 	case ViewFloorEvent:
 		content = m.viewFloorEvent()
+	case ViewBoss:
+		content = m.viewBoss()
 	case ViewRotation:
 		content = m.viewRotation()
+	case ViewAutomation:
+		content = m.viewAutomation()
+	case ViewAchievements:
+		content = m.viewAchievements()
+	case ViewGraphs:
+		content = m.viewGraphs()
+	case ViewLeaderboard:
+		content = m.viewLeaderboard()
+	case ViewEventLog:
+		content = m.viewEventLog()
+	case ViewDebugLog:
+		content = m.viewDebugLog()
+	case ViewSettings:
+		content = m.viewSettings()
 	default:
 		content = m.viewTower()
 	}
 
-	// Add notification if present
-	if m.notification != "" {
-		notification := lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true).
-			Render("📢 " + m.notification)
-		content = lipgloss.JoinVertical(lipgloss.Top, content, "", notification)
+	// Help overlay replaces the view; otherwise show the tutorial's next step
+	if m.showHelp {
+		content = m.viewHelp()
+	} else if hint := m.viewTutorialHint(); hint != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, "", hint)
+	}
+
+	// Add stacked notifications, highest priority first
+	if m.notifications != nil {
+		if toasts := m.notifications.Visible(); len(toasts) > 0 {
+			var rendered []string
+			for _, n := range toasts {
+				rendered = append(rendered, n.Render(m.styles.Palette()))
+			}
+			if hidden := m.notifications.Len() - len(toasts); hidden > 0 {
+				rendered = append(rendered, m.styles.Dim.Render(fmt.Sprintf("  +%d more", hidden)))
+			}
+			content = lipgloss.JoinVertical(lipgloss.Left, content, "", lipgloss.JoinVertical(lipgloss.Left, rendered...))
+		}
 	}
 
 	// Add confirmation dialog if active
 	if m.confirming {
-		confirm := lipgloss.NewStyle().
+		confirm := m.styles.NewStyle().
 			Border(lipgloss.DoubleBorder()).
-			BorderForeground(ColorWarning).
+			BorderForeground(m.styles.ColorWarning).
 			Padding(1, 2).
 			Render(m.confirmText)
 		content = lipgloss.JoinVertical(lipgloss.Top, content, "", confirm)
@@ -82,7 +125,7 @@ func (m Model) viewFloorEvent() string {
 	}
 
 	evt := m.gameState.Session.ActiveFloorEvent
-	nowMs := time.Now().UnixMilli()
+	nowMs := m.now().UnixMilli()
 	remainingMs := evt.ExpiresAtMs - nowMs
 	if remainingMs < 0 {
 		remainingMs = 0
@@ -91,15 +134,15 @@ func (m Model) viewFloorEvent() string {
 	mins := int(remaining.Minutes())
 	secs := int(remaining.Seconds()) % 60
 
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	var lines []string
-	header := HeaderStyle.Width(60).Render(
-		TitleStyle.Render(sym.Event + " FLOOR EVENT"),
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(sym.Event + " FLOOR EVENT"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
-	lines = append(lines, SubtitleStyle.Render(fmt.Sprintf("A choice appears on Floor %d", evt.Floor)))
-	lines = append(lines, DimStyle.Render(fmt.Sprintf("Expires in %dm%02ds (auto-dismisses with no bonus)", mins, secs)))
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("A choice appears on Floor %d", evt.Floor)))
+	lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("Expires in %dm%02ds (auto-dismisses with no bonus)", mins, secs)))
 	lines = append(lines, "")
 
 	opts := []struct {
@@ -111,20 +154,20 @@ func (m Model) viewFloorEvent() string {
 		{models.FloorEventChoiceCooldownReduction, fmt.Sprintf("-%.0f%% spell cooldown for %d floors", game.FloorEventCooldownReduction*100, game.FloorEventBuffDurationFloors)},
 	}
 
-	lines = append(lines, SubtitleStyle.Render("Choose one:"))
+	lines = append(lines, m.styles.Subtitle.Render("Choose one:"))
 	for i, opt := range opts {
 		prefix := "  "
-		style := TextStyle
+		style := m.styles.Text
 		if i == m.selectedIndex {
 			prefix = "> "
-			style = HighlightStyle
+			style = m.styles.Highlight
 		}
 		name := models.FloorEventChoiceDisplayNames[opt.Choice]
 		lines = append(lines, style.Render(fmt.Sprintf("%s[%d] %s — %s", prefix, i+1, name, opt.Text)))
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, DimStyle.Render("[1/2/3 or Enter] Select  |  [Esc] Ignore"))
+	lines = append(lines, m.styles.Dim.Render("[1/2/3] Pick  "+m.keys.Help(ViewFloorEvent)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -139,13 +182,13 @@ func (m Model) viewTower() string {
 	var lines []string
 
 	// Header
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	era := ""
 	if gs.PrestigeData.CurrentEra > 0 {
 		era = fmt.Sprintf(" - ERA %d", gs.PrestigeData.CurrentEra)
 	}
-	header := HeaderStyle.Width(60).Render(
-		TitleStyle.Render(sym.Tower + " MAGE TOWER ASCENSION" + era),
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(sym.Tower + " MAGE TOWER ASCENSION" + era),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
@@ -155,15 +198,21 @@ func (m Model) viewTower() string {
 	if gs.Tower.MaxFloorReached > gs.Tower.CurrentFloor {
 		floorStr += fmt.Sprintf(" (Max: %d)", gs.Tower.MaxFloorReached)
 	}
-	lines = append(lines, SubtitleStyle.Render(floorStr))
+	lines = append(lines, m.styles.Subtitle.Render(floorStr))
+	if gs.Challenge != nil {
+		lines = append(lines, m.renderChallengeStatus()...)
+	}
+	if status := m.renderBossStatus(); status != "" {
+		lines = append(lines, status)
+	}
 	lines = append(lines, "")
 
 	// Mana progress bar
 	progress := gs.Tower.GetFloorProgress()
 	barWidth := 40
 	filled := int(progress * float64(barWidth))
-	bar := ProgressBarFilled.Render(strings.Repeat("█", filled)) +
-		ProgressBarEmpty.Render(strings.Repeat("░", barWidth-filled))
+	bar := m.styles.ProgressBarFilled.Render(strings.Repeat("█", filled)) +
+		m.styles.ProgressBarEmpty.Render(strings.Repeat("░", barWidth-filled))
 
 	percentage := int(progress * 100)
 	manaStr := fmt.Sprintf(sym.Mana+" Mana   [%s] %d%% (%s / %s)",
@@ -172,18 +221,22 @@ func (m Model) viewTower() string {
 		utils.FormatNumber(gs.Tower.CurrentMana),
 		utils.FormatNumber(gs.Tower.MaxMana),
 	)
+	recent := m.recentSamples(towerSparklineWidth)
+	if len(recent) > 1 {
+		manaStr += "  " + m.styles.Highlight.Render(m.styles.Palette().Sparkline(sampleSeries(recent, sampleManaPerSec), towerSparklineWidth))
+	}
 	lines = append(lines, manaStr)
 
 	// Ascension Sigil progress bar
 	sigilProgress := gs.Tower.GetSigilProgress()
 	sigilFilled := int(sigilProgress * float64(barWidth))
-	sigilBar := ProgressBarFilled.Render(strings.Repeat("█", sigilFilled)) +
-		ProgressBarEmpty.Render(strings.Repeat("░", barWidth-sigilFilled))
+	sigilBar := m.styles.ProgressBarFilled.Render(strings.Repeat("█", sigilFilled)) +
+		m.styles.ProgressBarEmpty.Render(strings.Repeat("░", barWidth-sigilFilled))
 	sigilPercent := int(sigilProgress * 100)
 
 	sigilStatus := ""
 	if gs.Tower.IsSigilCharged() {
-		sigilStatus = SuccessStyle.Render(" ✓ READY")
+		sigilStatus = m.styles.Success.Render(" ✓ READY")
 	}
 	sigilStr := fmt.Sprintf("⚔️ Sigil  [%s] %d%% (%.0f / %.0f)%s",
 		sigilBar,
@@ -192,19 +245,22 @@ func (m Model) viewTower() string {
 		gs.Tower.SigilRequired,
 		sigilStatus,
 	)
+	if len(recent) > 1 {
+		sigilStr += "  " + m.styles.Highlight.Render(m.styles.Palette().Sparkline(sampleSeries(recent, sampleSigilDPS), towerSparklineWidth))
+	}
 	lines = append(lines, sigilStr)
 	lines = append(lines, "")
 
 	// Stats section
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-	lines = append(lines, SubtitleStyle.Render(sym.Stats+" STATS"))
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	lines = append(lines, m.styles.Subtitle.Render(sym.Stats+" STATS"))
 
 	if m.engine != nil {
 		manaPerSec := m.engine.CalculateManaPerSecond(gs)
 		multiplier := m.engine.GetTotalMultiplier(gs)
 
 		lines = append(lines, fmt.Sprintf("  Mana/sec:    %s (%sx multiplier)",
-			HighlightStyle.Render(utils.FormatNumber(manaPerSec)),
+			m.styles.Highlight.Render(utils.FormatNumber(manaPerSec)),
 			utils.FormatMultiplier(multiplier),
 		))
 	}
@@ -226,34 +282,34 @@ func (m Model) viewTower() string {
 	lines = append(lines, fmt.Sprintf("  Auto-cast: %s", autoCastStatus))
 
 	// Element synergy status
-	if gs.HasActiveSynergy() {
-		element := gs.GetActiveSynergy()
-		remaining := gs.GetSynergyTimeRemaining() / 1000 // convert to seconds
+	if nowMs := m.now().UnixMilli(); gs.HasActiveSynergyAt(nowMs) {
+		element := gs.GetActiveSynergyAt(nowMs)
+		remaining := gs.GetSynergyTimeRemainingAt(nowMs) / 1000 // convert to seconds
 		synergyStr := fmt.Sprintf("  %s Synergy: %ds remaining (20%% bonus)",
-			string(element), remaining)
-		lines = append(lines, HighlightStyle.Render(synergyStr))
+			m.styles.GetElementLabel(string(element)), remaining)
+		lines = append(lines, m.styles.Highlight.Render(synergyStr))
 	} else if len(gs.Session.LastCastElements) > 0 {
 		// Show element streak progress
 		streakLen := len(gs.Session.LastCastElements)
 		lastElement := gs.Session.LastCastElements[streakLen-1]
-		lines = append(lines, DimStyle.Render(fmt.Sprintf("  Element streak: %s ×%d/3",
-			string(lastElement), streakLen)))
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  Element streak: %s ×%d/3",
+			m.styles.GetElementLabel(string(lastElement)), streakLen)))
 	}
 	lines = append(lines, "")
 
 	// Active rituals
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	activeRituals := gs.GetActiveRituals()
-	lines = append(lines, SubtitleStyle.Render(fmt.Sprintf(sym.Ritual+" ACTIVE RITUALS (%d/%d)",
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf(sym.Ritual+" ACTIVE RITUALS (%d/%d)",
 		len(activeRituals), gs.PrestigeData.RitualCapacity)))
 
 	if len(activeRituals) == 0 {
-		lines = append(lines, DimStyle.Render("  No active rituals"))
+		lines = append(lines, m.styles.Dim.Render("  No active rituals"))
 	} else {
 		for i, ritual := range activeRituals {
-			cooldownStr := SuccessStyle.Render("Ready")
+			cooldownStr := m.styles.Success.Render("Ready")
 			if ritual.CooldownRemaining > 0 {
-				cooldownStr = WarningStyle.Render(utils.FormatCooldown(ritual.CooldownRemaining))
+				cooldownStr = m.styles.Warning.Render(utils.FormatCooldown(ritual.CooldownRemaining))
 			}
 
 			// Compute effects dynamically for legacy rituals or use stored ones
@@ -274,15 +330,15 @@ func (m Model) viewTower() string {
 					effectStr := game.GetEffectDisplayString(effect)
 					effectStrs = append(effectStrs, icon+" "+effectStr)
 				}
-				lines = append(lines, HighlightStyle.Render("      "+strings.Join(effectStrs, "  |  ")))
+				lines = append(lines, m.styles.Highlight.Render("      "+strings.Join(effectStrs, "  |  ")))
 			}
 		}
 	}
 	lines = append(lines, "")
 
 	// Footer
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-	footer := FooterStyle.Render("[S] Spells  [R] Rituals  [O] Rotation  [T] Stats  [P] Prestige  [A] Auto-cast  [Q] Quit")
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	footer := m.styles.Footer.Render(m.keys.Help(ViewTower, keyScopeGlobal))
 	lines = append(lines, footer)
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
@@ -290,19 +346,19 @@ func (m Model) viewTower() string {
 
 // viewMenu renders the menu view.
 func (m Model) viewMenu() string {
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	var lines []string
 
-	header := HeaderStyle.Width(50).Render(
-		TitleStyle.Render(sym.Bullet + " MENU"),
+	header := m.styles.Header.Width(50).Render(
+		m.styles.Title.Render(sym.Bullet + " MENU"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
 
-	lines = append(lines, TextStyle.Render("  [S] Save Game"))
-	lines = append(lines, TextStyle.Render("  [Q] Save & Quit"))
+	lines = append(lines, m.styles.Text.Render("  "+m.keys.Label(ViewMenu, ActionSave)+" Save Game"))
+	lines = append(lines, m.styles.Text.Render("  "+m.keys.Label(keyScopeGlobal, ActionQuit)+" Save & Quit"))
 	lines = append(lines, "")
-	lines = append(lines, FooterStyle.Render("[B/Esc] Back"))
+	lines = append(lines, m.styles.Footer.Render(m.keys.Label(ViewMenu, ActionBack)+" Back"))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -313,11 +369,11 @@ func (m Model) viewSpells() string {
 		return "No game loaded"
 	}
 
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	var lines []string
 
-	header := HeaderStyle.Width(70).Render(
-		TitleStyle.Render(sym.Bullet + " SPELLS"),
+	header := m.styles.Header.Width(70).Render(
+		m.styles.Title.Render(sym.Bullet + " SPELLS"),
 	)
 	lines = append(lines, header)
 
@@ -325,15 +381,15 @@ func (m Model) viewSpells() string {
 	manaStr := fmt.Sprintf(sym.Mana+" Mana: %s / %s",
 		utils.FormatNumber(m.gameState.Tower.CurrentMana),
 		utils.FormatNumber(m.gameState.Tower.MaxMana))
-	lines = append(lines, HighlightStyle.Render(manaStr))
+	lines = append(lines, m.styles.Highlight.Render(manaStr))
 	lines = append(lines, "")
 
 	// Element Synergy Status
-	if m.gameState.HasActiveSynergy() {
-		synergy := m.gameState.GetActiveSynergy()
-		remaining := m.gameState.GetSynergyTimeRemaining() / 1000
-		icon := GetElementIcon(string(synergy))
-		lines = append(lines, SuccessStyle.Render(fmt.Sprintf("%s %s SYNERGY ACTIVE! +20%% bonus (%ds remaining)", sym.Synergy, icon, remaining)))
+	if nowMs := m.now().UnixMilli(); m.gameState.HasActiveSynergyAt(nowMs) {
+		synergy := m.gameState.GetActiveSynergyAt(nowMs)
+		remaining := m.gameState.GetSynergyTimeRemainingAt(nowMs) / 1000
+		icon := m.styles.GetElementIcon(string(synergy))
+		lines = append(lines, m.styles.Success.Render(fmt.Sprintf("%s %s %s SYNERGY ACTIVE! +20%% bonus (%ds remaining)", sym.Synergy, icon, strings.ToUpper(m.styles.GetElementLabel(string(synergy))), remaining)))
 		lines = append(lines, "")
 	}
 
@@ -345,7 +401,7 @@ func (m Model) viewSpells() string {
 	if m.gameState.Session.AutoCastEnabled {
 		autoCastStatus = "ON"
 	}
-	lines = append(lines, SubtitleStyle.Render(fmt.Sprintf(sym.AutoCast+" Auto-Cast Loadout [%s] (%d/%d slots)", autoCastStatus, usedSlots, maxSlots)))
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf(sym.AutoCast+" Auto-Cast Loadout [%s] (%d/%d slots)", autoCastStatus, usedSlots, maxSlots)))
 
 	idxBySpellID := map[string]int{}
 	for i, id := range slotSpellIDs {
@@ -353,15 +409,15 @@ func (m Model) viewSpells() string {
 	}
 
 	if len(slotSpellIDs) == 0 {
-		lines = append(lines, DimStyle.Render("  (empty - press Space on a spell to add)"))
+		lines = append(lines, m.styles.Dim.Render("  (empty - press Space on a spell to add)"))
 	} else {
 		for i, spellID := range slotSpellIDs {
 			spell := m.gameState.GetSpellByID(spellID)
 			if spell != nil {
-				icon := GetElementIcon(string(spell.Element))
+				icon := m.styles.GetElementIcon(string(spell.Element))
 				cond := m.gameState.GetAutoCastCondition(spellID)
 				condStr := models.ConditionShortNames[cond]
-				lines = append(lines, TextStyle.Render(fmt.Sprintf("  %d. %s %s (Lv%d) [%s]", i+1, icon, spell.Name, spell.Level, condStr)))
+				lines = append(lines, m.styles.Text.Render(fmt.Sprintf("  %d. %s %s (Lv%d) [%s]", i+1, icon, spell.Name, spell.Level, condStr)))
 			}
 		}
 	}
@@ -370,28 +426,28 @@ func (m Model) viewSpells() string {
 	counts := m.gameState.GetAutoCastElementCounts()
 	resLines := []string{}
 	if counts[models.ElementFire] >= game.ElementalResonanceMinSpells {
-		resLines = append(resLines, fmt.Sprintf("%s Fire x%d (+%.0f%% dmg)", GetElementIcon(string(models.ElementFire)), counts[models.ElementFire], game.ResonanceFireDamageBonus*100))
+		resLines = append(resLines, fmt.Sprintf("%s %s x%d (+%.0f%% dmg)", m.styles.GetElementIcon(string(models.ElementFire)), m.styles.GetElementLabel(string(models.ElementFire)), counts[models.ElementFire], game.ResonanceFireDamageBonus*100))
 	}
 	if counts[models.ElementIce] >= game.ElementalResonanceMinSpells {
-		resLines = append(resLines, fmt.Sprintf("%s Ice x%d (-%.0f%% CD)", GetElementIcon(string(models.ElementIce)), counts[models.ElementIce], game.ResonanceIceCooldownReduction*100))
+		resLines = append(resLines, fmt.Sprintf("%s %s x%d (-%.0f%% CD)", m.styles.GetElementIcon(string(models.ElementIce)), m.styles.GetElementLabel(string(models.ElementIce)), counts[models.ElementIce], game.ResonanceIceCooldownReduction*100))
 	}
 	if counts[models.ElementThunder] >= game.ElementalResonanceMinSpells {
-		resLines = append(resLines, fmt.Sprintf("%s Thunder x%d (-%.0f%% cost)", GetElementIcon(string(models.ElementThunder)), counts[models.ElementThunder], game.ResonanceThunderManaCostReduction*100))
+		resLines = append(resLines, fmt.Sprintf("%s %s x%d (-%.0f%% cost)", m.styles.GetElementIcon(string(models.ElementThunder)), m.styles.GetElementLabel(string(models.ElementThunder)), counts[models.ElementThunder], game.ResonanceThunderManaCostReduction*100))
 	}
 	if counts[models.ElementArcane] >= game.ElementalResonanceMinSpells {
-		resLines = append(resLines, fmt.Sprintf("%s Arcane x%d (+%.0f%% sigil)", GetElementIcon(string(models.ElementArcane)), counts[models.ElementArcane], game.ResonanceArcaneSigilChargeBonus*100))
+		resLines = append(resLines, fmt.Sprintf("%s %s x%d (+%.0f%% sigil)", m.styles.GetElementIcon(string(models.ElementArcane)), m.styles.GetElementLabel(string(models.ElementArcane)), counts[models.ElementArcane], game.ResonanceArcaneSigilChargeBonus*100))
 	}
 	if len(resLines) > 0 {
-		lines = append(lines, DimStyle.Render("Resonance: "+strings.Join(resLines, "  |  ")))
+		lines = append(lines, m.styles.Dim.Render("Resonance: "+strings.Join(resLines, "  |  ")))
 	}
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	lines = append(lines, "")
 
 	// Spell list
-	lines = append(lines, SubtitleStyle.Render("All Spells"))
+	lines = append(lines, m.styles.Subtitle.Render("All Spells"))
 	for i, spell := range m.gameState.Spells {
 		selected := i == m.selectedIndex
-		icon := GetElementIcon(string(spell.Element))
+		icon := m.styles.GetElementIcon(string(spell.Element))
 
 		prefix := "  "
 		if selected {
@@ -405,9 +461,9 @@ func (m Model) viewSpells() string {
 		}
 
 		// Status
-		status := SuccessStyle.Render("Ready")
+		status := m.styles.Success.Render("Ready")
 		if !spell.IsReady() {
-			status = WarningStyle.Render(utils.FormatCooldown(spell.CooldownRemainingMs))
+			status = m.styles.Warning.Render(utils.FormatCooldown(spell.CooldownRemainingMs))
 		}
 
 		// Level indicator with max check
@@ -420,11 +476,11 @@ func (m Model) viewSpells() string {
 			prefix, autoIndicator, icon, spell.Name, levelStr, status)
 
 		if selected {
-			lines = append(lines, SelectedStyle.Render(line))
+			lines = append(lines, m.styles.Selected.Render(line))
 		} else if m.gameState.IsSpellInAutoCast(spell.ID) {
-			lines = append(lines, HighlightStyle.Render(line))
+			lines = append(lines, m.styles.Highlight.Render(line))
 		} else {
-			lines = append(lines, TextStyle.Render(line))
+			lines = append(lines, m.styles.Text.Render(line))
 		}
 
 		// Show details for selected spell
@@ -453,7 +509,7 @@ func (m Model) viewSpells() string {
 				}
 			}
 
-			details := DimStyle.Render(fmt.Sprintf(
+			details := m.styles.Dim.Render(fmt.Sprintf(
 				"      %s | DMG: %.0f | CD: %s | Cost: %.0f | %s%s",
 				spell.Element,
 				stats.Damage,
@@ -469,7 +525,7 @@ func (m Model) viewSpells() string {
 	lines = append(lines, "")
 
 	// Footer with contextual help
-	lines = append(lines, FooterStyle.Render("[↑↓] Select  [Enter] Cast  [U] Upgrade  [Space] Slot  [<>] Order  [C] Cond  [X] Spec  [A] Auto  [B] Back"))
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewSpells)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -482,20 +538,20 @@ func (m Model) viewRituals() string {
 
 	var lines []string
 
-	header := HeaderStyle.Width(70).Render(
-		TitleStyle.Render("⚡ RITUALS"),
+	header := m.styles.Header.Width(70).Render(
+		m.styles.Title.Render("⚡ RITUALS"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
 
 	// Active rituals with v1.2.0 effects
-	lines = append(lines, SubtitleStyle.Render(fmt.Sprintf("Active Rituals (%d/%d)",
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("Active Rituals (%d/%d)",
 		len(m.gameState.GetActiveRituals()), m.gameState.PrestigeData.RitualCapacity)))
 
 	for _, ritual := range m.gameState.Rituals {
-		status := SuccessStyle.Render("Active")
+		status := m.styles.Success.Render("Active")
 		if !ritual.IsActive {
-			status = DimStyle.Render("Inactive")
+			status = m.styles.Dim.Render("Inactive")
 		}
 
 		// Build ritual display with name and signature
@@ -518,19 +574,19 @@ func (m Model) viewRituals() string {
 			if ritual.HasSpellEcho {
 				effectLine += " (Echo ✨)"
 			}
-			lines = append(lines, HighlightStyle.Render(effectLine))
+			lines = append(lines, m.styles.Highlight.Render(effectLine))
 		}
 	}
 
 	if len(m.gameState.Rituals) == 0 {
-		lines = append(lines, DimStyle.Render("  No rituals created"))
+		lines = append(lines, m.styles.Dim.Render("  No rituals created"))
 	}
 	lines = append(lines, "")
 
 	// Total ritual bonuses summary (v1.2.0, v1.4.0 with synergies)
 	if len(m.gameState.GetActiveRituals()) > 0 && m.engine != nil {
-		lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-		lines = append(lines, SubtitleStyle.Render("Combined Bonuses"))
+		lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+		lines = append(lines, m.styles.Subtitle.Render("Combined Bonuses"))
 
 		dmgBonus := m.engine.GetTotalRitualDamageBonusWithSynergies(m.gameState)
 		cdBonus := m.engine.GetTotalRitualCooldownReductionWithSynergies(m.gameState)
@@ -559,19 +615,19 @@ func (m Model) viewRituals() string {
 		activeSynergies := m.engine.GetActiveSynergies(m.gameState)
 		if len(activeSynergies) > 0 {
 			lines = append(lines, "")
-			lines = append(lines, SubtitleStyle.Render("✨ Active Synergies"))
+			lines = append(lines, m.styles.Subtitle.Render("✨ Active Synergies"))
 			for _, synergy := range activeSynergies {
 				// Build element icons string
 				elementIcons := []string{}
 				for _, element := range synergy.Elements {
-					elementIcons = append(elementIcons, GetElementIcon(string(element)))
+					elementIcons = append(elementIcons, m.styles.GetElementIcon(string(element)))
 				}
 				synergyLine := fmt.Sprintf("  %s %s: %s (+%.0f%% to effects)",
 					strings.Join(elementIcons, " + "),
 					synergy.Name,
 					synergy.Description,
 					synergy.Magnitude*100)
-				lines = append(lines, HighlightStyle.Render(synergyLine))
+				lines = append(lines, m.styles.Highlight.Render(synergyLine))
 			}
 		}
 
@@ -579,15 +635,15 @@ func (m Model) viewRituals() string {
 	}
 
 	// Ritual builder
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-	lines = append(lines, SubtitleStyle.Render("Create New Ritual (select 3 spells)"))
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	lines = append(lines, m.styles.Subtitle.Render("Create New Ritual (select 3 spells)"))
 
 	// Show selected spells with preview
 	lines = append(lines, fmt.Sprintf("Selected: %d/3", len(m.ritualSpells)))
 	for _, spellID := range m.ritualSpells {
 		spell := m.gameState.GetSpellByID(spellID)
 		if spell != nil {
-			icon := GetElementIcon(string(spell.Element))
+			icon := m.styles.GetElementIcon(string(spell.Element))
 			lines = append(lines, fmt.Sprintf("  ✓ %s %s", icon, spell.Name))
 		}
 	}
@@ -600,7 +656,7 @@ func (m Model) viewRituals() string {
 		if comboInfo.SignatureName != "" {
 			previewName = fmt.Sprintf("%s \"%s\"", comboInfo.Name, comboInfo.SignatureName)
 		}
-		lines = append(lines, HighlightStyle.Render("  Preview: "+previewName))
+		lines = append(lines, m.styles.Highlight.Render("  Preview: "+previewName))
 
 		effectStrs := []string{}
 		sortedEffects := sortRitualEffects(comboInfo.Effects)
@@ -610,13 +666,13 @@ func (m Model) viewRituals() string {
 			effectStrs = append(effectStrs, icon+" "+effectStr)
 		}
 		if len(effectStrs) > 0 {
-			lines = append(lines, SuccessStyle.Render("  Effects: "+strings.Join(effectStrs, "  |  ")))
+			lines = append(lines, m.styles.Success.Render("  Effects: "+strings.Join(effectStrs, "  |  ")))
 		}
 	}
 	lines = append(lines, "")
 
 	// Available spells
-	lines = append(lines, DimStyle.Render("Available spells:"))
+	lines = append(lines, m.styles.Dim.Render("Available spells:"))
 	for i, spell := range m.gameState.Spells {
 		selected := i == m.selectedIndex
 
@@ -637,25 +693,307 @@ func (m Model) viewRituals() string {
 			prefix = "  ✓ "
 		}
 
-		icon := GetElementIcon(string(spell.Element))
+		icon := m.styles.GetElementIcon(string(spell.Element))
 		line := fmt.Sprintf("%s%s %s", prefix, icon, spell.Name)
 
 		if selected {
-			lines = append(lines, SelectedStyle.Render(line))
+			lines = append(lines, m.styles.Selected.Render(line))
 		} else if inSelection {
-			lines = append(lines, SuccessStyle.Render(line))
+			lines = append(lines, m.styles.Success.Render(line))
 		} else {
-			lines = append(lines, TextStyle.Render(line))
+			lines = append(lines, m.styles.Text.Render(line))
 		}
 	}
 	lines = append(lines, "")
 
 	// Footer
-	lines = append(lines, FooterStyle.Render("[↑↓] Navigate  [Enter] Select  [C] Clear  [X] Reset Rituals  [B/Esc] Back"))
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewRituals)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// eventLogPageSize is how many entries the event log view shows at once.
+const eventLogPageSize = 20
+
+// eventPanelSize is how many entries the tower side panel shows.
+const eventPanelSize = 16
+
+// viewEventLog renders the scrollable, filterable history of engine events.
+func (m Model) viewEventLog() string {
+	var lines []string
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(m.styles.Symbols().Event + " EVENT LOG"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	var tabs []string
+	for i, filter := range engine.EventFilters {
+		label := fmt.Sprintf(" %d %s ", i+1, engine.EventFilterNames[filter])
+		if filter == m.eventFilter {
+			tabs = append(tabs, m.styles.Selected.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	lines = append(lines, "")
+
+	var entries []engine.EventLogEntry
+	if m.eventLog != nil {
+		entries = m.eventLog.Entries(m.eventFilter)
+	}
+	if len(entries) == 0 {
+		lines = append(lines, m.styles.Dim.Render("  No events yet"))
+	} else {
+		end := min(m.eventScroll+eventLogPageSize, len(entries))
+		for _, entry := range entries[m.eventScroll:end] {
+			lines = append(lines, "  "+m.renderEventEntry(entry, 0))
+		}
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  %d-%d of %d events (newest first, last %d kept)",
+			m.eventScroll+1, end, len(entries), game.EventLogSize)))
+	}
+
+	lines = append(lines, m.styles.Footer.Render("[1-6] Filter  "+m.keys.Help(ViewEventLog)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// viewEventPanel renders the most recent events for the tower side panel,
+// using the event log's current filter.
+func (m Model) viewEventPanel() string {
+	var lines []string
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("%s EVENTS (%s)", m.styles.Symbols().Event, engine.EventFilterNames[m.eventFilter])))
+
+	entries := m.eventLog.Entries(m.eventFilter)
+	if len(entries) == 0 {
+		lines = append(lines, m.styles.Dim.Render("No events yet"))
+	}
+	for _, entry := range entries[:min(len(entries), eventPanelSize)] {
+		lines = append(lines, m.renderEventEntry(entry, 36))
+	}
+
+	return m.styles.Section.Width(46).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderEventEntry renders one log line: time and description, trimmed to
+// width characters of description when width > 0.
+func (m Model) renderEventEntry(entry engine.EventLogEntry, width int) string {
+	text, style := m.describeEvent(entry.Event)
+	if width > 0 && len([]rune(text)) > width {
+		text = string([]rune(text)[:width-1]) + "…"
+	}
+	return m.styles.Dim.Render(entry.At.Format("15:04:05")) + " " + style.Render(text)
+}
+
+// describeEvent returns a readable description of an engine event and the
+// style to show it in.
+func (m Model) describeEvent(event game.EventData) (string, lipgloss.Style) {
+	data := event.Data
+	switch event.Type {
+	case game.EventSpellCast:
+		spellID, _ := data["spell_id"].(string)
+		damage, _ := data["damage"].(float64)
+		element, _ := data["element"].(string)
+		text := fmt.Sprintf("%s %s: %s dmg", m.styles.GetElementIcon(element), m.spellName(spellID), utils.FormatNumber(damage))
+		if crit, _ := data["crit"].(bool); crit {
+			return text + " CRIT!", m.styles.Highlight
+		}
+		return text, m.styles.Text
+	case game.EventFloorClimbed:
+		floor, _ := data["floor"].(int)
+		return fmt.Sprintf("%s Climbed to floor %d", m.styles.Symbols().Floor, floor), m.styles.Success
+	case game.EventPrestige:
+		era, _ := data["era"].(int)
+		return fmt.Sprintf("%s Ascended to Era %d", m.styles.Symbols().Prestige, era), m.styles.Highlight
+	case game.EventSpellUnlocked:
+		name, _ := data["spell_name"].(string)
+		return "New spell: " + name, m.styles.Success
+	case game.EventLevelUp:
+		spellID, _ := data["spell_id"].(string)
+		level, _ := data["level"].(int)
+		return fmt.Sprintf("%s upgraded to Lv.%d", m.spellName(spellID), level), m.styles.Text
+	case game.EventRitualCreated, game.EventRitualActivated:
+		ritualID, _ := data["ritual_id"].(string)
+		name := m.ritualName(ritualID)
+		if event.Type == game.EventRitualCreated {
+			return m.styles.Symbols().Ritual + " Ritual created: " + name, m.styles.Success
+		}
+		if active, _ := data["active"].(bool); active {
+			return m.styles.Symbols().Ritual + " Ritual activated: " + name, m.styles.Text
+		}
+		return m.styles.Symbols().Ritual + " Ritual deactivated: " + name, m.styles.Dim
+	case game.EventSynergyActivated:
+		element, _ := data["element"].(string)
+		return fmt.Sprintf("%s %s synergy!", m.styles.Symbols().Synergy, m.styles.GetElementLabel(element)), m.styles.GetElementStyle(element)
+	case game.EventAchievement:
+		name, _ := data["name"].(string)
+		return m.styles.Symbols().Star + " Achievement: " + name, m.styles.Highlight
+	case game.EventAutoCastSlotted:
+		spellID, _ := data["spell_id"].(string)
+		return m.spellName(spellID) + " added to auto-cast", m.styles.Text
+	case game.EventFloorEventChosen:
+		floor, _ := data["floor"].(int)
+		choice, _ := data["choice"].(string)
+		return fmt.Sprintf("Floor %d event: %s", floor, models.FloorEventChoiceDisplayNames[models.FloorEventChoice(choice)]), m.styles.Success
+	case game.EventFloorEventStarted:
+		floor, _ := data["floor"].(int)
+		return fmt.Sprintf("Floor %d event appeared", floor), m.styles.Warning
+	case game.EventBossStarted:
+		bossID, _ := data["boss_id"].(string)
+		floor, _ := data["floor"].(int)
+		return fmt.Sprintf("%s %s appeared on floor %d", m.styles.Symbols().Boss, bossName(bossID), floor), m.styles.Warning
+	case game.EventBossEnraged:
+		bossID, _ := data["boss_id"].(string)
+		return fmt.Sprintf("%s %s is enraged", m.styles.Symbols().Boss, bossName(bossID)), m.styles.Error
+	case game.EventBossDefeated:
+		bossID, _ := data["boss_id"].(string)
+		text := fmt.Sprintf("%s %s defeated", m.styles.Symbols().Boss, bossName(bossID))
+		artifactID, _ := data["artifact_id"].(string)
+		if artifact := game.GetArtifact(artifactID); artifact != nil {
+			text += ", dropped " + artifact.Name
+		}
+		return text, m.styles.Success
+	case game.EventBossFailed:
+		bossID, _ := data["boss_id"].(string)
+		return fmt.Sprintf("%s %s held out, sigil drained", m.styles.Symbols().Boss, bossName(bossID)), m.styles.Warning
+	case game.EventChallengeCompleted:
+		challengeID, _ := data["challenge_id"].(string)
+		elapsedMs, _ := data["elapsed_ms"].(int64)
+		return fmt.Sprintf("%s Challenge %s complete in %s", m.styles.Symbols().Star, challengeID,
+			utils.FormatDuration(time.Duration(elapsedMs)*time.Millisecond)), m.styles.Highlight
+	case game.EventSaveFailed:
+		errText, _ := data["error"].(string)
+		return "Save failed: " + errText, m.styles.Error
+	case game.EventOfflineProgress:
+		floors, _ := data["floors_climbed"].(int)
+		mana, _ := data["mana_earned"].(float64)
+		return fmt.Sprintf("Offline: +%s mana, %d floors", utils.FormatNumber(mana), floors), m.styles.Dim
+	}
+	return event.Message, m.styles.Dim
+}
+
+// spellName returns a spell's display name, falling back to its ID.
+func (m Model) spellName(spellID string) string {
+	if m.gameState != nil {
+		if spell := m.gameState.GetSpellByID(spellID); spell != nil {
+			return spell.Name
+		}
+	}
+	return spellID
+}
+
+// ritualName returns a ritual's display name, falling back to its ID.
+func (m Model) ritualName(ritualID string) string {
+	if m.gameState != nil {
+		for _, ritual := range m.gameState.Rituals {
+			if ritual.ID == ritualID {
+				return ritual.Name
+			}
+		}
+	}
+	return ritualID
+}
+
+// towerSparklineWidth is how many recent samples the tower view plots
+// beside the mana and sigil bars.
+const towerSparklineWidth = 12
+
+// graphWindows are the time spans the Graphs view can show.
+var graphWindows = []time.Duration{5 * time.Minute, 15 * time.Minute, 30 * time.Minute}
+
+// Sample series plotted by the tower sparklines and the Graphs view.
+var (
+	sampleManaPerSec = func(s models.Sample) float64 { return s.ManaPerSec }
+	sampleSigilDPS   = func(s models.Sample) float64 { return s.SigilDPS }
+	sampleFloor      = func(s models.Sample) float64 { return float64(s.Floor) }
+)
+
+// recentSamples returns up to n of the newest graph samples.
+func (m Model) recentSamples(n int) []models.Sample {
+	if m.gameState == nil || m.gameState.Session == nil || m.gameState.Session.Samples == nil {
+		return nil
+	}
+	return m.gameState.Session.Samples.Recent(n)
+}
+
+// sampleSeries extracts one value per sample.
+func sampleSeries(samples []models.Sample, value func(models.Sample) float64) []float64 {
+	series := make([]float64, len(samples))
+	for i, sample := range samples {
+		series[i] = value(sample)
+	}
+	return series
+}
+
+// viewGraphs renders time-series charts of mana/sec, sigil DPS and floor.
+func (m Model) viewGraphs() string {
+	if m.gameState == nil {
+		return "No game loaded"
+	}
+
+	var lines []string
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render(m.styles.Symbols().Stats + " GRAPHS"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	window := graphWindows[m.graphWindow]
+	samples := m.recentSamples(int(window.Seconds()) / game.SampleIntervalSec)
+
+	var spans []string
+	for i, span := range graphWindows {
+		label := fmt.Sprintf(" %s ", utils.FormatDurationShort(span))
+		if i == m.graphWindow {
+			spans = append(spans, m.styles.Selected.Render(label))
+		} else {
+			spans = append(spans, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, spans...))
+	lines = append(lines, "")
+
+	if len(samples) < 2 {
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  Collecting samples (one every %ds)...", game.SampleIntervalSec)))
+		lines = append(lines, "")
+	} else {
+		charts := []struct {
+			title  string
+			value  func(models.Sample) float64
+			format func(float64) string
+		}{
+			{m.styles.Symbols().Mana + " Mana/sec", sampleManaPerSec, utils.FormatNumber},
+			{"⚔️ Sigil DPS", sampleSigilDPS, utils.FormatNumber},
+			{m.styles.Symbols().Floor + " Floor", sampleFloor, func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+		}
+		for _, chart := range charts {
+			series := sampleSeries(samples, chart.value)
+			lo, hi := series[0], series[0]
+			for _, v := range series {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+			lines = append(lines, m.styles.Subtitle.Render(chart.title)+m.styles.Dim.Render(fmt.Sprintf("  now %s  min %s  max %s",
+				chart.format(series[len(series)-1]), chart.format(lo), chart.format(hi))))
+			for _, row := range m.styles.Palette().LineChart(series, 56, 4) {
+				lines = append(lines, "  "+m.styles.Highlight.Render(row))
+			}
+			lines = append(lines, "")
+		}
+		span := time.Duration(samples[len(samples)-1].AtMs-samples[0].AtMs) * time.Millisecond
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  %d samples over %s", len(samples), utils.FormatDuration(span))))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewGraphs)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// statsTabNames are the Stats view tabs.
+var statsTabNames = []string{"Current Era", "Previous Eras", "Lifetime"}
+
 // viewStats renders the stats view.
 func (m Model) viewStats() string {
 	if m.gameState == nil {
@@ -663,59 +1001,333 @@ func (m Model) viewStats() string {
 	}
 
 	gs := m.gameState
+	now := m.now()
+	ledger := gs.EnsureLedger(now)
 	var lines []string
 
-	header := HeaderStyle.Width(60).Render(
-		TitleStyle.Render("📊 STATISTICS"),
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render("📊 STATISTICS"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
 
-	// Tower stats
-	lines = append(lines, SubtitleStyle.Render("Tower Progress"))
-	lines = append(lines, fmt.Sprintf("  Current Floor: %d", gs.Tower.CurrentFloor))
-	lines = append(lines, fmt.Sprintf("  Max Floor Reached: %d", gs.Tower.MaxFloorReached))
-	lines = append(lines, fmt.Sprintf("  Current Mana: %s", utils.FormatNumber(gs.Tower.CurrentMana)))
-	lines = append(lines, fmt.Sprintf("  Lifetime Mana: %s", utils.FormatNumber(gs.Tower.LifetimeManaEarned)))
+	var tabs []string
+	for i, name := range statsTabNames {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if i == m.statsTab {
+			tabs = append(tabs, m.styles.Selected.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
 	lines = append(lines, "")
 
-	// Mana generation
-	if m.engine != nil {
-		lines = append(lines, SubtitleStyle.Render("Mana Generation"))
-		mps := m.engine.CalculateManaPerSecond(gs)
-		lines = append(lines, fmt.Sprintf("  Base Rate: %s/sec", utils.FormatNumber(mps)))
-		lines = append(lines, fmt.Sprintf("  Era Multiplier: %s", utils.FormatMultiplier(gs.PrestigeData.EraMultiplier)))
-		lines = append(lines, fmt.Sprintf("  Permanent Bonus: %s", utils.FormatMultiplier(gs.PrestigeData.PermanentManaGenMultiplier)))
-		ritualBonus := m.engine.GetRitualBonus(gs)
-		lines = append(lines, fmt.Sprintf("  Ritual Bonus: %s", utils.FormatMultiplier(ritualBonus)))
+	switch m.statsTab {
+	case 0:
+		// Tower stats
+		lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("Era %d - %s", ledger.Current.Era, utils.FormatDuration(ledger.Current.Duration(now)))))
+		lines = append(lines, fmt.Sprintf("  Current Floor: %d", gs.Tower.CurrentFloor))
+		lines = append(lines, fmt.Sprintf("  Max Floor Reached: %d", gs.Tower.MaxFloorReached))
+		lines = append(lines, fmt.Sprintf("  Current Mana: %s", utils.FormatNumber(gs.Tower.CurrentMana)))
+		lines = append(lines, fmt.Sprintf("  Spells Unlocked: %d", len(gs.Spells)))
+		lines = append(lines, "")
+
+		// Mana generation
+		if m.engine != nil {
+			lines = append(lines, m.styles.Subtitle.Render("Mana Generation"))
+			mps := m.engine.CalculateManaPerSecond(gs)
+			lines = append(lines, fmt.Sprintf("  Base Rate: %s/sec", utils.FormatNumber(mps)))
+			lines = append(lines, fmt.Sprintf("  Era Multiplier: %s", utils.FormatMultiplier(gs.PrestigeData.EraMultiplier)))
+			lines = append(lines, fmt.Sprintf("  Permanent Bonus: %s", utils.FormatMultiplier(gs.PrestigeData.PermanentManaGenMultiplier)))
+			ritualBonus := m.engine.GetRitualBonus(gs)
+			lines = append(lines, fmt.Sprintf("  Ritual Bonus: %s", utils.FormatMultiplier(ritualBonus)))
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, m.renderEraStats(ledger.Current, now)...)
+
+	case 1:
+		lines = append(lines, m.renderPreviousEras(ledger, now)...)
+
+	case 2:
+		lifetime := ledger.Lifetime()
+		lines = append(lines, m.styles.Subtitle.Render("Tower Progress"))
+		lines = append(lines, fmt.Sprintf("  Max Floor Reached: %d", gs.Tower.MaxFloorReached))
+		lines = append(lines, fmt.Sprintf("  Lifetime Mana: %s", utils.FormatNumber(gs.Tower.LifetimeManaEarned)))
+		if !lifetime.StartedAt.IsZero() {
+			lines = append(lines, fmt.Sprintf("  Tracked Since: %s (%s)", lifetime.StartedAt.Format("2006-01-02"), utils.FormatDuration(now.Sub(lifetime.StartedAt))))
+		}
+		lines = append(lines, "")
+
+		// Prestige stats
+		lines = append(lines, m.styles.Subtitle.Render("Prestige"))
+		lines = append(lines, fmt.Sprintf("  Current Era: %d", gs.PrestigeData.CurrentEra))
+		lines = append(lines, fmt.Sprintf("  Total Ascensions: %d", gs.PrestigeData.TotalAscensions))
+		if fastest := ledger.FastestEra(); fastest != nil {
+			lines = append(lines, fmt.Sprintf("  Fastest Era: Era %d in %s (%.0f floors/hr)",
+				fastest.Era, utils.FormatDuration(fastest.Duration(now)), fastest.FloorsPerHour(now)))
+		}
+		lines = append(lines, fmt.Sprintf("  Cooldown Reduction: %s", utils.FormatPercent(gs.PrestigeData.SpellCooldownReduction)))
+		lines = append(lines, fmt.Sprintf("  Ritual Capacity: %d", gs.PrestigeData.RitualCapacity))
+		lines = append(lines, fmt.Sprintf("  Auto-Cast Slots: %d (base 2 + %d bonus)", gs.GetAutoCastSlotCount(), gs.PrestigeData.AutoCastSlotBonus))
+		lines = append(lines, "")
+
+		// Bosses and the artifacts they dropped
+		lines = append(lines, m.styles.Subtitle.Render("Bosses"))
+		lines = append(lines, fmt.Sprintf("  Bosses Defeated: %d", gs.PrestigeData.BossesDefeated))
+		names := artifactNames(gs)
+		if len(names) == 0 {
+			names = []string{"none yet"}
+		}
+		lines = append(lines, "  Artifacts: "+strings.Join(names, ", "))
 		lines = append(lines, "")
+
+		lines = append(lines, m.renderEraStats(lifetime, now)...)
 	}
 
-	// Prestige stats
-	lines = append(lines, SubtitleStyle.Render("Prestige"))
-	lines = append(lines, fmt.Sprintf("  Current Era: %d", gs.PrestigeData.CurrentEra))
-	lines = append(lines, fmt.Sprintf("  Total Ascensions: %d", gs.PrestigeData.TotalAscensions))
-	lines = append(lines, fmt.Sprintf("  Cooldown Reduction: %s", utils.FormatPercent(gs.PrestigeData.SpellCooldownReduction)))
-	lines = append(lines, fmt.Sprintf("  Ritual Capacity: %d", gs.PrestigeData.RitualCapacity))
-	lines = append(lines, fmt.Sprintf("  Auto-Cast Slots: %d (base 2 + %d bonus)", gs.GetAutoCastSlotCount(), gs.PrestigeData.AutoCastSlotBonus))
+	// Footer
+	lines = append(lines, m.styles.Footer.Render("[1-3] Jump  "+m.keys.Help(ViewStats)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderEraStats renders the ledger sections shared by the era and lifetime tabs.
+func (m Model) renderEraStats(stats *models.EraStats, now time.Time) []string {
+	var lines []string
+
+	lines = append(lines, m.styles.Subtitle.Render("Spells"))
+	lines = append(lines, fmt.Sprintf("  Casts: %d   Crits: %d (%s)", stats.Casts, stats.Crits, utils.FormatPercent(stats.CritRate())))
+	lines = append(lines, fmt.Sprintf("  Total Damage: %s   Highest Cast: %s", utils.FormatNumber(stats.TotalDamage()), utils.FormatNumber(stats.HighestCast)))
+	total := stats.TotalDamage()
+	for _, element := range []models.Element{models.ElementFire, models.ElementIce, models.ElementThunder, models.ElementArcane} {
+		damage := stats.DamageByElement[element]
+		share := 0.0
+		if total > 0 {
+			share = damage / total
+		}
+		icon := m.styles.GetElementStyle(string(element)).Render(m.styles.GetElementIcon(string(element)))
+		lines = append(lines, fmt.Sprintf("  %s %-8s %s %s", icon, m.styles.GetElementLabel(string(element)),
+			m.styles.Palette().ProgressBarColored(20, share, m.styles.Palette().GetElementColor(string(element))), utils.FormatNumber(damage)))
+	}
 	lines = append(lines, "")
 
-	// Spell stats
-	lines = append(lines, SubtitleStyle.Render("Spells"))
-	lines = append(lines, fmt.Sprintf("  Unlocked: %d", len(gs.Spells)))
-	totalCasts := 0
-	for _, s := range gs.Spells {
-		totalCasts += s.CastCount
+	lines = append(lines, m.styles.Subtitle.Render("Mana Spent"))
+	spent := stats.TotalManaSpent()
+	for _, row := range []struct {
+		label  string
+		amount float64
+	}{
+		{"Casts", stats.ManaOnCasts},
+		{"Upgrades", stats.ManaOnUpgrades},
+		{"Floors", stats.ManaOnFloors},
+	} {
+		share := 0.0
+		if spent > 0 {
+			share = row.amount / spent
+		}
+		lines = append(lines, fmt.Sprintf("  %-9s %s %s (%s)", row.label, m.styles.Palette().ProgressBar(20, share), utils.FormatNumber(row.amount), utils.FormatPercent(share)))
 	}
-	lines = append(lines, fmt.Sprintf("  Total Casts: %d", totalCasts))
 	lines = append(lines, "")
 
-	// Footer
-	lines = append(lines, FooterStyle.Render("[B/Esc] Back"))
+	lines = append(lines, m.styles.Subtitle.Render("Climbing"))
+	lines = append(lines, fmt.Sprintf("  Floors Climbed: %d   Highest Floor: %d", stats.FloorsClimbed, stats.HighestFloor))
+	if stats.Era >= 0 {
+		lines = append(lines, fmt.Sprintf("  Floors/hr: %.1f", stats.FloorsPerHour(now)))
+	}
+	gated := stats.SigilGatedMs + stats.ManaGatedMs
+	if gated > 0 {
+		sigilShare := float64(stats.SigilGatedMs) / float64(gated)
+		lines = append(lines, fmt.Sprintf("  Waiting on sigil: %s (%s)   on mana: %s (%s)",
+			utils.FormatDuration(time.Duration(stats.SigilGatedMs)*time.Millisecond), utils.FormatPercent(sigilShare),
+			utils.FormatDuration(time.Duration(stats.ManaGatedMs)*time.Millisecond), utils.FormatPercent(1-sigilShare)))
+	}
+	lines = append(lines, "")
+
+	return lines
+}
+
+// renderPreviousEras renders a table of finished eras, newest first.
+func (m Model) renderPreviousEras(ledger *models.StatsLedger, now time.Time) []string {
+	var lines []string
+	if len(ledger.PreviousEras) == 0 {
+		lines = append(lines, m.styles.Dim.Render("  No finished eras yet. Prestige at floor 100 to start a new era."))
+		lines = append(lines, "")
+		return lines
+	}
+
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("  %-5s %10s %7s %9s %8s %7s %12s", "Era", "Duration", "Floors", "Floors/hr", "Casts", "Crits", "Damage")))
+	fastest := ledger.FastestEra()
+	const visible = 15
+	offset := min(m.statsScroll, max(len(ledger.PreviousEras)-visible, 0))
+	for i := len(ledger.PreviousEras) - 1 - offset; i >= 0 && i >= len(ledger.PreviousEras)-offset-visible; i-- {
+		era := ledger.PreviousEras[i]
+		row := fmt.Sprintf("  %-5d %10s %7d %9.1f %8d %7d %12s", era.Era, utils.FormatDurationShort(era.Duration(now)),
+			era.FloorsClimbed, era.FloorsPerHour(now), era.Casts, era.Crits, utils.FormatNumber(era.TotalDamage()))
+		if era == fastest {
+			lines = append(lines, m.styles.Highlight.Render(row+" "+m.styles.Symbols().Star))
+		} else {
+			lines = append(lines, m.styles.Text.Render(row))
+		}
+	}
+	if len(ledger.PreviousEras) > visible {
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  Showing %d-%d of %d eras", offset+1, min(offset+visible, len(ledger.PreviousEras)), len(ledger.PreviousEras))))
+	}
+	lines = append(lines, "")
+	return lines
+}
+
+// viewAutomation renders the automation rules and loadout presets.
+func (m Model) viewAutomation() string {
+	if m.gameState == nil {
+		return "No game loaded"
+	}
+
+	gs := m.gameState
+	auto := gs.Automation
+	if auto == nil {
+		auto = models.NewAutomationState(m.now().UnixMilli())
+	}
+
+	sym := m.styles.Symbols()
+	var lines []string
+
+	header := m.styles.Header.Width(70).Render(
+		m.styles.Title.Render(sym.AutoCast + " AUTOMATION"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	rate := "measuring..."
+	if fph, ok := auto.FloorsPerHour(m.now().UnixMilli()); ok {
+		rate = fmt.Sprintf("%.0f floors/hr", fph)
+	}
+	lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("Climb rate (last 15m): %s", rate)))
+	lines = append(lines, "")
+
+	check := func(enabled bool) string {
+		if enabled {
+			return m.styles.Success.Render(sym.Check)
+		}
+		return m.styles.Dim.Render(sym.Cross)
+	}
+	row := func(idx int, text string) {
+		if idx == m.selectedIndex {
+			lines = append(lines, m.styles.Selected.Render(sym.Arrow+" "+text))
+		} else {
+			lines = append(lines, m.styles.Text.Render("  "+text))
+		}
+	}
+
+	presetName := auto.ReapplyPreset.Preset
+	if presetName == "" {
+		presetName = "(none)"
+	}
+
+	lines = append(lines, m.styles.Subtitle.Render("Rules:"))
+	row(autoRowPrestige, fmt.Sprintf("%s Auto-prestige at floor %d+", check(auto.Prestige.Enabled), auto.Prestige.MinFloor))
+	row(autoRowPrestigeRate, fmt.Sprintf("    ...when climbing slower than %.0f floors/hr", auto.Prestige.MaxFloorsPerHour))
+	row(autoRowUpgrade, fmt.Sprintf("%s Auto-upgrade cheapest spell when mana > %.0f%% of floor cost", check(auto.Upgrade.Enabled), auto.Upgrade.ManaAbove*100))
+	row(autoRowFloorEvent, fmt.Sprintf("%s Auto-pick floor event: %s", check(auto.FloorEvent.Enabled), models.FloorEventChoiceDisplayNames[auto.FloorEvent.Choice]))
+	row(autoRowPreset, fmt.Sprintf("%s Re-apply loadout after prestige: %s", check(auto.ReapplyPreset.Enabled), presetName))
+	lines = append(lines, "")
+
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("Loadout Presets (%d/%d):", len(gs.Presets), models.MaxLoadoutPresets)))
+	if len(gs.Presets) == 0 {
+		lines = append(lines, m.styles.Dim.Render("  No presets yet. "+m.keys.Label(ViewAutomation, ActionNewPreset)+" saves your auto-cast slots and rotation."))
+	}
+	for i, preset := range gs.Presets {
+		spells := len(preset.AutoCastConfigs)
+		if preset.Rotation != nil && preset.Rotation.Enabled {
+			spells = len(preset.Rotation.Spells)
+		}
+		text := fmt.Sprintf("%s (%d spells)", preset.Name, spells)
+		if auto.ReapplyPreset.Enabled && preset.Name == auto.ReapplyPreset.Preset {
+			text += " " + sym.Prestige
+		}
+		row(autoRuleRows+i, text)
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewAutomation)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// viewAchievements renders achievement progress for the selected category.
+func (m Model) viewAchievements() string {
+	sym := m.styles.Symbols()
+	var lines []string
+
+	header := m.styles.Header.Width(70).Render(
+		m.styles.Title.Render(sym.Star + " ACHIEVEMENTS"),
+	)
+	lines = append(lines, header)
+	lines = append(lines, "")
+
+	if m.achievements == nil {
+		lines = append(lines, m.styles.Dim.Render("Achievements are unavailable for this session."))
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Footer.Render(m.keys.Label(ViewAchievements, ActionBack)+" Back"))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	defs := m.achievements.Definitions()
+	state := m.achievements.State()
+	lines = append(lines, m.styles.Highlight.Render(fmt.Sprintf("Unlocked: %d/%d   Reward: +%.0f%% mana generation",
+		len(state.Unlocked), len(defs), m.achievements.TotalManaGenReward()*100)))
+	lines = append(lines, "")
+
+	// Category tabs
+	var tabs []string
+	for i, category := range models.AchievementCategories {
+		label := fmt.Sprintf(" %d %s ", i+1, models.AchievementCategoryNames[category])
+		if i == m.achievementTab {
+			tabs = append(tabs, m.styles.Selected.Render(label))
+		} else {
+			tabs = append(tabs, m.styles.Dim.Render(label))
+		}
+	}
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	lines = append(lines, "")
+
+	category := models.AchievementCategories[m.achievementTab]
+	for _, def := range defs {
+		if def.Category != category {
+			continue
+		}
+		value, progress := m.achievements.Progress(def)
+		icon, nameStyle := m.styles.Dim.Render(sym.Locked), m.styles.Text
+		if state.IsUnlocked(def.ID) {
+			icon, nameStyle = m.styles.Success.Render(sym.Check), m.styles.Highlight
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", icon, nameStyle.Render(def.Name)))
+
+		detail := def.Description
+		if def.RewardManaGen > 0 {
+			detail += fmt.Sprintf(" (+%.0f%% mana gen)", def.RewardManaGen*100)
+		}
+		lines = append(lines, m.styles.Dim.Render("   "+detail))
+		lines = append(lines, "   "+m.styles.Palette().ProgressBarWithLabel(30, progress, achievementProgressLabel(def, value)))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, m.styles.Footer.Render("[1-5] Jump  "+m.keys.Help(ViewAchievements)))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// achievementProgressLabel formats an achievement's stat against its target.
+func achievementProgressLabel(def *models.AchievementDefinition, value float64) string {
+	if def.LowerIsBetter {
+		best := "no record"
+		if value > 0 {
+			best = "best " + utils.FormatDuration(time.Duration(value)*time.Millisecond)
+		}
+		return fmt.Sprintf("%s / %s", best, utils.FormatDuration(time.Duration(def.Target)*time.Millisecond))
+	}
+	return fmt.Sprintf("%s / %s", utils.FormatNumber(math.Min(value, def.Target)), utils.FormatNumber(def.Target))
+}
+
 // viewPrestige renders the prestige view.
 func (m Model) viewPrestige() string {
 	if m.gameState == nil {
@@ -725,8 +1337,8 @@ func (m Model) viewPrestige() string {
 	gs := m.gameState
 	var lines []string
 
-	header := HeaderStyle.Width(60).Render(
-		TitleStyle.Render("✨ PRESTIGE - ASCENSION"),
+	header := m.styles.Header.Width(60).Render(
+		m.styles.Title.Render("✨ PRESTIGE - ASCENSION"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
@@ -734,14 +1346,14 @@ func (m Model) viewPrestige() string {
 	canPrestige := m.engine != nil && m.engine.CanPrestige(gs)
 
 	if canPrestige {
-		lines = append(lines, SuccessStyle.Render("You have reached Floor 100!"))
+		lines = append(lines, m.styles.Success.Render("You have reached Floor 100!"))
 		lines = append(lines, "")
-		lines = append(lines, TextStyle.Render("Ascending will:"))
-		lines = append(lines, TextStyle.Render("  • Reset your floor to 1"))
-		lines = append(lines, TextStyle.Render("  • Reset your current mana"))
-		lines = append(lines, TextStyle.Render("  • Remove all rituals"))
+		lines = append(lines, m.styles.Text.Render("Ascending will:"))
+		lines = append(lines, m.styles.Text.Render("  • Reset your floor to 1"))
+		lines = append(lines, m.styles.Text.Render("  • Reset your current mana"))
+		lines = append(lines, m.styles.Text.Render("  • Remove all rituals"))
 		lines = append(lines, "")
-		lines = append(lines, HighlightStyle.Render("But you will gain:"))
+		lines = append(lines, m.styles.Highlight.Render("But you will gain:"))
 
 		newEra := gs.PrestigeData.CurrentEra + 1
 		newMultiplier := 1.0 + (0.15 * float64(newEra))
@@ -755,14 +1367,14 @@ func (m Model) viewPrestige() string {
 			lines = append(lines, "  • +1 auto-cast slot")
 		}
 		lines = append(lines, "")
-		lines = append(lines, WarningStyle.Render("Press [Enter] to ascend"))
+		lines = append(lines, m.styles.Warning.Render("Press "+m.keys.Label(ViewPrestige, ActionAscend)+" to ascend"))
 	} else {
-		lines = append(lines, ErrorStyle.Render(fmt.Sprintf("Reach Floor 100 to prestige (currently floor %d)", gs.Tower.CurrentFloor)))
+		lines = append(lines, m.styles.Error.Render(fmt.Sprintf("Reach Floor 100 to prestige (currently floor %d)", gs.Tower.CurrentFloor)))
 	}
 	lines = append(lines, "")
 
 	// Footer
-	lines = append(lines, FooterStyle.Render("[B/Esc] Back"))
+	lines = append(lines, m.styles.Footer.Render(m.keys.Label(ViewPrestige, ActionBack)+" Back"))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -778,11 +1390,11 @@ func (m Model) viewSpecialize() string {
 		return "Spell not found"
 	}
 
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	lines := []string{}
-	lines = append(lines, TitleStyle.Render(sym.Star+" Spell Specialization"))
+	lines = append(lines, m.styles.Title.Render(sym.Star+" Spell Specialization"))
 	lines = append(lines, "")
-	lines = append(lines, SubtitleStyle.Render(fmt.Sprintf("Choose a Tier %d specialization for %s (Lv%d)", m.specTier, spell.Name, spell.Level)))
+	lines = append(lines, m.styles.Subtitle.Render(fmt.Sprintf("Choose a Tier %d specialization for %s (Lv%d)", m.specTier, spell.Name, spell.Level)))
 	lines = append(lines, "")
 
 	type specOption struct {
@@ -805,18 +1417,18 @@ func (m Model) viewSpecialize() string {
 	}
 
 	for i, opt := range options {
-		style := lipgloss.NewStyle().Padding(0, 2)
+		style := m.styles.NewStyle().Padding(0, 2)
 		if i == m.specChoiceIdx {
-			style = style.Bold(true).Foreground(ColorAccent).
-				Border(lipgloss.RoundedBorder()).BorderForeground(ColorAccent)
+			style = style.Bold(true).Foreground(m.styles.ColorAccent).
+				Border(lipgloss.RoundedBorder()).BorderForeground(m.styles.ColorAccent)
 		}
-		optText := fmt.Sprintf("%s\n%s", opt.name, lipgloss.NewStyle().Foreground(ColorTextDim).Render(opt.desc))
+		optText := fmt.Sprintf("%s\n%s", opt.name, m.styles.NewStyle().Foreground(m.styles.ColorTextDim).Render(opt.desc))
 		lines = append(lines, style.Render(optText))
 		lines = append(lines, "")
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, FooterStyle.Render("[↑/↓] Select  [Enter] Confirm  [Esc] Cancel"))
+	lines = append(lines, m.styles.Footer.Render(m.keys.Help(ViewSpecialize)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
@@ -830,12 +1442,12 @@ func (m Model) viewRotation() string {
 	m.gameState.EnsureRotation()
 	rotation := m.gameState.Session.Rotation
 
-	sym := GetSymbols()
+	sym := m.styles.Symbols()
 	var lines []string
 
 	// Header
-	header := HeaderStyle.Width(80).Render(
-		TitleStyle.Render(sym.Bullet + " ADVANCED SPELL ROTATION"),
+	header := m.styles.Header.Width(80).Render(
+		m.styles.Title.Render(sym.Bullet + " ADVANCED SPELL ROTATION"),
 	)
 	lines = append(lines, header)
 	lines = append(lines, "")
@@ -845,20 +1457,38 @@ func (m Model) viewRotation() string {
 	if rotation.Enabled {
 		statusStr = "🟢 ENABLED"
 	}
-	lines = append(lines, HighlightStyle.Render(fmt.Sprintf("Rotation System: %s", statusStr)))
+	lines = append(lines, m.styles.Highlight.Render(fmt.Sprintf("Rotation System: %s", statusStr)))
 	lines = append(lines, "")
 
 	// Settings
-	lines = append(lines, SubtitleStyle.Render("⚙️  Rotation Settings:"))
-	lines = append(lines, fmt.Sprintf("  Cooldown Weaving: %v (casts spells with shortest CD first)", rotation.CooldownWeaving))
-	lines = append(lines, fmt.Sprintf("  Mana Reserve: %.0f%% (reserves mana, never drops below this)", rotation.ManaThreshold*100))
-	lines = append(lines, fmt.Sprintf("  Optimize for Idle: %v (spreads casts for sustained DPS)", rotation.OptimizeForIdle))
+	lines = append(lines, m.styles.Subtitle.Render("⚙️  Rotation Settings:"))
+	lines = append(lines, fmt.Sprintf("  %s Cooldown Weaving: %v (casts spells with shortest CD first)", m.keys.Label(ViewRotation, ActionWeaving), rotation.CooldownWeaving))
+	lines = append(lines, fmt.Sprintf("  %s Mana Reserve: %.0f%% (reserves mana, never drops below this)", m.keys.Label(ViewRotation, ActionReserveDown, ActionReserveUp), rotation.ManaThreshold*100))
+	lines = append(lines, fmt.Sprintf("  %s Optimize for Idle: %v (spreads casts for sustained DPS)", m.keys.Label(ViewRotation, ActionIdle), rotation.OptimizeForIdle))
 	lines = append(lines, "")
 
+	// Live preview of the next cast
+	if m.engine != nil {
+		preview := m.engine.PreviewNextRotationCast(m.gameState)
+		nextStr := m.styles.Dim.Render(preview.Reason)
+		if preview.SpellID != "" {
+			if spell := m.gameState.GetSpellByID(preview.SpellID); spell != nil {
+				when := "ready now"
+				if preview.WaitMs > 0 {
+					when = "in " + utils.FormatCooldown(preview.WaitMs)
+				}
+				nextStr = m.styles.GetElementStyle(string(spell.Element)).Render(m.styles.GetElementIcon(string(spell.Element))+" "+spell.Name) +
+					m.styles.Dim.Render(" ("+when+")")
+			}
+		}
+		lines = append(lines, m.styles.Highlight.Render("⏭️  Next Cast: ")+nextStr)
+		lines = append(lines, "")
+	}
+
 	// Spell list
-	lines = append(lines, SubtitleStyle.Render("📋 Configured Spells:"))
+	lines = append(lines, m.styles.Subtitle.Render("📋 Configured Spells:"))
 	if len(rotation.Spells) == 0 {
-		lines = append(lines, DimStyle.Render("  No spells configured. Press [V] to convert auto-cast slots."))
+		lines = append(lines, m.styles.Dim.Render("  No spells configured. Press "+m.keys.Label(ViewRotation, ActionConvert)+" to convert auto-cast slots."))
 	} else {
 		for i, config := range rotation.Spells {
 			spell := m.gameState.GetSpellByID(config.SpellID)
@@ -866,7 +1496,7 @@ func (m Model) viewRotation() string {
 				continue
 			}
 
-			icon := GetElementIcon(string(spell.Element))
+			icon := m.styles.GetElementIcon(string(spell.Element))
 			priorityLabel := models.GetPriorityLabel(config.Priority)
 			condDesc := models.GetConditionDescription(config.Condition)
 
@@ -875,31 +1505,210 @@ func (m Model) viewRotation() string {
 				statusIcon = "❌"
 			}
 
-			style := TextStyle
+			style := m.styles.Text
+			prefix := "  "
 			if i == m.selectedIndex {
-				style = SelectedStyle
+				style = m.styles.Selected
+				prefix = sym.Arrow + " "
+			}
+
+			var exprErr error
+			if config.Condition == models.RotationConditionExpression {
+				condDesc = "when " + config.Expression
+				exprErr = engine.ValidateConditionExpr(config.Expression)
 			}
 
-			spellLine := fmt.Sprintf("%s %s %s [%s] - %s",
-				statusIcon, icon, spell.Name, priorityLabel, condDesc)
+			spellLine := fmt.Sprintf("%s%d. %s %s %s [%s] - %s",
+				prefix, i+1, statusIcon, icon, spell.Name, priorityLabel, condDesc)
 			lines = append(lines, style.Render(spellLine))
+			if exprErr != nil {
+				lines = append(lines, m.styles.Error.Render("     "+sym.Cross+" "+exprErr.Error()+" (never casts)"))
+			}
+		}
+	}
+
+	if m.optimizing {
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Highlight.Render("⏳ Optimizing rotation..."))
+	} else if result := m.optimizeResult; result != nil {
+		label := engine.OptimizeObjectiveLabels[result.Objective]
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Subtitle.Render("🧠 Optimizer Result:"))
+		lines = append(lines, m.styles.Text.Render(fmt.Sprintf("  %s: %s → %s (%+.1f%%) %s %d simulations",
+			label, utils.FormatNumber(result.CurrentScore), utils.FormatNumber(result.OptimizedScore),
+			result.Improvement()*100, sym.Bullet, result.Evaluations)))
+		if m.rotationBeforeOptimize != nil {
+			lines = append(lines, m.styles.Dim.Render("  Applied. Press "+m.keys.Label(ViewRotation, ActionUndo)+" to restore the previous rotation."))
+		}
+	}
+
+	if m.rotationPlan != nil && !m.editingExpr && !m.addingRotationSpell {
+		lines = append(lines, "")
+		lines = append(lines, m.renderRotationTimeline()...)
+	}
+
+	if m.editingExpr {
+		lines = append(lines, "")
+		lines = append(lines, m.renderExprEditor()...)
+	}
+
+	if m.addingRotationSpell {
+		lines = append(lines, "")
+		lines = append(lines, m.styles.Subtitle.Render("➕ Add Spell to Rotation:"))
+		for i, spell := range m.rotationCandidates() {
+			style := m.styles.Text
+			prefix := "  "
+			if i == m.rotationPickIdx {
+				style = m.styles.Selected
+				prefix = sym.Arrow + " "
+			}
+			lines = append(lines, style.Render(fmt.Sprintf("%s%s %s (Lv.%d)",
+				prefix, m.styles.GetElementIcon(string(spell.Element)), spell.Name, spell.Level)))
 		}
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, DimStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	lines = append(lines, m.styles.Dim.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
 	lines = append(lines, "")
 
 	// Controls
-	controls := []string{
-		"[O] Toggle Rotation On/Off",
-		"[V] Convert Auto-Cast Slots",
-		"[W] Toggle Cooldown Weaving",
-		"[Space] Toggle Selected Spell",
-		"[↑/↓] Navigate",
-		"[B/Esc] Back",
+	controls := []string{m.keys.Help(ViewRotation)}
+	if m.addingRotationSpell {
+		controls = []string{m.keys.Help(keyScopeRotationPicker)}
 	}
-	lines = append(lines, FooterStyle.Render(lipgloss.JoinVertical(lipgloss.Left, controls...)))
+	if m.editingExpr {
+		controls = []string{
+			"[Enter] Save Expression (empty = always)",
+			"[Ctrl+U] Clear",
+			"[Esc] Cancel",
+		}
+	}
+	lines = append(lines, m.styles.Footer.Render(lipgloss.JoinVertical(lipgloss.Left, controls...)))
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
+
+// renderRotationTimeline renders the rotation dry-run as a one-cell-per-second strip.
+func (m Model) renderRotationTimeline() []string {
+	sym := m.styles.Symbols()
+	plan := m.rotationPlan
+	seconds := int(plan.HorizonMs / 1000)
+	if seconds <= 0 || len(plan.Samples) == 0 {
+		return nil
+	}
+
+	// Casts: first cast in each second, lettered and colored by element
+	cells := make([]string, seconds)
+	for i := range cells {
+		cells[i] = m.styles.Dim.Render("·")
+	}
+	filled := make([]bool, seconds)
+	for _, cast := range plan.Casts {
+		sec := int(cast.AtMs / 1000)
+		if sec >= seconds || filled[sec] {
+			continue
+		}
+		filled[sec] = true
+		letter := string([]rune(m.styles.GetElementLabel(string(cast.Element)))[:1])
+		cells[sec] = m.styles.GetElementStyle(string(cast.Element)).Render(letter)
+	}
+
+	var mana, sigil []float64
+	for _, sample := range plan.Samples[1:] {
+		mana = append(mana, sample.ManaPct)
+		sigil = append(sigil, sample.SigilPct)
+	}
+
+	axis := fmt.Sprintf("%-*s%*s", seconds/2, "now", seconds-seconds/2, fmt.Sprintf("+%ds", seconds))
+	lines := []string{
+		m.styles.Subtitle.Render(fmt.Sprintf("📈 Next %ds (dry-run):", seconds)),
+		"  Casts │" + strings.Join(cells, "") + "│",
+		"  Mana  │" + m.styles.Arcane.Render(m.renderSparkline(mana)) + "│",
+		"  Sigil │" + m.styles.Highlight.Render(m.renderSparkline(sigil)) + "│",
+		m.styles.Dim.Render("         " + axis),
+	}
+
+	summary := fmt.Sprintf("  %d casts %s Sigil +%s (%s/s) %s Mana spent %s",
+		len(plan.Casts), sym.Bullet, utils.FormatNumber(plan.SigilGained), utils.FormatNumber(plan.SigilPerSecond()),
+		sym.Bullet, utils.FormatNumber(plan.ManaSpent))
+	if plan.FloorsClimbed > 0 {
+		summary += fmt.Sprintf(" %s +%d floors", sym.Bullet, plan.FloorsClimbed)
+	}
+	if base := m.rotationPlanBaseline; base != nil {
+		delta := plan.SigilPerSecond() - base.SigilPerSecond()
+		deltaStyle := m.styles.Dim
+		if delta > 0.005 {
+			deltaStyle = m.styles.Success
+		} else if delta < -0.005 {
+			deltaStyle = m.styles.Error
+		}
+		summary += deltaStyle.Render(fmt.Sprintf(" (%+.2f sigil/s vs before last change)", delta))
+	}
+	lines = append(lines, m.styles.Text.Render(summary))
+
+	// Top reasons ready spells were held back
+	for i, blocked := range plan.Blocked {
+		if i >= 3 {
+			break
+		}
+		name := blocked.SpellID
+		if spell := m.gameState.GetSpellByID(blocked.SpellID); spell != nil {
+			name = spell.Name
+		}
+		lines = append(lines, m.styles.Dim.Render(fmt.Sprintf("  %s %s blocked by %s for %s (first at +%s)",
+			sym.Cross, name, blocked.Reason, utils.FormatCooldown(blocked.BlockedMs), utils.FormatCooldown(max(blocked.FirstAtMs, 1)))))
+	}
+	return lines
+}
+
+// renderSparkline draws values in [0, 1] as a row of block characters.
+func (m Model) renderSparkline(values []float64) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	if !m.styles.UsingEmoji() {
+		levels = []rune("_.-:=+*#")
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := int(utils.Clamp(v, 0, 1) * float64(len(levels)-1))
+		b.WriteRune(levels[idx])
+	}
+	return b.String()
+}
+
+// renderExprEditor renders the condition expression input with validation feedback.
+func (m Model) renderExprEditor() []string {
+	sym := m.styles.Symbols()
+	prompt := "  > "
+	lines := []string{
+		m.styles.Subtitle.Render("✏️  Condition Expression:"),
+		m.styles.Highlight.Render(prompt + m.exprInput + "_"),
+	}
+
+	var exprErr *engine.ConditionExprError
+	switch {
+	case errors.As(m.exprError, &exprErr):
+		// Pos counts bytes; the caret goes under the same display column
+		before := m.exprInput[:min(max(exprErr.Pos-1, 0), len(m.exprInput))]
+		caret := strings.Repeat(" ", lipgloss.Width(prompt+before)) + "^"
+		lines = append(lines, m.styles.Error.Render(caret))
+		lines = append(lines, m.styles.Error.Render("  "+sym.Cross+" "+exprErr.Msg))
+	case m.exprError != nil:
+		lines = append(lines, m.styles.Error.Render("  "+sym.Cross+" "+m.exprError.Error()))
+	case strings.TrimSpace(m.exprInput) != "":
+		lines = append(lines, m.styles.Success.Render("  "+sym.Check+" Valid expression"))
+	}
+
+	lines = append(lines, "")
+	var vars []string
+	for _, v := range engine.ConditionVariables {
+		vars = append(vars, v.Name)
+	}
+	var funcs []string
+	for _, f := range engine.ConditionFunctions {
+		funcs = append(funcs, f.Name)
+	}
+	lines = append(lines, m.styles.Dim.Render("  Variables: "+strings.Join(vars, ", ")))
+	lines = append(lines, m.styles.Dim.Render("  Functions: "+strings.Join(funcs, ", ")))
+	lines = append(lines, m.styles.Dim.Render("  Operators: && || ! < <= > >= == != + - * /   Units: 500ms 2s 1m 60%"))
+	return lines
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -33,10 +36,13 @@ func (l LogLevel) String() string {
 	}
 }
 
-// Logger provides simple logging functionality.
+// Logger writes leveled messages to the console and, optionally, as
+// structured key=value lines to a log file. It is safe for concurrent use.
 type Logger struct {
+	mu     sync.Mutex
 	level  LogLevel
-	output io.Writer
+	output io.Writer // Console, human-readable (may be io.Discard)
+	file   io.Writer // Structured log file (nil = none)
 }
 
 // DefaultLogger is the global logger instance.
@@ -52,22 +58,85 @@ func NewLogger(level LogLevel) *Logger {
 
 // SetLevel changes the logging level.
 func (l *Logger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.level = level
 }
 
-// SetOutput changes the logging output destination.
+// Level returns the logging level.
+func (l *Logger) Level() LogLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetOutput changes the console output destination. Use io.Discard to keep
+// log lines off the screen while the TUI runs.
 func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.output = w
 }
 
-// log writes a log message if the level is high enough.
+// SetFile sets the structured log file (nil to stop file logging).
+func (l *Logger) SetFile(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file = w
+}
+
+// log writes a printf-style message if the level is high enough.
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	l.Log(level, fmt.Sprintf(format, args...))
+}
+
+// Log writes msg with alternating key/value pairs if the level is high
+// enough, e.g. Log(LogWarn, "save failed", "slot", 0, "err", err).
+func (l *Logger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if level < l.level {
 		return
 	}
-	timestamp := time.Now().Format("15:04:05")
-	message := fmt.Sprintf(format, args...)
-	fmt.Fprintf(l.output, "[%s] [%s] %s\n", timestamp, level.String(), message)
+	now := time.Now()
+	fields := formatFields(keyvals)
+	if l.output != nil {
+		line := fmt.Sprintf("[%s] [%s] %s", now.Format("15:04:05"), level.String(), msg)
+		if fields != "" {
+			line += " " + fields
+		}
+		fmt.Fprintln(l.output, line)
+	}
+	if l.file != nil {
+		line := fmt.Sprintf("time=%s level=%s msg=%s", now.Format(time.RFC3339Nano), strings.ToLower(level.String()), quoteValue(msg))
+		if fields != "" {
+			line += " " + fields
+		}
+		fmt.Fprintln(l.file, line)
+	}
+}
+
+// formatFields renders key/value pairs as key=value, quoting values with
+// spaces. A trailing key without a value is logged with an empty value.
+func formatFields(keyvals []interface{}) string {
+	var parts []string
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		value := ""
+		if i+1 < len(keyvals) {
+			value = fmt.Sprint(keyvals[i+1])
+		}
+		parts = append(parts, key+"="+quoteValue(value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteValue quotes a value that is empty or contains spaces, quotes or '='.
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return strconv.Quote(value)
+	}
+	return value
 }
 
 // Debug logs a debug message.
@@ -112,6 +181,21 @@ func Error(format string, args ...interface{}) {
 	DefaultLogger.Error(format, args...)
 }
 
+// Log writes a structured message using the default logger.
+func Log(level LogLevel, msg string, keyvals ...interface{}) {
+	DefaultLogger.Log(level, msg, keyvals...)
+}
+
+// SetLogOutput sets the console output of the default logger.
+func SetLogOutput(w io.Writer) {
+	DefaultLogger.SetOutput(w)
+}
+
+// SetLogFile sets the structured log file of the default logger.
+func SetLogFile(w io.Writer) {
+	DefaultLogger.SetFile(w)
+}
+
 // SetLogLevel sets the level for the default logger.
 func SetLogLevel(level LogLevel) {
 	DefaultLogger.SetLevel(level)